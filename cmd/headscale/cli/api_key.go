@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
@@ -11,6 +12,7 @@ import (
 	"github.com/pterm/pterm"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -21,10 +23,15 @@ const (
 
 func init() {
 	rootCmd.AddCommand(apiKeysCmd)
+	addListFlags(listAPIKeys, "keys")
 	apiKeysCmd.AddCommand(listAPIKeys)
 
 	createAPIKeyCmd.Flags().
 		StringP("expiration", "e", DefaultAPIKeyExpiry, "Human-readable expiration of the key (e.g. 30m, 24h)")
+	createAPIKeyCmd.Flags().
+		StringSlice("scope", []string{}, "Roles (admin, read-only, preauthkey-issuer, route-approver) or API calls (e.g. ListNodes) the key is limited to, full access if empty")
+	createAPIKeyCmd.Flags().
+		StringSliceP("user", "u", []string{}, "Users the key is limited to, all users if empty")
 
 	apiKeysCmd.AddCommand(createAPIKeyCmd)
 
@@ -53,7 +60,13 @@ var listAPIKeys = &cobra.Command{
 		defer cancel()
 		defer conn.Close()
 
-		request := &v1.ListApiKeysRequest{}
+		list := getListFlags(cmd)
+
+		request := &v1.ListApiKeysRequest{
+			PageSize:  list.pageSize,
+			PageToken: list.pageToken,
+			ReadMask:  list.readMask,
+		}
 
 		response, err := client.ListApiKeys(ctx, request)
 		if err != nil {
@@ -67,13 +80,17 @@ var listAPIKeys = &cobra.Command{
 		}
 
 		if output != "" {
-			SuccessOutput(response.GetApiKeys(), "", output)
+			if list.paged() {
+				SuccessOutput(response, "", output)
+			} else {
+				SuccessOutput(response.GetApiKeys(), "", output)
+			}
 
 			return
 		}
 
 		tableData := pterm.TableData{
-			{"ID", "Prefix", "Expiration", "Created"},
+			{"ID", "Prefix", "Expiration", "Created", "Scopes", "Users"},
 		}
 		for _, key := range response.GetApiKeys() {
 			expiration := "-"
@@ -87,6 +104,8 @@ var listAPIKeys = &cobra.Command{
 				key.GetPrefix(),
 				expiration,
 				key.GetCreatedAt().AsTime().Format(HeadscaleDateTimeFormat),
+				apiKeyListString(key.GetScopes(), "full access"),
+				apiKeyListString(key.GetUsers(), "all"),
 			})
 
 		}
@@ -100,6 +119,8 @@ var listAPIKeys = &cobra.Command{
 
 			return
 		}
+
+		printNextPage(response, len(response.GetApiKeys()), "keys")
 	},
 }
 
@@ -140,6 +161,9 @@ If you loose a key, create a new one and revoke (expire) the old one.`,
 
 		request.Expiration = timestamppb.New(expiration)
 
+		request.Scopes, _ = cmd.Flags().GetStringSlice("scope")
+		request.Users, _ = cmd.Flags().GetStringSlice("user")
+
 		ctx, client, conn, cancel := getHeadscaleCLIClient()
 		defer cancel()
 		defer conn.Close()
@@ -148,7 +172,7 @@ If you loose a key, create a new one and revoke (expire) the old one.`,
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Cannot create Api Key: %s\n", err),
+				fmt.Sprintf("Cannot create Api Key: %s\n", status.Convert(err).Message()),
 				output,
 			)
 
@@ -199,3 +223,11 @@ var expireAPIKeyCmd = &cobra.Command{
 		SuccessOutput(response, "Key expired", output)
 	},
 }
+
+func apiKeyListString(values []string, empty string) string {
+	if len(values) == 0 {
+		return empty
+	}
+
+	return strings.Join(values, ", ")
+}
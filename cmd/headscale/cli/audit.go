@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultAuditEventLimit = 100
+)
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	listAuditEventsCmd.Flags().String("actor", "", "Only show events made by this actor (e.g. api_key:<prefix>, unix_socket)")
+	listAuditEventsCmd.Flags().String("action", "", "Only show events of this action (e.g. route.enable)")
+	listAuditEventsCmd.Flags().String("target", "", "Only show events made to this target (e.g. node:1, route:3)")
+	listAuditEventsCmd.Flags().String("since", "", "Only show events newer than this duration (e.g. 30m, 24h, 7d)")
+	listAuditEventsCmd.Flags().Uint32P("limit", "l", DefaultAuditEventLimit, "Maximum number of events to show, 0 for all")
+	auditCmd.AddCommand(listAuditEventsCmd)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit log of administrative changes",
+}
+
+var listAuditEventsCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List audit events, newest first",
+	Aliases: []string{"ls", "show"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		actor, _ := cmd.Flags().GetString("actor")
+		action, _ := cmd.Flags().GetString("action")
+		target, _ := cmd.Flags().GetString("target")
+		since, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetUint32("limit")
+
+		request := &v1.ListAuditEventsRequest{
+			Actor:  actor,
+			Action: action,
+			Target: target,
+			Limit:  limit,
+		}
+
+		if since != "" {
+			duration, err := model.ParseDuration(since)
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Could not parse duration: %s\n", err),
+					output,
+				)
+
+				return
+			}
+
+			request.Since = timestamppb.New(time.Now().UTC().Add(-time.Duration(duration)))
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListAuditEvents(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error getting the audit events: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.GetEvents(), "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"ID", "Time", "Actor", "Action", "Target"},
+		}
+		for _, event := range response.GetEvents() {
+			tableData = append(tableData, []string{
+				fmt.Sprintf("%d", event.GetId()),
+				event.GetCreatedAt().AsTime().Format(HeadscaleDateTimeFormat),
+				event.GetActor(),
+				event.GetAction(),
+				event.GetTarget(),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
@@ -0,0 +1,582 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol"
+	"github.com/juanfont/headscale/hscontrol/db"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"github.com/pterm/pterm"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errInvalidDSN              = errors.New("invalid DSN, it must have a host, dbname and user")
+	errUnsupportedDatabaseType = errors.New("unsupported database type")
+	errNoConfigFile            = errors.New("no configuration file is used")
+	errConfigNotYAML           = errors.New("configuration file is not a YAML file")
+)
+
+// sendChunkSize is the size of the chunks a backup or an export is sent
+// in.
+const sendChunkSize = 1 << 20
+
+func init() {
+	rootCmd.AddCommand(databaseCmd)
+	databaseCmd.AddCommand(backupDatabaseCmd)
+	databaseCmd.AddCommand(restoreDatabaseCmd)
+
+	migrateDatabaseCmd.Flags().
+		String("to", db.Postgres, "Type of the database to migrate to (postgres, sqlite3)")
+	migrateDatabaseCmd.Flags().
+		String("dsn", "", "Connection string of the database to migrate to, or its path for sqlite3")
+	err := migrateDatabaseCmd.MarkFlagRequired("dsn")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	migrateDatabaseCmd.Flags().
+		Bool("update-config", true, "Point the configuration file to the new database")
+	databaseCmd.AddCommand(migrateDatabaseCmd)
+}
+
+var databaseCmd = &cobra.Command{
+	Use:     "db",
+	Short:   "Back up, restore and migrate the database of Headscale",
+	Aliases: []string{"database"},
+}
+
+// databaseContext returns a context for backups, restores, exports and
+// imports, which can take longer than the CLI timeout, cancelled when
+// interrupted.
+func databaseContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+var backupDatabaseCmd = &cobra.Command{
+	Use:   "backup FILE",
+	Short: "Write a consistent backup of the database to FILE while Headscale is running",
+	Long: "Write a consistent backup of the database to FILE while Headscale is running.\n" +
+		"SQLite databases are backed up as a database file, PostgreSQL databases as a logical " +
+		"export that can only be restored into PostgreSQL.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path := args[0]
+
+		_, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		ctx, stop := databaseContext()
+		defer stop()
+
+		stream, err := client.BackupDatabase(ctx, &v1.BackupDatabaseRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error backing up database: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		err = receiveToFile(path, func() ([]byte, error) {
+			chunk, err := stream.Recv()
+
+			return chunk.GetData(), err
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error backing up database: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			map[string]string{"Result": "Database backed up", "File": path},
+			fmt.Sprintf("Database backed up to %s", path),
+			output,
+		)
+	},
+}
+
+var restoreDatabaseCmd = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Replace the database with the backup in FILE",
+	Long: "Replace the database with the backup in FILE, made with `headscale db backup`.\n" +
+		"Backups of SQLite databases made by an older version of Headscale are migrated, " +
+		"backups of PostgreSQL databases must be made by the same version.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path := args[0]
+
+		file, err := os.Open(path)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error opening backup file: %s", err), output)
+
+			return
+		}
+		defer file.Close()
+
+		confirm := false
+		force, _ := cmd.Flags().GetBool("force")
+		if !force {
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf(
+					"Do you want to replace the whole database with the backup %s?",
+					path,
+				),
+			}
+			err = survey.AskOne(prompt, &confirm)
+			if err != nil {
+				return
+			}
+		}
+
+		if !confirm && !force {
+			SuccessOutput(map[string]string{"Result": "Database not restored"}, "Database not restored", output)
+
+			return
+		}
+
+		_, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		ctx, stop := databaseContext()
+		defer stop()
+
+		stream, err := client.RestoreDatabase(ctx)
+		if err == nil {
+			err = sendChunks(file, func(data []byte) error {
+				return stream.Send(&v1.RestoreDatabaseRequest{Data: data})
+			})
+		}
+		if err == nil {
+			_, err = stream.CloseAndRecv()
+		}
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error restoring database: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			map[string]string{"Result": "Database restored"},
+			"Database restored",
+			output,
+		)
+	},
+}
+
+var migrateDatabaseCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy the database to another database, like from SQLite to PostgreSQL",
+	Long: "Copy every row of the configured database to the empty database given by --to and --dsn, " +
+		"keeping their IDs, then point the configuration file to it.\n" +
+		"Headscale can keep running meanwhile, changes made after the copy started are not copied. " +
+		"Restart Headscale to use the new database.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		dbType, _ := cmd.Flags().GetString("to")
+		dsn, _ := cmd.Flags().GetString("dsn")
+		updateConfig, _ := cmd.Flags().GetBool("update-config")
+
+		cfg, err := types.GetHeadscaleConfig()
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error loading configuration: %s", err), output)
+
+			return
+		}
+
+		srcConnectionAddr, err := hscontrol.DatabaseConnectionString(cfg)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error reading database configuration: %s", err), output)
+
+			return
+		}
+
+		settings, err := databaseSettingsFromDSN(dbType, dsn)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error parsing --dsn: %s", err), output)
+
+			return
+		}
+
+		dstConnectionAddr := dsn
+		if dbType == db.Sqlite {
+			// The path is made absolute like db_path.
+			dstConnectionAddr, _ = filepath.Abs(dsn)
+		}
+
+		copied, err := db.CopyDatabase(cfg.DBtype, srcConnectionAddr, dbType, dstConnectionAddr)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error migrating database: %s", err), output)
+
+			return
+		}
+
+		configPath := viper.ConfigFileUsed()
+		if updateConfig {
+			if err := updateConfigFile(configPath, settings); err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf(
+						"Database migrated, but the configuration could not be updated, set %s yourself: %s",
+						settingKeys(settings),
+						err,
+					),
+					output,
+				)
+
+				return
+			}
+		}
+
+		if output == "" {
+			tableData := pterm.TableData{
+				{"Table", "Rows"},
+			}
+			for _, table := range copied {
+				tableData = append(tableData, []string{
+					table.Table,
+					strconv.FormatInt(table.Rows, util.Base10),
+				})
+			}
+			err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Failed to render pterm table: %s", err),
+					output,
+				)
+
+				return
+			}
+		}
+
+		message := fmt.Sprintf("Database migrated, set %s and restart Headscale to switch to it", settingKeys(settings))
+		if updateConfig {
+			message = fmt.Sprintf("Database migrated, %s now uses it, restart Headscale to switch to it", configPath)
+		}
+
+		SuccessOutput(copied, message, output)
+	},
+}
+
+// configSetting is a top level setting of the configuration file, with
+// the YAML type of its value.
+type configSetting struct {
+	key   string
+	value string
+	tag   string
+}
+
+// databaseSettingsFromDSN returns the settings of the configuration file
+// connecting to the database of type dbType at dsn. PostgreSQL DSNs can
+// be URLs or key=value pairs, SQLite DSNs are paths.
+func databaseSettingsFromDSN(dbType, dsn string) ([]configSetting, error) {
+	switch dbType {
+	case db.Sqlite:
+		path, err := filepath.Abs(dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		return []configSetting{
+			{"db_type", db.Sqlite, "!!str"},
+			{"db_path", path, "!!str"},
+		}, nil
+
+	case db.Postgres:
+		params, err := parsePostgresDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		if params["host"] == "" || params["dbname"] == "" || params["user"] == "" {
+			return nil, errInvalidDSN
+		}
+
+		settings := []configSetting{
+			{"db_type", db.Postgres, "!!str"},
+			{"db_host", params["host"], "!!str"},
+		}
+		if params["port"] != "" {
+			if _, err := strconv.Atoi(params["port"]); err != nil {
+				return nil, fmt.Errorf("%w: invalid port %q", errInvalidDSN, params["port"])
+			}
+			settings = append(settings, configSetting{"db_port", params["port"], "!!int"})
+		}
+		settings = append(settings,
+			configSetting{"db_name", params["dbname"], "!!str"},
+			configSetting{"db_user", params["user"], "!!str"},
+		)
+		if params["password"] != "" {
+			settings = append(settings, configSetting{"db_pass", params["password"], "!!str"})
+		}
+
+		// db_ssl is a boolean for the defaults of libpq, or an sslmode.
+		switch params["sslmode"] {
+		case "":
+			settings = append(settings, configSetting{"db_ssl", "true", "!!bool"})
+		case "disable":
+			settings = append(settings, configSetting{"db_ssl", "false", "!!bool"})
+		default:
+			settings = append(settings, configSetting{"db_ssl", params["sslmode"], "!!str"})
+		}
+
+		for param := range params {
+			switch param {
+			case "host", "port", "dbname", "user", "password", "sslmode":
+			default:
+				log.Warn().
+					Str("parameter", param).
+					Msg("DSN parameter cannot be set in the configuration, it is ignored after the migration")
+			}
+		}
+
+		return settings, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", errUnsupportedDatabaseType, dbType)
+}
+
+// parsePostgresDSN returns the parameters of a PostgreSQL connection
+// string, either a postgres:// URL or key=value pairs.
+func parsePostgresDSN(dsn string) (map[string]string, error) {
+	params := map[string]string{}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		dsnURL, err := url.Parse(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidDSN, err)
+		}
+
+		params["host"] = dsnURL.Hostname()
+		params["port"] = dsnURL.Port()
+		params["dbname"] = strings.TrimPrefix(dsnURL.Path, "/")
+		params["user"] = dsnURL.User.Username()
+		params["password"], _ = dsnURL.User.Password()
+		for param, values := range dsnURL.Query() {
+			params[param] = values[0]
+		}
+
+		return params, nil
+	}
+
+	for rest := strings.TrimSpace(dsn); rest != ""; rest = strings.TrimSpace(rest) {
+		param, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: missing = after %q", errInvalidDSN, rest)
+		}
+		param = strings.TrimSpace(param)
+		value = strings.TrimLeft(value, " \t")
+
+		// Quoted values can contain spaces and escape quotes with \.
+		if strings.HasPrefix(value, "'") {
+			var unquoted strings.Builder
+			closed := false
+			index := 1
+			for ; index < len(value); index++ {
+				if value[index] == '\\' && index+1 < len(value) {
+					index++
+				} else if value[index] == '\'' {
+					closed = true
+
+					break
+				}
+				unquoted.WriteByte(value[index])
+			}
+			if !closed {
+				return nil, fmt.Errorf("%w: unterminated quote for %s", errInvalidDSN, param)
+			}
+
+			params[param] = unquoted.String()
+			rest = value[index+1:]
+
+			continue
+		}
+
+		end := strings.IndexAny(value, " \t\n")
+		if end < 0 {
+			end = len(value)
+		}
+		params[param] = value[:end]
+		rest = value[end:]
+	}
+
+	return params, nil
+}
+
+// updateConfigFile sets settings in the YAML configuration file at path
+// by replacing the lines of the top level keys, so the rest of the file
+// is kept as it is. Missing settings are added after db_type, and the
+// previous file is kept with a .bak suffix.
+func updateConfigFile(path string, settings []configSetting) error {
+	if path == "" {
+		return errNoConfigFile
+	}
+
+	if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("%w: %s", errConfigNotYAML, path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	insertAt := len(lines)
+	for _, setting := range settings {
+		value := setting.value
+		if setting.tag == "!!str" {
+			// Marshalling quotes the values YAML would read as
+			// another type, like "false".
+			quoted, err := yaml.Marshal(setting.value)
+			if err != nil {
+				return err
+			}
+			value = strings.TrimSuffix(string(quoted), "\n")
+		}
+		line := setting.key + ": " + value
+
+		index := slices.IndexFunc(lines, func(line string) bool {
+			return strings.HasPrefix(line, setting.key+":")
+		})
+		if index >= 0 {
+			lines[index] = line
+			insertAt = index + 1
+
+			continue
+		}
+
+		lines = slices.Insert(lines, insertAt, line)
+		insertAt++
+	}
+
+	updated := []byte(strings.Join(lines, "\n"))
+	var check map[string]any
+	if err := yaml.Unmarshal(updated, &check); err != nil {
+		return fmt.Errorf("%w: %s", errConfigNotYAML, err)
+	}
+
+	if err := os.WriteFile(path+".bak", data, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, updated, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func settingKeys(settings []configSetting) string {
+	keys := make([]string, len(settings))
+	for index, setting := range settings {
+		keys[index] = setting.key
+	}
+
+	return strings.Join(keys, ", ")
+}
+
+// receiveToFile writes the chunks of a server stream to a new file at
+// path, readable only by its owner. The file is removed if the stream
+// fails.
+func receiveToFile(path string, recv func() ([]byte, error)) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for {
+		chunk, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			_, err = file.Write(chunk)
+		}
+		if err != nil {
+			file.Close()
+			os.Remove(path)
+
+			return err
+		}
+	}
+
+	return file.Sync()
+}
+
+// sendChunks sends what is read from reader in chunks to a client stream.
+func sendChunks(reader io.Reader, send func(data []byte) error) error {
+	buf := make([]byte, sendChunkSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				// The reason is returned by CloseAndRecv.
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	watchEventsCmd.Flags().
+		StringSliceP("type", "t", []string{}, "Only show events of these types (e.g. node.online,route.failover)")
+	eventsCmd.AddCommand(watchEventsCmd)
+}
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Follow node, route and user events",
+}
+
+var watchEventsCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print events as they happen until interrupted",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		eventTypes, _ := cmd.Flags().GetStringSlice("type")
+
+		_, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		// The stream is open ended, so it must not be bound by the
+		// CLI timeout.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		stream, err := client.WatchEvents(ctx, &v1.WatchEventsRequest{
+			Types: eventTypes,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error watching events: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) || ctx.Err() != nil {
+					return
+				}
+
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Error watching events: %s", status.Convert(err).Message()),
+					output,
+				)
+
+				return
+			}
+
+			SuccessOutput(event, eventString(event), output)
+		}
+	},
+}
+
+func eventString(event *v1.Event) string {
+	line := fmt.Sprintf(
+		"%s %s",
+		event.GetCreatedAt().AsTime().Format(HeadscaleDateTimeFormat),
+		event.GetType(),
+	)
+
+	if event.GetUser() != nil {
+		line += fmt.Sprintf(" user=%s", event.GetUser().GetName())
+	}
+
+	if event.GetNode() != nil {
+		line += fmt.Sprintf(" node=%s", event.GetNode().GetGivenName())
+	}
+
+	if event.GetPreviousNode() != nil {
+		line += fmt.Sprintf(" previous_node=%s", event.GetPreviousNode().GetGivenName())
+	}
+
+	if event.GetRoute() != nil {
+		line += fmt.Sprintf(" route=%s", event.GetRoute().GetPrefix())
+	}
+
+	return line
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export FILE",
+	Short: "Export users, nodes, keys, routes and the policy to a JSON document",
+	Long: "Export users, nodes, pre-auth keys, API key hashes, routes, IP reservations " +
+		"and the policy to a versioned JSON document in FILE.\n" +
+		"The export can be imported into an empty database of any type with `headscale import`.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path := args[0]
+
+		_, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		ctx, stop := databaseContext()
+		defer stop()
+
+		stream, err := client.ExportState(ctx, &v1.ExportStateRequest{})
+		if err == nil {
+			err = receiveToFile(path, func() ([]byte, error) {
+				chunk, err := stream.Recv()
+
+				return chunk.GetData(), err
+			})
+		}
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error exporting: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			map[string]string{"Result": "Exported", "File": path},
+			fmt.Sprintf("Exported to %s", path),
+			output,
+		)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import a JSON document made with `headscale export` into an empty database",
+	Long: "Import a JSON document made with `headscale export` into an empty database.\n" +
+		"The database must not have any user, node, pre-auth key, route, IP reservation " +
+		"or policy yet, API keys are added to the existing ones.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path := args[0]
+
+		file, err := os.Open(path)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error opening export file: %s", err), output)
+
+			return
+		}
+		defer file.Close()
+
+		_, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		ctx, stop := databaseContext()
+		defer stop()
+
+		stream, err := client.ImportState(ctx)
+		if err == nil {
+			err = sendChunks(file, func(data []byte) error {
+				return stream.Send(&v1.ImportStateRequest{Data: data})
+			})
+		}
+		if err == nil {
+			_, err = stream.CloseAndRecv()
+		}
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error importing: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			map[string]string{"Result": "Imported", "File": path},
+			fmt.Sprintf("Imported %s", path),
+			output,
+		)
+	},
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"github.com/pterm/pterm"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(ipReservationsCmd)
+
+	createIPReservationCmd.Flags().String("ip", "", "Address from ip_prefixes to reserve")
+	err := createIPReservationCmd.MarkFlagRequired("ip")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	createIPReservationCmd.Flags().String("hostname", "", "Hostname of the node to reserve the address for")
+	createIPReservationCmd.Flags().StringP("user", "u", "", "User of the pre-auth key")
+	createIPReservationCmd.Flags().StringP("key", "k", "", "Pre-auth key of the node to reserve the address for")
+	createIPReservationCmd.MarkFlagsOneRequired("hostname", "key")
+	createIPReservationCmd.MarkFlagsMutuallyExclusive("hostname", "key")
+	createIPReservationCmd.MarkFlagsRequiredTogether("user", "key")
+	ipReservationsCmd.AddCommand(createIPReservationCmd)
+
+	ipReservationsCmd.AddCommand(listIPReservationsCmd)
+
+	deleteIPReservationCmd.Flags().Uint64P("identifier", "i", 0, "Reservation identifier (ID)")
+	err = deleteIPReservationCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	ipReservationsCmd.AddCommand(deleteIPReservationCmd)
+}
+
+var ipReservationsCmd = &cobra.Command{
+	Use:     "ip-reservations",
+	Short:   "Manage the addresses reserved for nodes",
+	Aliases: []string{"ip-reservation", "reservations"},
+}
+
+var createIPReservationCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Reserve an address for a node",
+	Long: "Reserve an address from ip_prefixes for the node registering with the given hostname " +
+		"or pre-auth key. The node gets the address when it registers, if no other node uses it.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ip, _ := cmd.Flags().GetString("ip")
+		hostname, _ := cmd.Flags().GetString("hostname")
+		user, _ := cmd.Flags().GetString("user")
+		key, _ := cmd.Flags().GetString("key")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.CreateIPReservation(ctx, &v1.CreateIPReservationRequest{
+			Ip:         ip,
+			Hostname:   hostname,
+			User:       user,
+			PreAuthKey: key,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot reserve address: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.GetReservation(), "Address reserved", output)
+	},
+}
+
+var listIPReservationsCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the reserved addresses",
+	Aliases: []string{"ls", "show"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListIPReservations(ctx, &v1.ListIPReservationsRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get reserved addresses: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.GetReservations(), "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"ID", "IP", "Hostname", "User", "Pre-auth key ID", "Created"},
+		}
+		for _, reservation := range response.GetReservations() {
+			user, keyID := "", ""
+			if reservation.GetPreAuthKey() != nil {
+				user = reservation.GetPreAuthKey().GetUser()
+				keyID = reservation.GetPreAuthKey().GetId()
+			}
+
+			tableData = append(tableData, []string{
+				strconv.FormatUint(reservation.GetId(), util.Base10),
+				reservation.GetIp(),
+				reservation.GetHostname(),
+				user,
+				keyID,
+				reservation.GetCreatedAt().AsTime().Format(HeadscaleDateTimeFormat),
+			})
+		}
+
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var deleteIPReservationCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "Release a reserved address",
+	Long:    "Release a reserved address, a node already using it keeps it.",
+	Aliases: []string{"remove", "rm", "del"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.DeleteIPReservation(ctx, &v1.DeleteIPReservationRequest{
+			Id: identifier,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot release address: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response, "Address released", output)
+	},
+}
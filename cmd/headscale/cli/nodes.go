@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"net/netip"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,9 +12,11 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
 	"github.com/juanfont/headscale/hscontrol/util"
+	"github.com/prometheus/common/model"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/types/key"
 )
 
@@ -21,6 +24,14 @@ func init() {
 	rootCmd.AddCommand(nodeCmd)
 	listNodesCmd.Flags().StringP("user", "u", "", "Filter by user")
 	listNodesCmd.Flags().BoolP("tags", "t", false, "Show tags")
+	listNodesCmd.Flags().StringSlice("tag", []string{}, "Filter by tag, forced or granted by the policy")
+	listNodesCmd.Flags().Bool("online", false, "Filter by connection state")
+	listNodesCmd.Flags().Bool("expired", false, "Filter by expiry state")
+	listNodesCmd.Flags().String("os", "", "Filter by operating system")
+	listNodesCmd.Flags().String("route", "", "Filter by advertised route")
+	listNodesCmd.Flags().String("hostname", "", "Filter by hostname or name, with a glob pattern")
+	listNodesCmd.Flags().String("sort", "", "Sort by id, name, hostname, user, last_seen, created_at or expiry, followed by \" desc\" to reverse")
+	addListFlags(listNodesCmd, "nodes")
 
 	listNodesCmd.Flags().StringP("namespace", "n", "", "User")
 	listNodesNamespaceFlag := listNodesCmd.Flags().Lookup("namespace")
@@ -47,6 +58,30 @@ func init() {
 	}
 	nodeCmd.AddCommand(registerNodeCmd)
 
+	nodeCmd.AddCommand(listPendingRegistrationsCmd)
+
+	nodeCmd.AddCommand(checkIPPoolsCmd)
+
+	approveNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = approveNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(approveNodeCmd)
+
+	rejectNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	rejectNodeCmd.Flags().StringP("key", "k", "", "Machine key of the pending registration")
+	rejectNodeCmd.MarkFlagsOneRequired("identifier", "key")
+	rejectNodeCmd.MarkFlagsMutuallyExclusive("identifier", "key")
+	nodeCmd.AddCommand(rejectNodeCmd)
+
+	showNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = showNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(showNodeCmd)
+
 	expireNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
 	err = expireNodeCmd.MarkFlagRequired("identifier")
 	if err != nil {
@@ -54,6 +89,25 @@ func init() {
 	}
 	nodeCmd.AddCommand(expireNodeCmd)
 
+	setNodeExpiryCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = setNodeExpiryCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	setNodeExpiryCmd.Flags().StringP("expiration", "e", "", "Human-readable expiration of the node key (e.g. 30m, 24h, 90d)")
+	err = setNodeExpiryCmd.MarkFlagRequired("expiration")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(setNodeExpiryCmd)
+
+	disableNodeExpiryCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = disableNodeExpiryCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(disableNodeExpiryCmd)
+
 	renameNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
 	err = renameNodeCmd.MarkFlagRequired("identifier")
 	if err != nil {
@@ -61,6 +115,18 @@ func init() {
 	}
 	nodeCmd.AddCommand(renameNodeCmd)
 
+	setNodeIPCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = setNodeIPCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	setNodeIPCmd.Flags().String("ip", "", "Address from ip_prefixes to assign to the node")
+	err = setNodeIPCmd.MarkFlagRequired("ip")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(setNodeIPCmd)
+
 	deleteNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
 	err = deleteNodeCmd.MarkFlagRequired("identifier")
 	if err != nil {
@@ -160,7 +226,7 @@ var registerNodeCmd = &cobra.Command{
 var listNodesCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List nodes",
-	Aliases: []string{"ls", "show"},
+	Aliases: []string{"ls"},
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
 		user, err := cmd.Flags().GetString("user")
@@ -176,14 +242,35 @@ var listNodesCmd = &cobra.Command{
 			return
 		}
 
-		ctx, client, conn, cancel := getHeadscaleCLIClient()
-		defer cancel()
-		defer conn.Close()
+		list := getListFlags(cmd)
 
 		request := &v1.ListNodesRequest{
-			User: user,
+			User:      user,
+			PageSize:  list.pageSize,
+			PageToken: list.pageToken,
+			ReadMask:  list.readMask,
+		}
+		request.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		request.Os, _ = cmd.Flags().GetString("os")
+		request.Route, _ = cmd.Flags().GetString("route")
+		request.Hostname, _ = cmd.Flags().GetString("hostname")
+		request.OrderBy, _ = cmd.Flags().GetString("sort")
+
+		// The state filters are only set when the flags are given, as
+		// --online=false lists the offline nodes.
+		if cmd.Flags().Changed("online") {
+			online, _ := cmd.Flags().GetBool("online")
+			request.Online = &online
+		}
+		if cmd.Flags().Changed("expired") {
+			expired, _ := cmd.Flags().GetBool("expired")
+			request.Expired = &expired
 		}
 
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
 		response, err := client.ListNodes(ctx, request)
 		if err != nil {
 			ErrorOutput(
@@ -196,7 +283,11 @@ var listNodesCmd = &cobra.Command{
 		}
 
 		if output != "" {
-			SuccessOutput(response.GetNodes(), "", output)
+			if list.paged() {
+				SuccessOutput(response, "", output)
+			} else {
+				SuccessOutput(response.GetNodes(), "", output)
+			}
 
 			return
 		}
@@ -218,6 +309,386 @@ var listNodesCmd = &cobra.Command{
 
 			return
 		}
+
+		printNextPage(response, len(response.GetNodes()), "nodes")
+	},
+}
+
+var showNodeCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show everything about a node",
+	Long:  "Show a node with its tags, pre-auth key and routes, and its endpoints, preferred DERP region, client and NAT as reported by the node.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.GetNode(ctx, &v1.GetNodeRequest{
+			NodeId:      identifier,
+			Diagnostics: true,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get node: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		routes, err := client.GetNodeRoutes(ctx, &v1.GetNodeRoutesRequest{
+			NodeId: identifier,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get routes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(struct {
+				Node   *v1.Node    `json:"node"`
+				Routes []*v1.Route `json:"routes"`
+			}{response.GetNode(), routes.GetRoutes()}, "", output)
+
+			return
+		}
+
+		err = pterm.DefaultTable.WithData(nodeToPtable(response.GetNode())).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		if len(routes.GetRoutes()) == 0 {
+			return
+		}
+
+		fmt.Println()
+
+		err = pterm.DefaultTable.WithHasHeader().WithData(routesToPtables(routes.GetRoutes())).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var listPendingRegistrationsCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List nodes waiting to be registered",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListPendingRegistrations(ctx, &v1.ListPendingRegistrationsRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get pending registrations: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.GetRegistrations(), "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"MachineKey", "Hostname", "User", "Created", "Expiration", "Status"},
+		}
+		for _, reg := range response.GetRegistrations() {
+			regStatus := pterm.LightYellow("pending")
+			if reg.GetRejected() {
+				regStatus = pterm.LightRed("rejected")
+			}
+
+			tableData = append(tableData, []string{
+				reg.GetMachineKey(),
+				reg.GetHostname(),
+				reg.GetUser().GetName(),
+				reg.GetCreatedAt().AsTime().Format(HeadscaleDateTimeFormat),
+				reg.GetExpiry().AsTime().Format(HeadscaleDateTimeFormat),
+				regStatus,
+			})
+		}
+
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var checkIPPoolsCmd = &cobra.Command{
+	Use:   "check-ip-pools",
+	Short: "List nodes with addresses outside of their IP pool",
+	Long: "List the nodes with an address outside of the IP pool of their user or tag, " +
+		"and the nodes outside of any pool with an address of a pool. " +
+		"Their addresses were allocated before the pools were configured.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListNodesOutsideIPPools(ctx, &v1.ListNodesOutsideIPPoolsRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot check IP pools: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.GetNodes(), "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"ID", "Hostname", "User", "Pool", "Addresses outside of pool"},
+		}
+		for _, outside := range response.GetNodes() {
+			pool := outside.GetPool()
+			if pool == "" {
+				pool = "none"
+			}
+
+			tableData = append(tableData, []string{
+				strconv.FormatUint(outside.GetNode().GetId(), util.Base10),
+				outside.GetNode().GetGivenName(),
+				outside.GetNode().GetUser().GetName(),
+				pool,
+				strings.Join(outside.GetAddresses(), ", "),
+			})
+		}
+
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var approveNodeCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Approve a node waiting for approval",
+	Long:  "Approving a node authorizes it to join the network when node approval is required.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ApproveNode(ctx, &v1.ApproveNodeRequest{
+			NodeId: identifier,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot approve node: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.GetNode(), "Node approved", output)
+	},
+}
+
+var setNodeIPCmd = &cobra.Command{
+	Use:   "set-ip",
+	Short: "Assign a specific address to a node",
+	Long: "Assign an address from ip_prefixes to a node, replacing its address of the same IP family. " +
+		"The address must not be used by another node or reserved for one.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ip, _ := cmd.Flags().GetString("ip")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.SetNodeIP(ctx, &v1.SetNodeIPRequest{
+			NodeId: identifier,
+			Ip:     ip,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot set the address of node: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.GetNode(), "Node address set", output)
+	},
+}
+
+var rejectNodeCmd = &cobra.Command{
+	Use:   "reject",
+	Short: "Reject a node or the pending registration of a node",
+	Long: "Rejecting a node by identifier revokes its approval, it is removed from the network " +
+		"until it is approved again.\n" +
+		"Rejecting a pending registration by machine key refuses the node until the " +
+		"registration expires, it can then ask to be registered again.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		machineKey, err := cmd.Flags().GetString("key")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error getting machine key from flag: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if machineKey == "" {
+			response, err := client.RejectNode(ctx, &v1.RejectNodeRequest{
+				NodeId: identifier,
+			})
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf(
+						"Cannot reject node: %s\n",
+						status.Convert(err).Message(),
+					),
+					output,
+				)
+
+				return
+			}
+
+			SuccessOutput(response.GetNode(), "Node rejected", output)
+
+			return
+		}
+
+		response, err := client.RejectPendingRegistration(ctx, &v1.RejectPendingRegistrationRequest{
+			MachineKey: machineKey,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot reject registration: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			response.GetRegistration(),
+			fmt.Sprintf("Registration of %s rejected", response.GetRegistration().GetHostname()),
+			output,
+		)
 	},
 }
 
@@ -266,6 +737,108 @@ var expireNodeCmd = &cobra.Command{
 	},
 }
 
+var setNodeExpiryCmd = &cobra.Command{
+	Use:   "set-expiry",
+	Short: "Set when the key of a node expires",
+	Long:  "Setting the expiry of a node subjects it to the node_expiry policy again if its expiry was disabled.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		durationStr, _ := cmd.Flags().GetString("expiration")
+
+		duration, err := model.ParseDuration(durationStr)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s\n", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.SetNodeExpiryRequest{
+			NodeId: identifier,
+			Expiry: timestamppb.New(time.Now().UTC().Add(time.Duration(duration))),
+		}
+
+		response, err := client.SetNodeExpiry(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot set node expiry: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.GetNode(), "Node expiry set", output)
+	},
+}
+
+var disableNodeExpiryCmd = &cobra.Command{
+	Use:   "disable-expiry",
+	Short: "Make the key of a node never expire",
+	Long:  "Disabling the expiry of a node removes its current expiry and exempts it from the node_expiry policy.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.DisableNodeExpiryRequest{
+			NodeId: identifier,
+		}
+
+		response, err := client.DisableNodeExpiry(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot disable node expiry: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.GetNode(), "Node expiry disabled", output)
+	},
+}
+
 var renameNodeCmd = &cobra.Command{
 	Use:   "rename NEW_NAME",
 	Short: "Renames a node in your network",
@@ -495,6 +1068,7 @@ func nodesToPtables(
 		"Expiration",
 		"Connected",
 		"Expired",
+		"Approved",
 	}
 	if showTags {
 		tableHeader = append(tableHeader, []string{
@@ -557,6 +1131,13 @@ func nodesToPtables(
 			expired = pterm.LightRed("yes")
 		}
 
+		var approved string
+		if node.GetPendingApproval() {
+			approved = pterm.LightYellow("pending")
+		} else {
+			approved = pterm.LightGreen("yes")
+		}
+
 		var forcedTags string
 		for _, tag := range node.GetForcedTags() {
 			forcedTags += "," + tag
@@ -608,6 +1189,7 @@ func nodesToPtables(
 			expiryTime,
 			online,
 			expired,
+			approved,
 		}
 		if showTags {
 			nodeData = append(nodeData, []string{forcedTags, invalidTags, validTags}...)
@@ -621,6 +1203,94 @@ func nodesToPtables(
 	return tableData, nil
 }
 
+// nodeToPtable renders a single node, with its diagnostics, one field
+// per row.
+func nodeToPtable(node *v1.Node) pterm.TableData {
+	formatTime := func(timestamp *timestamppb.Timestamp) string {
+		if timestamp == nil {
+			return "N/A"
+		}
+
+		return timestamp.AsTime().Format("2006-01-02 15:04:05")
+	}
+
+	online := pterm.LightRed("offline")
+	if node.GetOnline() {
+		online = pterm.LightGreen("online")
+	}
+
+	expiration := formatTime(node.GetExpiry())
+	if node.GetExpiryDisabled() {
+		expiration = "disabled"
+	} else if node.GetExpiry().AsTime().IsZero() {
+		expiration = "N/A"
+	}
+
+	diagnostics := node.GetDiagnostics()
+
+	preferredDERP := "N/A"
+	if diagnostics.GetPreferredDerpRegionId() != 0 {
+		preferredDERP = fmt.Sprintf(
+			"%s (%d)",
+			diagnostics.GetPreferredDerp(),
+			diagnostics.GetPreferredDerpRegionId(),
+		)
+	}
+
+	hardNAT := pterm.LightGreen("no")
+	if diagnostics.GetHardNat() {
+		hardNAT = pterm.LightYellow("yes")
+	}
+
+	// The effective tags of the node are its forced tags and the tags
+	// granted to it by the policy.
+	tags := slices.Clone(node.GetForcedTags())
+	for _, tag := range node.GetValidTags() {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	preAuthKey := "N/A"
+	if authKey := node.GetPreAuthKey(); authKey != nil {
+		preAuthKey = authKey.GetId()
+
+		var properties []string
+		if authKey.GetReusable() {
+			properties = append(properties, "reusable")
+		}
+		if authKey.GetEphemeral() {
+			properties = append(properties, "ephemeral")
+		}
+		if len(properties) > 0 {
+			preAuthKey += " (" + strings.Join(properties, ", ") + ")"
+		}
+	}
+
+	return pterm.TableData{
+		{"ID", strconv.FormatUint(node.GetId(), util.Base10)},
+		{"Hostname", node.GetName()},
+		{"Name", node.GetGivenName()},
+		{"User", node.GetUser().GetName()},
+		{"IP addresses", strings.Join(node.GetIpAddresses(), ", ")},
+		{"Machine key", node.GetMachineKey()},
+		{"Node key", node.GetNodeKey()},
+		{"Connected", online},
+		{"Last seen", formatTime(node.GetLastSeen())},
+		{"Expiration", expiration},
+		{"Tags", strings.Join(tags, ", ")},
+		{"Invalid tags", strings.Join(node.GetInvalidTags(), ", ")},
+		{"Pre-auth key", preAuthKey},
+		{"Endpoints", strings.Join(diagnostics.GetEndpoints(), ", ")},
+		{"Preferred DERP", preferredDERP},
+		{"Hard NAT", hardNAT},
+		{"Client version", diagnostics.GetClientVersion()},
+		{"OS", strings.TrimSpace(diagnostics.GetOs() + " " + diagnostics.GetOsVersion())},
+		{"Capability version", strconv.Itoa(int(diagnostics.GetCapabilityVersion()))},
+		{"Last map request", formatTime(diagnostics.GetLastMapRequest())},
+	}
+}
+
 var tagCmd = &cobra.Command{
 	Use:     "tag",
 	Short:   "Manage the tags of a node",
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/policy"
+	"github.com/pterm/pterm"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(getPolicy)
+
+	setPolicy.Flags().StringP("file", "f", "", "Path to a policy file in HuJSON format")
+	if err := setPolicy.MarkFlagRequired("file"); err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	policyCmd.AddCommand(setPolicy)
+
+	checkPolicy.Flags().StringP("file", "f", "", "Path to a policy file in HuJSON or YAML format")
+	if err := checkPolicy.MarkFlagRequired("file"); err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	policyCmd.AddCommand(checkPolicy)
+
+	testPolicy.Flags().String("src", "", "Source node, user, group, tag or IP")
+	if err := testPolicy.MarkFlagRequired("src"); err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	testPolicy.Flags().String("dst", "", "Destination in the form <node|ip|alias>:<port>")
+	testPolicy.Flags().String("proto", "", "Protocol, e.g. tcp, udp or icmp (default tcp when --dst is set)")
+	policyCmd.AddCommand(testPolicy)
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage the Headscale ACL Policy",
+}
+
+var getPolicy = &cobra.Command{
+	Use:     "get",
+	Short:   "Print the current ACL Policy",
+	Aliases: []string{"show", "view", "fetch"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.GetPolicyRequest{}
+
+		response, err := client.GetPolicy(ctx, request)
+		if status.Code(err) == codes.NotFound {
+			ErrorOutput(
+				err,
+				"No ACL Policy is set, all traffic is allowed",
+				output,
+			)
+
+			return
+		}
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed loading ACL Policy: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response, response.GetPolicy(), output)
+	},
+}
+
+var setPolicy = &cobra.Command{
+	Use:   "set",
+	Short: "Updates the ACL Policy",
+	Long: `
+	Updates the existing ACL Policy with the provided policy. The policy must be a valid HuJSON object.
+	The policy is validated, including its tests, before it is stored and pushed to all nodes.
+	This command only works when the acl_policy_mode of the server is set to "database".`,
+	Aliases: []string{"put", "update"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		policyPath, _ := cmd.Flags().GetString("file")
+
+		policyBytes, err := os.ReadFile(policyPath)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error reading the policy file: %s", err), output)
+
+			return
+		}
+
+		request := &v1.SetPolicyRequest{Policy: string(policyBytes)}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.SetPolicy(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to set ACL Policy: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			response,
+			fmt.Sprintf("Policy updated to version %d.", response.GetVersion()),
+			output,
+		)
+	},
+}
+
+var checkPolicy = &cobra.Command{
+	Use:   "check",
+	Short: "Check the ACL Policy",
+	Long: `
+	Validates a policy file locally, including the tests it contains,
+	without contacting the server.`,
+	Aliases: []string{"validate"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		policyPath, _ := cmd.Flags().GetString("file")
+
+		_, err := policy.LoadACLPolicyFromPath(policyPath)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Policy is invalid: %s", err), output)
+
+			return
+		}
+
+		SuccessOutput(nil, "Policy is valid", output)
+	},
+}
+
+var testPolicy = &cobra.Command{
+	Use:   "test",
+	Short: "Check what the current ACL Policy allows",
+	Long: `
+	Asks the server whether the source can reach the destination with the policy currently in use,
+	and which ACL entries allow it.
+	Without --dst, every destination the source can reach is listed instead.`,
+	Aliases: []string{"can"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		src, _ := cmd.Flags().GetString("src")
+		dst, _ := cmd.Flags().GetString("dst")
+		proto, _ := cmd.Flags().GetString("proto")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.CheckAccessRequest{
+			Source:      src,
+			Destination: dst,
+			Protocol:    proto,
+		}
+
+		response, err := client.CheckAccess(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to check access: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response, "", output)
+
+			return
+		}
+
+		if dst != "" {
+			if !response.GetAllowed() {
+				SuccessOutput(response, fmt.Sprintf("%s cannot reach %s", src, dst), output)
+
+				return
+			}
+
+			if len(response.GetRules()) == 0 {
+				SuccessOutput(
+					response,
+					fmt.Sprintf("%s can reach %s, no ACL policy is in use", src, dst),
+					output,
+				)
+
+				return
+			}
+
+			lines := []string{fmt.Sprintf("%s can reach %s, allowed by:", src, dst)}
+			for _, rule := range response.GetRules() {
+				lines = append(lines, "  "+aclRuleString(rule))
+			}
+
+			SuccessOutput(response, strings.Join(lines, "\n"), output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"Destination", "Ports", "Nodes", "ACL"},
+		}
+		for _, reach := range response.GetReachable() {
+			acl := "-"
+			if reach.GetRule() != nil {
+				acl = aclRuleString(reach.GetRule())
+			}
+
+			tableData = append(tableData, []string{
+				reach.GetPrefix(),
+				reach.GetPorts(),
+				strings.Join(reach.GetNodes(), ", "),
+				acl,
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+func aclRuleString(rule *v1.AclRule) string {
+	proto := ""
+	if rule.GetProtocol() != "" {
+		proto = fmt.Sprintf(" proto %s", rule.GetProtocol())
+	}
+
+	return fmt.Sprintf(
+		"acls[%d]: %s%s from %s to %s",
+		rule.GetIndex(),
+		rule.GetAction(),
+		proto,
+		strings.Join(rule.GetSources(), ", "),
+		strings.Join(rule.GetDestinations(), ", "),
+	)
+}
@@ -31,6 +31,7 @@ func init() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("")
 	}
+	addListFlags(listPreAuthKeys, "keys")
 	preauthkeysCmd.AddCommand(listPreAuthKeys)
 	preauthkeysCmd.AddCommand(createPreAuthKeyCmd)
 	preauthkeysCmd.AddCommand(expirePreAuthKeyCmd)
@@ -68,8 +69,13 @@ var listPreAuthKeys = &cobra.Command{
 		defer cancel()
 		defer conn.Close()
 
+		list := getListFlags(cmd)
+
 		request := &v1.ListPreAuthKeysRequest{
-			User: user,
+			User:      user,
+			PageSize:  list.pageSize,
+			PageToken: list.pageToken,
+			ReadMask:  list.readMask,
 		}
 
 		response, err := client.ListPreAuthKeys(ctx, request)
@@ -84,7 +90,11 @@ var listPreAuthKeys = &cobra.Command{
 		}
 
 		if output != "" {
-			SuccessOutput(response.GetPreAuthKeys(), "", output)
+			if list.paged() {
+				SuccessOutput(response, "", output)
+			} else {
+				SuccessOutput(response.GetPreAuthKeys(), "", output)
+			}
 
 			return
 		}
@@ -144,6 +154,8 @@ var listPreAuthKeys = &cobra.Command{
 
 			return
 		}
+
+		printNextPage(response, len(response.GetPreAuthKeys()), "keys")
 	},
 }
 
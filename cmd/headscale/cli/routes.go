@@ -20,6 +20,8 @@ const (
 func init() {
 	rootCmd.AddCommand(routesCmd)
 	listRoutesCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	// The routes of a single node are not paginated.
+	addListFlags(listRoutesCmd, "routes")
 	routesCmd.AddCommand(listRoutesCmd)
 
 	enableRouteCmd.Flags().Uint64P("route", "r", 0, "Route identifier (ID)")
@@ -73,9 +75,16 @@ var listRoutesCmd = &cobra.Command{
 		defer conn.Close()
 
 		var routes []*v1.Route
+		var page pagedResponse
 
 		if machineID == 0 {
-			response, err := client.GetRoutes(ctx, &v1.GetRoutesRequest{})
+			list := getListFlags(cmd)
+
+			response, err := client.GetRoutes(ctx, &v1.GetRoutesRequest{
+				PageSize:  list.pageSize,
+				PageToken: list.pageToken,
+				ReadMask:  list.readMask,
+			})
 			if err != nil {
 				ErrorOutput(
 					err,
@@ -87,12 +96,17 @@ var listRoutesCmd = &cobra.Command{
 			}
 
 			if output != "" {
-				SuccessOutput(response.GetRoutes(), "", output)
+				if list.paged() {
+					SuccessOutput(response, "", output)
+				} else {
+					SuccessOutput(response.GetRoutes(), "", output)
+				}
 
 				return
 			}
 
 			routes = response.GetRoutes()
+			page = response
 		} else {
 			response, err := client.GetNodeRoutes(ctx, &v1.GetNodeRoutesRequest{
 				NodeId: machineID,
@@ -133,6 +147,10 @@ var listRoutesCmd = &cobra.Command{
 
 			return
 		}
+
+		if page != nil {
+			printNextPage(page, len(routes), "routes")
+		}
 	},
 }
 
@@ -15,6 +15,7 @@ import (
 func init() {
 	rootCmd.AddCommand(userCmd)
 	userCmd.AddCommand(createUserCmd)
+	addListFlags(listUsersCmd, "users")
 	userCmd.AddCommand(listUsersCmd)
 	userCmd.AddCommand(destroyUserCmd)
 	userCmd.AddCommand(renameUserCmd)
@@ -155,7 +156,13 @@ var listUsersCmd = &cobra.Command{
 		defer cancel()
 		defer conn.Close()
 
-		request := &v1.ListUsersRequest{}
+		list := getListFlags(cmd)
+
+		request := &v1.ListUsersRequest{
+			PageSize:  list.pageSize,
+			PageToken: list.pageToken,
+			ReadMask:  list.readMask,
+		}
 
 		response, err := client.ListUsers(ctx, request)
 		if err != nil {
@@ -169,7 +176,11 @@ var listUsersCmd = &cobra.Command{
 		}
 
 		if output != "" {
-			SuccessOutput(response.GetUsers(), "", output)
+			if list.paged() {
+				SuccessOutput(response, "", output)
+			} else {
+				SuccessOutput(response.GetUsers(), "", output)
+			}
 
 			return
 		}
@@ -195,6 +206,8 @@ var listUsersCmd = &cobra.Command{
 
 			return
 		}
+
+		printNextPage(response, len(response.GetUsers()), "users")
 	},
 }
 
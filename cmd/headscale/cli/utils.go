@@ -10,13 +10,14 @@ import (
 
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
 	"github.com/juanfont/headscale/hscontrol"
-	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"gopkg.in/yaml.v3"
 )
 
@@ -39,21 +40,6 @@ func getHeadscaleApp() (*hscontrol.Headscale, error) {
 		return nil, err
 	}
 
-	// We are doing this here, as in the future could be cool to have it also hot-reload
-
-	if cfg.ACL.PolicyPath != "" {
-		aclPath := util.AbsolutePathFromConfigPath(cfg.ACL.PolicyPath)
-		pol, err := policy.LoadACLPolicyFromPath(aclPath)
-		if err != nil {
-			log.Fatal().
-				Str("path", aclPath).
-				Err(err).
-				Msg("Could not load the ACL policy")
-		}
-
-		app.ACLPolicy = pol
-	}
-
 	return app, nil
 }
 
@@ -222,3 +208,59 @@ func contains[T string](ts []T, t T) bool {
 
 	return false
 }
+
+// addListFlags adds the pagination and field flags of a list command of
+// items.
+func addListFlags(cmd *cobra.Command, items string) {
+	cmd.Flags().Uint32("page-size", 0, fmt.Sprintf("Number of %s per page, all %s are listed if 0", items, items))
+	cmd.Flags().String("page-token", "", "Token of the page to list, printed with the previous page")
+	cmd.Flags().StringSlice("fields", []string{}, fmt.Sprintf("Fields of the %s to return (e.g. id,name), all fields if empty", items))
+}
+
+// listFlags are the values of the flags added by addListFlags.
+type listFlags struct {
+	pageSize  uint32
+	pageToken string
+	readMask  *fieldmaskpb.FieldMask
+}
+
+func getListFlags(cmd *cobra.Command) listFlags {
+	var flags listFlags
+	flags.pageSize, _ = cmd.Flags().GetUint32("page-size")
+	flags.pageToken, _ = cmd.Flags().GetString("page-token")
+
+	if fields, _ := cmd.Flags().GetStringSlice("fields"); len(fields) > 0 {
+		flags.readMask = &fieldmaskpb.FieldMask{Paths: fields}
+	}
+
+	return flags
+}
+
+// paged reports if a page is asked for. The whole response is printed by
+// the machine outputs of paged lists, and the items alone otherwise to
+// keep the output of the unpaginated lists unchanged.
+func (flags listFlags) paged() bool {
+	return flags.pageSize > 0 || flags.pageToken != ""
+}
+
+// pagedResponse is the response of a paginated list call.
+type pagedResponse interface {
+	GetNextPageToken() string
+	GetTotalSize() uint32
+}
+
+// printNextPage prints how to list the next page after the table of the
+// listed items, if the list has a next page.
+func printNextPage(response pagedResponse, listed int, items string) {
+	if response.GetNextPageToken() == "" {
+		return
+	}
+
+	fmt.Printf(
+		"Listed %d of %d %s, next page: --page-token %s\n",
+		listed,
+		response.GetTotalSize(),
+		items,
+		response.GetNextPageToken(),
+	)
+}
@@ -205,3 +205,58 @@ tls_letsencrypt_challenge_type: TLS-ALPN-01
 	err = types.LoadConfig(tmpDir, false)
 	c.Assert(err, check.IsNil)
 }
+
+func (*Suite) TestConfigReload(c *check.C) {
+	tmpDir, err := os.MkdirTemp("", "headscale")
+	if err != nil {
+		c.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	configYaml := []byte(`---
+noise:
+  private_key_path: noise_private.key
+server_url: http://127.0.0.1:8080
+listen_addr: 127.0.0.1:8080
+randomize_client_port: false
+dns_config:
+  nameservers:
+    - 1.1.1.1
+`)
+	writeConfig(c, tmpDir, configYaml)
+
+	err = types.LoadConfig(tmpDir, false)
+	c.Assert(err, check.IsNil)
+
+	cfg, err := types.GetHeadscaleConfig()
+	c.Assert(err, check.IsNil)
+
+	configYaml = []byte(`---
+noise:
+  private_key_path: noise_private.key
+server_url: http://127.0.0.1:8080
+listen_addr: 0.0.0.0:8080
+randomize_client_port: true
+dns_config:
+  nameservers:
+    - 9.9.9.9
+`)
+	writeConfig(c, tmpDir, configYaml)
+
+	reloaded, err := types.ReloadConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(reloaded.RandomizeClientPort, check.Equals, true)
+	c.Assert(reloaded.DNSConfig.Nameservers[0].String(), check.Equals, "9.9.9.9")
+	c.Assert(cfg.RestartRequired(reloaded), check.DeepEquals, []string{"listen_addr"})
+
+	configYaml = []byte(`---
+noise:
+  private_key_path: noise_private.key
+server_url: http://127.0.0.1:8080
+ephemeral_node_inactivity_timeout: 10s
+`)
+	writeConfig(c, tmpDir, configYaml)
+
+	_, err = types.ReloadConfig()
+	c.Assert(err, check.NotNil)
+}
@@ -19,31 +19,51 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	HeadscaleService_GetUser_FullMethodName          = "/headscale.v1.HeadscaleService/GetUser"
-	HeadscaleService_CreateUser_FullMethodName       = "/headscale.v1.HeadscaleService/CreateUser"
-	HeadscaleService_RenameUser_FullMethodName       = "/headscale.v1.HeadscaleService/RenameUser"
-	HeadscaleService_DeleteUser_FullMethodName       = "/headscale.v1.HeadscaleService/DeleteUser"
-	HeadscaleService_ListUsers_FullMethodName        = "/headscale.v1.HeadscaleService/ListUsers"
-	HeadscaleService_CreatePreAuthKey_FullMethodName = "/headscale.v1.HeadscaleService/CreatePreAuthKey"
-	HeadscaleService_ExpirePreAuthKey_FullMethodName = "/headscale.v1.HeadscaleService/ExpirePreAuthKey"
-	HeadscaleService_ListPreAuthKeys_FullMethodName  = "/headscale.v1.HeadscaleService/ListPreAuthKeys"
-	HeadscaleService_DebugCreateNode_FullMethodName  = "/headscale.v1.HeadscaleService/DebugCreateNode"
-	HeadscaleService_GetNode_FullMethodName          = "/headscale.v1.HeadscaleService/GetNode"
-	HeadscaleService_SetTags_FullMethodName          = "/headscale.v1.HeadscaleService/SetTags"
-	HeadscaleService_RegisterNode_FullMethodName     = "/headscale.v1.HeadscaleService/RegisterNode"
-	HeadscaleService_DeleteNode_FullMethodName       = "/headscale.v1.HeadscaleService/DeleteNode"
-	HeadscaleService_ExpireNode_FullMethodName       = "/headscale.v1.HeadscaleService/ExpireNode"
-	HeadscaleService_RenameNode_FullMethodName       = "/headscale.v1.HeadscaleService/RenameNode"
-	HeadscaleService_ListNodes_FullMethodName        = "/headscale.v1.HeadscaleService/ListNodes"
-	HeadscaleService_MoveNode_FullMethodName         = "/headscale.v1.HeadscaleService/MoveNode"
-	HeadscaleService_GetRoutes_FullMethodName        = "/headscale.v1.HeadscaleService/GetRoutes"
-	HeadscaleService_EnableRoute_FullMethodName      = "/headscale.v1.HeadscaleService/EnableRoute"
-	HeadscaleService_DisableRoute_FullMethodName     = "/headscale.v1.HeadscaleService/DisableRoute"
-	HeadscaleService_GetNodeRoutes_FullMethodName    = "/headscale.v1.HeadscaleService/GetNodeRoutes"
-	HeadscaleService_DeleteRoute_FullMethodName      = "/headscale.v1.HeadscaleService/DeleteRoute"
-	HeadscaleService_CreateApiKey_FullMethodName     = "/headscale.v1.HeadscaleService/CreateApiKey"
-	HeadscaleService_ExpireApiKey_FullMethodName     = "/headscale.v1.HeadscaleService/ExpireApiKey"
-	HeadscaleService_ListApiKeys_FullMethodName      = "/headscale.v1.HeadscaleService/ListApiKeys"
+	HeadscaleService_GetUser_FullMethodName                   = "/headscale.v1.HeadscaleService/GetUser"
+	HeadscaleService_CreateUser_FullMethodName                = "/headscale.v1.HeadscaleService/CreateUser"
+	HeadscaleService_RenameUser_FullMethodName                = "/headscale.v1.HeadscaleService/RenameUser"
+	HeadscaleService_DeleteUser_FullMethodName                = "/headscale.v1.HeadscaleService/DeleteUser"
+	HeadscaleService_ListUsers_FullMethodName                 = "/headscale.v1.HeadscaleService/ListUsers"
+	HeadscaleService_CreatePreAuthKey_FullMethodName          = "/headscale.v1.HeadscaleService/CreatePreAuthKey"
+	HeadscaleService_ExpirePreAuthKey_FullMethodName          = "/headscale.v1.HeadscaleService/ExpirePreAuthKey"
+	HeadscaleService_ListPreAuthKeys_FullMethodName           = "/headscale.v1.HeadscaleService/ListPreAuthKeys"
+	HeadscaleService_DebugCreateNode_FullMethodName           = "/headscale.v1.HeadscaleService/DebugCreateNode"
+	HeadscaleService_GetNode_FullMethodName                   = "/headscale.v1.HeadscaleService/GetNode"
+	HeadscaleService_SetTags_FullMethodName                   = "/headscale.v1.HeadscaleService/SetTags"
+	HeadscaleService_RegisterNode_FullMethodName              = "/headscale.v1.HeadscaleService/RegisterNode"
+	HeadscaleService_DeleteNode_FullMethodName                = "/headscale.v1.HeadscaleService/DeleteNode"
+	HeadscaleService_ExpireNode_FullMethodName                = "/headscale.v1.HeadscaleService/ExpireNode"
+	HeadscaleService_SetNodeExpiry_FullMethodName             = "/headscale.v1.HeadscaleService/SetNodeExpiry"
+	HeadscaleService_DisableNodeExpiry_FullMethodName         = "/headscale.v1.HeadscaleService/DisableNodeExpiry"
+	HeadscaleService_ApproveNode_FullMethodName               = "/headscale.v1.HeadscaleService/ApproveNode"
+	HeadscaleService_RejectNode_FullMethodName                = "/headscale.v1.HeadscaleService/RejectNode"
+	HeadscaleService_ListNodesOutsideIPPools_FullMethodName   = "/headscale.v1.HeadscaleService/ListNodesOutsideIPPools"
+	HeadscaleService_RenameNode_FullMethodName                = "/headscale.v1.HeadscaleService/RenameNode"
+	HeadscaleService_ListNodes_FullMethodName                 = "/headscale.v1.HeadscaleService/ListNodes"
+	HeadscaleService_MoveNode_FullMethodName                  = "/headscale.v1.HeadscaleService/MoveNode"
+	HeadscaleService_SetNodeIP_FullMethodName                 = "/headscale.v1.HeadscaleService/SetNodeIP"
+	HeadscaleService_ListPendingRegistrations_FullMethodName  = "/headscale.v1.HeadscaleService/ListPendingRegistrations"
+	HeadscaleService_RejectPendingRegistration_FullMethodName = "/headscale.v1.HeadscaleService/RejectPendingRegistration"
+	HeadscaleService_CreateIPReservation_FullMethodName       = "/headscale.v1.HeadscaleService/CreateIPReservation"
+	HeadscaleService_ListIPReservations_FullMethodName        = "/headscale.v1.HeadscaleService/ListIPReservations"
+	HeadscaleService_DeleteIPReservation_FullMethodName       = "/headscale.v1.HeadscaleService/DeleteIPReservation"
+	HeadscaleService_GetRoutes_FullMethodName                 = "/headscale.v1.HeadscaleService/GetRoutes"
+	HeadscaleService_EnableRoute_FullMethodName               = "/headscale.v1.HeadscaleService/EnableRoute"
+	HeadscaleService_DisableRoute_FullMethodName              = "/headscale.v1.HeadscaleService/DisableRoute"
+	HeadscaleService_GetNodeRoutes_FullMethodName             = "/headscale.v1.HeadscaleService/GetNodeRoutes"
+	HeadscaleService_DeleteRoute_FullMethodName               = "/headscale.v1.HeadscaleService/DeleteRoute"
+	HeadscaleService_CreateApiKey_FullMethodName              = "/headscale.v1.HeadscaleService/CreateApiKey"
+	HeadscaleService_ExpireApiKey_FullMethodName              = "/headscale.v1.HeadscaleService/ExpireApiKey"
+	HeadscaleService_ListApiKeys_FullMethodName               = "/headscale.v1.HeadscaleService/ListApiKeys"
+	HeadscaleService_GetPolicy_FullMethodName                 = "/headscale.v1.HeadscaleService/GetPolicy"
+	HeadscaleService_SetPolicy_FullMethodName                 = "/headscale.v1.HeadscaleService/SetPolicy"
+	HeadscaleService_CheckAccess_FullMethodName               = "/headscale.v1.HeadscaleService/CheckAccess"
+	HeadscaleService_ListAuditEvents_FullMethodName           = "/headscale.v1.HeadscaleService/ListAuditEvents"
+	HeadscaleService_WatchEvents_FullMethodName               = "/headscale.v1.HeadscaleService/WatchEvents"
+	HeadscaleService_BackupDatabase_FullMethodName            = "/headscale.v1.HeadscaleService/BackupDatabase"
+	HeadscaleService_RestoreDatabase_FullMethodName           = "/headscale.v1.HeadscaleService/RestoreDatabase"
+	HeadscaleService_ExportState_FullMethodName               = "/headscale.v1.HeadscaleService/ExportState"
+	HeadscaleService_ImportState_FullMethodName               = "/headscale.v1.HeadscaleService/ImportState"
 )
 
 // HeadscaleServiceClient is the client API for HeadscaleService service.
@@ -67,9 +87,21 @@ type HeadscaleServiceClient interface {
 	RegisterNode(ctx context.Context, in *RegisterNodeRequest, opts ...grpc.CallOption) (*RegisterNodeResponse, error)
 	DeleteNode(ctx context.Context, in *DeleteNodeRequest, opts ...grpc.CallOption) (*DeleteNodeResponse, error)
 	ExpireNode(ctx context.Context, in *ExpireNodeRequest, opts ...grpc.CallOption) (*ExpireNodeResponse, error)
+	SetNodeExpiry(ctx context.Context, in *SetNodeExpiryRequest, opts ...grpc.CallOption) (*SetNodeExpiryResponse, error)
+	DisableNodeExpiry(ctx context.Context, in *DisableNodeExpiryRequest, opts ...grpc.CallOption) (*DisableNodeExpiryResponse, error)
+	ApproveNode(ctx context.Context, in *ApproveNodeRequest, opts ...grpc.CallOption) (*ApproveNodeResponse, error)
+	RejectNode(ctx context.Context, in *RejectNodeRequest, opts ...grpc.CallOption) (*RejectNodeResponse, error)
+	ListNodesOutsideIPPools(ctx context.Context, in *ListNodesOutsideIPPoolsRequest, opts ...grpc.CallOption) (*ListNodesOutsideIPPoolsResponse, error)
 	RenameNode(ctx context.Context, in *RenameNodeRequest, opts ...grpc.CallOption) (*RenameNodeResponse, error)
 	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
 	MoveNode(ctx context.Context, in *MoveNodeRequest, opts ...grpc.CallOption) (*MoveNodeResponse, error)
+	SetNodeIP(ctx context.Context, in *SetNodeIPRequest, opts ...grpc.CallOption) (*SetNodeIPResponse, error)
+	ListPendingRegistrations(ctx context.Context, in *ListPendingRegistrationsRequest, opts ...grpc.CallOption) (*ListPendingRegistrationsResponse, error)
+	RejectPendingRegistration(ctx context.Context, in *RejectPendingRegistrationRequest, opts ...grpc.CallOption) (*RejectPendingRegistrationResponse, error)
+	// --- IPReservation start ---
+	CreateIPReservation(ctx context.Context, in *CreateIPReservationRequest, opts ...grpc.CallOption) (*CreateIPReservationResponse, error)
+	ListIPReservations(ctx context.Context, in *ListIPReservationsRequest, opts ...grpc.CallOption) (*ListIPReservationsResponse, error)
+	DeleteIPReservation(ctx context.Context, in *DeleteIPReservationRequest, opts ...grpc.CallOption) (*DeleteIPReservationResponse, error)
 	// --- Route start ---
 	GetRoutes(ctx context.Context, in *GetRoutesRequest, opts ...grpc.CallOption) (*GetRoutesResponse, error)
 	EnableRoute(ctx context.Context, in *EnableRouteRequest, opts ...grpc.CallOption) (*EnableRouteResponse, error)
@@ -80,6 +112,19 @@ type HeadscaleServiceClient interface {
 	CreateApiKey(ctx context.Context, in *CreateApiKeyRequest, opts ...grpc.CallOption) (*CreateApiKeyResponse, error)
 	ExpireApiKey(ctx context.Context, in *ExpireApiKeyRequest, opts ...grpc.CallOption) (*ExpireApiKeyResponse, error)
 	ListApiKeys(ctx context.Context, in *ListApiKeysRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error)
+	// --- Policy start ---
+	GetPolicy(ctx context.Context, in *GetPolicyRequest, opts ...grpc.CallOption) (*GetPolicyResponse, error)
+	SetPolicy(ctx context.Context, in *SetPolicyRequest, opts ...grpc.CallOption) (*SetPolicyResponse, error)
+	CheckAccess(ctx context.Context, in *CheckAccessRequest, opts ...grpc.CallOption) (*CheckAccessResponse, error)
+	// --- Audit start ---
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// --- Events start ---
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (HeadscaleService_WatchEventsClient, error)
+	// --- Database start ---
+	BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (HeadscaleService_BackupDatabaseClient, error)
+	RestoreDatabase(ctx context.Context, opts ...grpc.CallOption) (HeadscaleService_RestoreDatabaseClient, error)
+	ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (HeadscaleService_ExportStateClient, error)
+	ImportState(ctx context.Context, opts ...grpc.CallOption) (HeadscaleService_ImportStateClient, error)
 }
 
 type headscaleServiceClient struct {
@@ -216,6 +261,51 @@ func (c *headscaleServiceClient) ExpireNode(ctx context.Context, in *ExpireNodeR
 	return out, nil
 }
 
+func (c *headscaleServiceClient) SetNodeExpiry(ctx context.Context, in *SetNodeExpiryRequest, opts ...grpc.CallOption) (*SetNodeExpiryResponse, error) {
+	out := new(SetNodeExpiryResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_SetNodeExpiry_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) DisableNodeExpiry(ctx context.Context, in *DisableNodeExpiryRequest, opts ...grpc.CallOption) (*DisableNodeExpiryResponse, error) {
+	out := new(DisableNodeExpiryResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_DisableNodeExpiry_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ApproveNode(ctx context.Context, in *ApproveNodeRequest, opts ...grpc.CallOption) (*ApproveNodeResponse, error) {
+	out := new(ApproveNodeResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_ApproveNode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) RejectNode(ctx context.Context, in *RejectNodeRequest, opts ...grpc.CallOption) (*RejectNodeResponse, error) {
+	out := new(RejectNodeResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_RejectNode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListNodesOutsideIPPools(ctx context.Context, in *ListNodesOutsideIPPoolsRequest, opts ...grpc.CallOption) (*ListNodesOutsideIPPoolsResponse, error) {
+	out := new(ListNodesOutsideIPPoolsResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_ListNodesOutsideIPPools_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *headscaleServiceClient) RenameNode(ctx context.Context, in *RenameNodeRequest, opts ...grpc.CallOption) (*RenameNodeResponse, error) {
 	out := new(RenameNodeResponse)
 	err := c.cc.Invoke(ctx, HeadscaleService_RenameNode_FullMethodName, in, out, opts...)
@@ -243,6 +333,60 @@ func (c *headscaleServiceClient) MoveNode(ctx context.Context, in *MoveNodeReque
 	return out, nil
 }
 
+func (c *headscaleServiceClient) SetNodeIP(ctx context.Context, in *SetNodeIPRequest, opts ...grpc.CallOption) (*SetNodeIPResponse, error) {
+	out := new(SetNodeIPResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_SetNodeIP_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListPendingRegistrations(ctx context.Context, in *ListPendingRegistrationsRequest, opts ...grpc.CallOption) (*ListPendingRegistrationsResponse, error) {
+	out := new(ListPendingRegistrationsResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_ListPendingRegistrations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) RejectPendingRegistration(ctx context.Context, in *RejectPendingRegistrationRequest, opts ...grpc.CallOption) (*RejectPendingRegistrationResponse, error) {
+	out := new(RejectPendingRegistrationResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_RejectPendingRegistration_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) CreateIPReservation(ctx context.Context, in *CreateIPReservationRequest, opts ...grpc.CallOption) (*CreateIPReservationResponse, error) {
+	out := new(CreateIPReservationResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_CreateIPReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListIPReservations(ctx context.Context, in *ListIPReservationsRequest, opts ...grpc.CallOption) (*ListIPReservationsResponse, error) {
+	out := new(ListIPReservationsResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_ListIPReservations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) DeleteIPReservation(ctx context.Context, in *DeleteIPReservationRequest, opts ...grpc.CallOption) (*DeleteIPReservationResponse, error) {
+	out := new(DeleteIPReservationResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_DeleteIPReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *headscaleServiceClient) GetRoutes(ctx context.Context, in *GetRoutesRequest, opts ...grpc.CallOption) (*GetRoutesResponse, error) {
 	out := new(GetRoutesResponse)
 	err := c.cc.Invoke(ctx, HeadscaleService_GetRoutes_FullMethodName, in, out, opts...)
@@ -315,6 +459,206 @@ func (c *headscaleServiceClient) ListApiKeys(ctx context.Context, in *ListApiKey
 	return out, nil
 }
 
+func (c *headscaleServiceClient) GetPolicy(ctx context.Context, in *GetPolicyRequest, opts ...grpc.CallOption) (*GetPolicyResponse, error) {
+	out := new(GetPolicyResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_GetPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) SetPolicy(ctx context.Context, in *SetPolicyRequest, opts ...grpc.CallOption) (*SetPolicyResponse, error) {
+	out := new(SetPolicyResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_SetPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) CheckAccess(ctx context.Context, in *CheckAccessRequest, opts ...grpc.CallOption) (*CheckAccessResponse, error) {
+	out := new(CheckAccessResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_CheckAccess_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, HeadscaleService_ListAuditEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (HeadscaleService_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HeadscaleService_ServiceDesc.Streams[0], HeadscaleService_WatchEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &headscaleServiceWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HeadscaleService_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type headscaleServiceWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *headscaleServiceWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *headscaleServiceClient) BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (HeadscaleService_BackupDatabaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HeadscaleService_ServiceDesc.Streams[1], HeadscaleService_BackupDatabase_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &headscaleServiceBackupDatabaseClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HeadscaleService_BackupDatabaseClient interface {
+	Recv() (*BackupDatabaseResponse, error)
+	grpc.ClientStream
+}
+
+type headscaleServiceBackupDatabaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *headscaleServiceBackupDatabaseClient) Recv() (*BackupDatabaseResponse, error) {
+	m := new(BackupDatabaseResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *headscaleServiceClient) RestoreDatabase(ctx context.Context, opts ...grpc.CallOption) (HeadscaleService_RestoreDatabaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HeadscaleService_ServiceDesc.Streams[2], HeadscaleService_RestoreDatabase_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &headscaleServiceRestoreDatabaseClient{stream}
+	return x, nil
+}
+
+type HeadscaleService_RestoreDatabaseClient interface {
+	Send(*RestoreDatabaseRequest) error
+	CloseAndRecv() (*RestoreDatabaseResponse, error)
+	grpc.ClientStream
+}
+
+type headscaleServiceRestoreDatabaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *headscaleServiceRestoreDatabaseClient) Send(m *RestoreDatabaseRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *headscaleServiceRestoreDatabaseClient) CloseAndRecv() (*RestoreDatabaseResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RestoreDatabaseResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *headscaleServiceClient) ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (HeadscaleService_ExportStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HeadscaleService_ServiceDesc.Streams[3], HeadscaleService_ExportState_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &headscaleServiceExportStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HeadscaleService_ExportStateClient interface {
+	Recv() (*ExportStateResponse, error)
+	grpc.ClientStream
+}
+
+type headscaleServiceExportStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *headscaleServiceExportStateClient) Recv() (*ExportStateResponse, error) {
+	m := new(ExportStateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *headscaleServiceClient) ImportState(ctx context.Context, opts ...grpc.CallOption) (HeadscaleService_ImportStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HeadscaleService_ServiceDesc.Streams[4], HeadscaleService_ImportState_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &headscaleServiceImportStateClient{stream}
+	return x, nil
+}
+
+type HeadscaleService_ImportStateClient interface {
+	Send(*ImportStateRequest) error
+	CloseAndRecv() (*ImportStateResponse, error)
+	grpc.ClientStream
+}
+
+type headscaleServiceImportStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *headscaleServiceImportStateClient) Send(m *ImportStateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *headscaleServiceImportStateClient) CloseAndRecv() (*ImportStateResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportStateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HeadscaleServiceServer is the server API for HeadscaleService service.
 // All implementations must embed UnimplementedHeadscaleServiceServer
 // for forward compatibility
@@ -336,9 +680,21 @@ type HeadscaleServiceServer interface {
 	RegisterNode(context.Context, *RegisterNodeRequest) (*RegisterNodeResponse, error)
 	DeleteNode(context.Context, *DeleteNodeRequest) (*DeleteNodeResponse, error)
 	ExpireNode(context.Context, *ExpireNodeRequest) (*ExpireNodeResponse, error)
+	SetNodeExpiry(context.Context, *SetNodeExpiryRequest) (*SetNodeExpiryResponse, error)
+	DisableNodeExpiry(context.Context, *DisableNodeExpiryRequest) (*DisableNodeExpiryResponse, error)
+	ApproveNode(context.Context, *ApproveNodeRequest) (*ApproveNodeResponse, error)
+	RejectNode(context.Context, *RejectNodeRequest) (*RejectNodeResponse, error)
+	ListNodesOutsideIPPools(context.Context, *ListNodesOutsideIPPoolsRequest) (*ListNodesOutsideIPPoolsResponse, error)
 	RenameNode(context.Context, *RenameNodeRequest) (*RenameNodeResponse, error)
 	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
 	MoveNode(context.Context, *MoveNodeRequest) (*MoveNodeResponse, error)
+	SetNodeIP(context.Context, *SetNodeIPRequest) (*SetNodeIPResponse, error)
+	ListPendingRegistrations(context.Context, *ListPendingRegistrationsRequest) (*ListPendingRegistrationsResponse, error)
+	RejectPendingRegistration(context.Context, *RejectPendingRegistrationRequest) (*RejectPendingRegistrationResponse, error)
+	// --- IPReservation start ---
+	CreateIPReservation(context.Context, *CreateIPReservationRequest) (*CreateIPReservationResponse, error)
+	ListIPReservations(context.Context, *ListIPReservationsRequest) (*ListIPReservationsResponse, error)
+	DeleteIPReservation(context.Context, *DeleteIPReservationRequest) (*DeleteIPReservationResponse, error)
 	// --- Route start ---
 	GetRoutes(context.Context, *GetRoutesRequest) (*GetRoutesResponse, error)
 	EnableRoute(context.Context, *EnableRouteRequest) (*EnableRouteResponse, error)
@@ -349,6 +705,19 @@ type HeadscaleServiceServer interface {
 	CreateApiKey(context.Context, *CreateApiKeyRequest) (*CreateApiKeyResponse, error)
 	ExpireApiKey(context.Context, *ExpireApiKeyRequest) (*ExpireApiKeyResponse, error)
 	ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error)
+	// --- Policy start ---
+	GetPolicy(context.Context, *GetPolicyRequest) (*GetPolicyResponse, error)
+	SetPolicy(context.Context, *SetPolicyRequest) (*SetPolicyResponse, error)
+	CheckAccess(context.Context, *CheckAccessRequest) (*CheckAccessResponse, error)
+	// --- Audit start ---
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	// --- Events start ---
+	WatchEvents(*WatchEventsRequest, HeadscaleService_WatchEventsServer) error
+	// --- Database start ---
+	BackupDatabase(*BackupDatabaseRequest, HeadscaleService_BackupDatabaseServer) error
+	RestoreDatabase(HeadscaleService_RestoreDatabaseServer) error
+	ExportState(*ExportStateRequest, HeadscaleService_ExportStateServer) error
+	ImportState(HeadscaleService_ImportStateServer) error
 	mustEmbedUnimplementedHeadscaleServiceServer()
 }
 
@@ -398,6 +767,21 @@ func (UnimplementedHeadscaleServiceServer) DeleteNode(context.Context, *DeleteNo
 func (UnimplementedHeadscaleServiceServer) ExpireNode(context.Context, *ExpireNodeRequest) (*ExpireNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExpireNode not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) SetNodeExpiry(context.Context, *SetNodeExpiryRequest) (*SetNodeExpiryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNodeExpiry not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) DisableNodeExpiry(context.Context, *DisableNodeExpiryRequest) (*DisableNodeExpiryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableNodeExpiry not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ApproveNode(context.Context, *ApproveNodeRequest) (*ApproveNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveNode not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) RejectNode(context.Context, *RejectNodeRequest) (*RejectNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectNode not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListNodesOutsideIPPools(context.Context, *ListNodesOutsideIPPoolsRequest) (*ListNodesOutsideIPPoolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodesOutsideIPPools not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) RenameNode(context.Context, *RenameNodeRequest) (*RenameNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenameNode not implemented")
 }
@@ -407,6 +791,24 @@ func (UnimplementedHeadscaleServiceServer) ListNodes(context.Context, *ListNodes
 func (UnimplementedHeadscaleServiceServer) MoveNode(context.Context, *MoveNodeRequest) (*MoveNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MoveNode not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) SetNodeIP(context.Context, *SetNodeIPRequest) (*SetNodeIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNodeIP not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListPendingRegistrations(context.Context, *ListPendingRegistrationsRequest) (*ListPendingRegistrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingRegistrations not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) RejectPendingRegistration(context.Context, *RejectPendingRegistrationRequest) (*RejectPendingRegistrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectPendingRegistration not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) CreateIPReservation(context.Context, *CreateIPReservationRequest) (*CreateIPReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIPReservation not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListIPReservations(context.Context, *ListIPReservationsRequest) (*ListIPReservationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIPReservations not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) DeleteIPReservation(context.Context, *DeleteIPReservationRequest) (*DeleteIPReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteIPReservation not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) GetRoutes(context.Context, *GetRoutesRequest) (*GetRoutesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRoutes not implemented")
 }
@@ -431,6 +833,33 @@ func (UnimplementedHeadscaleServiceServer) ExpireApiKey(context.Context, *Expire
 func (UnimplementedHeadscaleServiceServer) ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) GetPolicy(context.Context, *GetPolicyRequest) (*GetPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPolicy not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) SetPolicy(context.Context, *SetPolicyRequest) (*SetPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPolicy not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) CheckAccess(context.Context, *CheckAccessRequest) (*CheckAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckAccess not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) WatchEvents(*WatchEventsRequest, HeadscaleService_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) BackupDatabase(*BackupDatabaseRequest, HeadscaleService_BackupDatabaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method BackupDatabase not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) RestoreDatabase(HeadscaleService_RestoreDatabaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method RestoreDatabase not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ExportState(*ExportStateRequest, HeadscaleService_ExportStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportState not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ImportState(HeadscaleService_ImportStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportState not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) mustEmbedUnimplementedHeadscaleServiceServer() {}
 
 // UnsafeHeadscaleServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -696,6 +1125,96 @@ func _HeadscaleService_ExpireNode_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HeadscaleService_SetNodeExpiry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNodeExpiryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetNodeExpiry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_SetNodeExpiry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetNodeExpiry(ctx, req.(*SetNodeExpiryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_DisableNodeExpiry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableNodeExpiryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).DisableNodeExpiry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_DisableNodeExpiry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).DisableNodeExpiry(ctx, req.(*DisableNodeExpiryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ApproveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ApproveNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_ApproveNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ApproveNode(ctx, req.(*ApproveNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_RejectNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).RejectNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_RejectNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).RejectNode(ctx, req.(*RejectNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ListNodesOutsideIPPools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesOutsideIPPoolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListNodesOutsideIPPools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_ListNodesOutsideIPPools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListNodesOutsideIPPools(ctx, req.(*ListNodesOutsideIPPoolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _HeadscaleService_RenameNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RenameNodeRequest)
 	if err := dec(in); err != nil {
@@ -750,6 +1269,114 @@ func _HeadscaleService_MoveNode_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HeadscaleService_SetNodeIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNodeIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetNodeIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_SetNodeIP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetNodeIP(ctx, req.(*SetNodeIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ListPendingRegistrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingRegistrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListPendingRegistrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_ListPendingRegistrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListPendingRegistrations(ctx, req.(*ListPendingRegistrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_RejectPendingRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectPendingRegistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).RejectPendingRegistration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_RejectPendingRegistration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).RejectPendingRegistration(ctx, req.(*RejectPendingRegistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_CreateIPReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIPReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).CreateIPReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_CreateIPReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).CreateIPReservation(ctx, req.(*CreateIPReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ListIPReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIPReservationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListIPReservations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_ListIPReservations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListIPReservations(ctx, req.(*ListIPReservationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_DeleteIPReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteIPReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).DeleteIPReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_DeleteIPReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).DeleteIPReservation(ctx, req.(*DeleteIPReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _HeadscaleService_GetRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetRoutesRequest)
 	if err := dec(in); err != nil {
@@ -894,6 +1521,193 @@ func _HeadscaleService_ListApiKeys_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HeadscaleService_GetPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).GetPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_GetPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).GetPolicy(ctx, req.(*GetPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_SetPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetPolicy(ctx, req.(*SetPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_CheckAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).CheckAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_CheckAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).CheckAccess(ctx, req.(*CheckAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeadscaleService_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HeadscaleServiceServer).WatchEvents(m, &headscaleServiceWatchEventsServer{stream})
+}
+
+type HeadscaleService_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type headscaleServiceWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *headscaleServiceWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _HeadscaleService_BackupDatabase_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupDatabaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HeadscaleServiceServer).BackupDatabase(m, &headscaleServiceBackupDatabaseServer{stream})
+}
+
+type HeadscaleService_BackupDatabaseServer interface {
+	Send(*BackupDatabaseResponse) error
+	grpc.ServerStream
+}
+
+type headscaleServiceBackupDatabaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *headscaleServiceBackupDatabaseServer) Send(m *BackupDatabaseResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _HeadscaleService_RestoreDatabase_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HeadscaleServiceServer).RestoreDatabase(&headscaleServiceRestoreDatabaseServer{stream})
+}
+
+type HeadscaleService_RestoreDatabaseServer interface {
+	SendAndClose(*RestoreDatabaseResponse) error
+	Recv() (*RestoreDatabaseRequest, error)
+	grpc.ServerStream
+}
+
+type headscaleServiceRestoreDatabaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *headscaleServiceRestoreDatabaseServer) SendAndClose(m *RestoreDatabaseResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *headscaleServiceRestoreDatabaseServer) Recv() (*RestoreDatabaseRequest, error) {
+	m := new(RestoreDatabaseRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _HeadscaleService_ExportState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HeadscaleServiceServer).ExportState(m, &headscaleServiceExportStateServer{stream})
+}
+
+type HeadscaleService_ExportStateServer interface {
+	Send(*ExportStateResponse) error
+	grpc.ServerStream
+}
+
+type headscaleServiceExportStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *headscaleServiceExportStateServer) Send(m *ExportStateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _HeadscaleService_ImportState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HeadscaleServiceServer).ImportState(&headscaleServiceImportStateServer{stream})
+}
+
+type HeadscaleService_ImportStateServer interface {
+	SendAndClose(*ImportStateResponse) error
+	Recv() (*ImportStateRequest, error)
+	grpc.ServerStream
+}
+
+type headscaleServiceImportStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *headscaleServiceImportStateServer) SendAndClose(m *ImportStateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *headscaleServiceImportStateServer) Recv() (*ImportStateRequest, error) {
+	m := new(ImportStateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HeadscaleService_ServiceDesc is the grpc.ServiceDesc for HeadscaleService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -957,6 +1771,26 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExpireNode",
 			Handler:    _HeadscaleService_ExpireNode_Handler,
 		},
+		{
+			MethodName: "SetNodeExpiry",
+			Handler:    _HeadscaleService_SetNodeExpiry_Handler,
+		},
+		{
+			MethodName: "DisableNodeExpiry",
+			Handler:    _HeadscaleService_DisableNodeExpiry_Handler,
+		},
+		{
+			MethodName: "ApproveNode",
+			Handler:    _HeadscaleService_ApproveNode_Handler,
+		},
+		{
+			MethodName: "RejectNode",
+			Handler:    _HeadscaleService_RejectNode_Handler,
+		},
+		{
+			MethodName: "ListNodesOutsideIPPools",
+			Handler:    _HeadscaleService_ListNodesOutsideIPPools_Handler,
+		},
 		{
 			MethodName: "RenameNode",
 			Handler:    _HeadscaleService_RenameNode_Handler,
@@ -969,6 +1803,30 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MoveNode",
 			Handler:    _HeadscaleService_MoveNode_Handler,
 		},
+		{
+			MethodName: "SetNodeIP",
+			Handler:    _HeadscaleService_SetNodeIP_Handler,
+		},
+		{
+			MethodName: "ListPendingRegistrations",
+			Handler:    _HeadscaleService_ListPendingRegistrations_Handler,
+		},
+		{
+			MethodName: "RejectPendingRegistration",
+			Handler:    _HeadscaleService_RejectPendingRegistration_Handler,
+		},
+		{
+			MethodName: "CreateIPReservation",
+			Handler:    _HeadscaleService_CreateIPReservation_Handler,
+		},
+		{
+			MethodName: "ListIPReservations",
+			Handler:    _HeadscaleService_ListIPReservations_Handler,
+		},
+		{
+			MethodName: "DeleteIPReservation",
+			Handler:    _HeadscaleService_DeleteIPReservation_Handler,
+		},
 		{
 			MethodName: "GetRoutes",
 			Handler:    _HeadscaleService_GetRoutes_Handler,
@@ -1001,7 +1859,49 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListApiKeys",
 			Handler:    _HeadscaleService_ListApiKeys_Handler,
 		},
+		{
+			MethodName: "GetPolicy",
+			Handler:    _HeadscaleService_GetPolicy_Handler,
+		},
+		{
+			MethodName: "SetPolicy",
+			Handler:    _HeadscaleService_SetPolicy_Handler,
+		},
+		{
+			MethodName: "CheckAccess",
+			Handler:    _HeadscaleService_CheckAccess_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _HeadscaleService_ListAuditEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _HeadscaleService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "BackupDatabase",
+			Handler:       _HeadscaleService_BackupDatabase_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RestoreDatabase",
+			Handler:       _HeadscaleService_RestoreDatabase_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportState",
+			Handler:       _HeadscaleService_ExportState_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportState",
+			Handler:       _HeadscaleService_ImportState_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "headscale/v1/headscale.proto",
 }
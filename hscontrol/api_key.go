@@ -0,0 +1,247 @@
+package hscontrol
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// authorizeAPIKey checks that the scopes and user limits of the API key
+// allow calling method with the given request. req is nil for streaming
+// calls.
+func (h *Headscale) authorizeAPIKey(key *types.APIKey, method string, req interface{}) error {
+	if key.HasFullAccess() {
+		return nil
+	}
+
+	method = path.Base(method)
+
+	if !key.AllowsMethod(method) {
+		log.Info().
+			Str("api_key", key.Prefix).
+			Str("method", method).
+			Msg("API key is not allowed to call method")
+
+		return status.Errorf(codes.PermissionDenied, "API key is not allowed to call %s", method)
+	}
+
+	if len(key.Users) == 0 {
+		return nil
+	}
+
+	users := h.apiRequestUsers(req)
+	if len(users) == 0 {
+		return status.Errorf(
+			codes.PermissionDenied,
+			"API key is limited to users %s, %s must be called for one of them",
+			strings.Join(key.Users, ", "),
+			method,
+		)
+	}
+
+	for _, user := range users {
+		if !key.AllowsUser(user) {
+			log.Info().
+				Str("api_key", key.Prefix).
+				Str("method", method).
+				Str("user", user).
+				Msg("API key is not allowed to access user")
+
+			return status.Errorf(
+				codes.PermissionDenied,
+				"API key is limited to users %s",
+				strings.Join(key.Users, ", "),
+			)
+		}
+	}
+
+	return nil
+}
+
+// redactAPIResponse clears the keys of the pre auth keys in the response
+// of method, unless the API key has the admin role. The key returned by
+// CreatePreAuthKey is left for its caller.
+func redactAPIResponse(key *types.APIKey, method string, resp interface{}) {
+	if key == nil || key.IsAdmin() || path.Base(method) == "CreatePreAuthKey" {
+		return
+	}
+
+	if msg, ok := resp.(proto.Message); ok && msg != nil {
+		redactPreAuthKeys(msg.ProtoReflect())
+	}
+}
+
+// redactPreAuthKeys clears the key of every pre auth key within msg.
+func redactPreAuthKeys(msg protoreflect.Message) {
+	if !msg.IsValid() {
+		return
+	}
+
+	if msg.Descriptor().FullName() == preAuthKeyDescriptor.FullName() {
+		msg.Clear(preAuthKeyDescriptor.Fields().ByName("key"))
+	}
+
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsMap():
+			if field.MapValue().Message() != nil {
+				value.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+					redactPreAuthKeys(value.Message())
+
+					return true
+				})
+			}
+		case field.Message() == nil:
+		case field.IsList():
+			list := value.List()
+			for index := 0; index < list.Len(); index++ {
+				redactPreAuthKeys(list.Get(index).Message())
+			}
+		default:
+			redactPreAuthKeys(value.Message())
+		}
+
+		return true
+	})
+}
+
+var preAuthKeyDescriptor = (&v1.PreAuthKey{}).ProtoReflect().Descriptor()
+
+// redactingServerStream redacts the messages of a streaming call made
+// with key.
+type redactingServerStream struct {
+	grpc.ServerStream
+	key    *types.APIKey
+	method string
+}
+
+func (stream *redactingServerStream) SendMsg(msg interface{}) error {
+	redactAPIResponse(stream.key, stream.method, msg)
+
+	return stream.ServerStream.SendMsg(msg)
+}
+
+// apiRequestUsers returns the users owning the objects the request refers
+// to. It returns nil if the request is not limited to specific users,
+// like listing all nodes.
+func (h *Headscale) apiRequestUsers(req interface{}) []string {
+	switch request := req.(type) {
+	case *v1.GetUserRequest:
+		return []string{request.GetName()}
+	case *v1.CreateUserRequest:
+		return []string{request.GetName()}
+	case *v1.DeleteUserRequest:
+		return []string{request.GetName()}
+	case *v1.RenameUserRequest:
+		return []string{request.GetOldName(), request.GetNewName()}
+	}
+
+	users := []string{}
+
+	if request, ok := req.(interface{ GetUser() string }); ok {
+		if request.GetUser() == "" {
+			return nil
+		}
+
+		users = append(users, request.GetUser())
+	}
+
+	if request, ok := req.(interface{ GetNodeId() uint64 }); ok {
+		node, err := h.db.GetNodeByID(request.GetNodeId())
+		if err != nil {
+			return nil
+		}
+
+		users = append(users, node.User.Name)
+	}
+
+	if request, ok := req.(interface{ GetRouteId() uint64 }); ok {
+		route, err := h.db.GetRoute(request.GetRouteId())
+		if err != nil {
+			return nil
+		}
+
+		users = append(users, route.Node.User.Name)
+	}
+
+	return users
+}
+
+// forwardedAPIKey returns the API key of a request forwarded by the
+// grpc-gateway over the unix socket. The key has already been validated
+// by httpAuthenticationMiddleware, nil is returned for requests made
+// directly on the socket.
+func (h *Headscale) forwardedAPIKey(ctx context.Context) (*types.APIKey, error) {
+	meta, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(meta["authorization"]) == 0 {
+		return nil, nil
+	}
+
+	token := strings.TrimPrefix(meta["authorization"][0], AuthPrefix)
+	prefix, _, _ := strings.Cut(token, ".")
+
+	key, err := h.db.GetAPIKey(prefix)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return key, nil
+}
+
+// grpcSocketAuthorizationInterceptor enforces the scope of API keys
+// used with the HTTP API, which reaches the gRPC server over the
+// unix socket.
+func (h *Headscale) grpcSocketAuthorizationInterceptor(ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	key, err := h.forwardedAPIKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != nil {
+		if err := h.authorizeAPIKey(key, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	redactAPIResponse(key, info.FullMethod, resp)
+
+	return resp, nil
+}
+
+func (h *Headscale) grpcSocketStreamAuthorizationInterceptor(srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	key, err := h.forwardedAPIKey(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	if key != nil {
+		if err := h.authorizeAPIKey(key, info.FullMethod, nil); err != nil {
+			return err
+		}
+	}
+
+	return handler(srv, &redactingServerStream{stream, key, info.FullMethod})
+}
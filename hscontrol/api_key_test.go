@@ -0,0 +1,153 @@
+package hscontrol
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/check.v1"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+)
+
+func (s *Suite) TestAuthorizeAPIKey(c *check.C) {
+	_, err := app.db.CreateUser("ci")
+	c.Assert(err, check.IsNil)
+
+	prod, err := app.db.CreateUser("prod")
+	c.Assert(err, check.IsNil)
+
+	node, err := app.db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "router",
+		UserID:         prod.ID,
+		RegisterMethod: util.RegisterMethodCLI,
+		Hostinfo: &tailcfg.Hostinfo{
+			RoutableIPs: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")},
+		},
+	})
+	c.Assert(err, check.IsNil)
+
+	_, err = app.db.SaveNodeRoutes(node)
+	c.Assert(err, check.IsNil)
+
+	routes, err := app.db.GetNodeRoutes(node)
+	c.Assert(err, check.IsNil)
+	c.Assert(routes, check.HasLen, 1)
+
+	issuer := &types.APIKey{
+		Scopes: types.StringList{types.APIKeyRolePreAuthKeyIssuer},
+		Users:  types.StringList{"ci"},
+	}
+	approver := &types.APIKey{
+		Scopes: types.StringList{types.APIKeyRoleRouteApprover},
+		Users:  types.StringList{"prod"},
+	}
+
+	tests := []struct {
+		key    *types.APIKey
+		method string
+		req    interface{}
+		code   codes.Code
+	}{
+		{&types.APIKey{}, "DeleteUser", &v1.DeleteUserRequest{Name: "prod"}, codes.OK},
+		{issuer, "CreatePreAuthKey", &v1.CreatePreAuthKeyRequest{User: "ci"}, codes.OK},
+		{issuer, "CreatePreAuthKey", &v1.CreatePreAuthKeyRequest{User: "prod"}, codes.PermissionDenied},
+		{issuer, "ListPreAuthKeys", &v1.ListPreAuthKeysRequest{}, codes.PermissionDenied},
+		{issuer, "DeleteUser", &v1.DeleteUserRequest{Name: "ci"}, codes.PermissionDenied},
+		{issuer, "CreateApiKey", &v1.CreateApiKeyRequest{}, codes.PermissionDenied},
+		{approver, "EnableRoute", &v1.EnableRouteRequest{RouteId: uint64(routes[0].ID)}, codes.OK},
+		{approver, "GetNodeRoutes", &v1.GetNodeRoutesRequest{NodeId: node.ID}, codes.OK},
+		{approver, "GetRoutes", &v1.GetRoutesRequest{}, codes.PermissionDenied},
+		{approver, "DeleteRoute", &v1.DeleteRouteRequest{RouteId: uint64(routes[0].ID)}, codes.PermissionDenied},
+		{issuer, "GetNodeRoutes", &v1.GetNodeRoutesRequest{NodeId: node.ID}, codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		err := app.authorizeAPIKey(tt.key, "/headscale.v1.HeadscaleService/"+tt.method, tt.req)
+		c.Check(status.Code(err), check.Equals, tt.code, check.Commentf("%s %v", tt.method, tt.req))
+	}
+}
+
+func (s *Suite) TestReadOnlyAPIKeyDoesNotSeePreAuthKeys(c *check.C) {
+	user, err := app.db.CreateUser("ci")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.db.CreatePreAuthKey(user.Name, true, false, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	_, err = app.db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "ci-1",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		AuthKeyID:      uint(pak.ID),
+	})
+	c.Assert(err, check.IsNil)
+
+	readOnly, _, err := app.db.CreateAPIKey(nil, []string{types.APIKeyRoleReadOnly}, nil)
+	c.Assert(err, check.IsNil)
+
+	admin, _, err := app.db.CreateAPIKey(nil, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	api := newHeadscaleV1APIServer(app)
+
+	call := func(apiKey string, method string, req interface{}) string {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("authorization", AuthPrefix+apiKey),
+		)
+
+		resp, err := app.grpcSocketAuthorizationInterceptor(
+			ctx,
+			req,
+			&grpc.UnaryServerInfo{FullMethod: "/headscale.v1.HeadscaleService/" + method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				switch req := req.(type) {
+				case *v1.ListPreAuthKeysRequest:
+					return api.ListPreAuthKeys(ctx, req)
+				case *v1.ListNodesRequest:
+					return api.ListNodes(ctx, req)
+				}
+
+				return nil, nil
+			},
+		)
+		c.Assert(err, check.IsNil)
+
+		out, err := protojson.Marshal(resp.(proto.Message))
+		c.Assert(err, check.IsNil)
+
+		return string(out)
+	}
+
+	for method, req := range map[string]interface{}{
+		"ListPreAuthKeys": &v1.ListPreAuthKeysRequest{User: "ci"},
+		"ListNodes":       &v1.ListNodesRequest{},
+	} {
+		c.Assert(
+			strings.Contains(call(readOnly, method, req), pak.Key),
+			check.Equals,
+			false,
+			check.Commentf("%s with a read-only key", method),
+		)
+		c.Assert(
+			strings.Contains(call(admin, method, req), pak.Key),
+			check.Equals,
+			true,
+			check.Commentf("%s with an admin key", method),
+		)
+	}
+}
@@ -9,12 +9,14 @@ import (
 	"net"
 	"net/http"
 	_ "net/http/pprof" //nolint
+	"net/netip"
 	"os"
 	"os/signal"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -24,18 +26,24 @@ import (
 	grpcRuntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/juanfont/headscale"
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/cluster"
 	"github.com/juanfont/headscale/hscontrol/db"
 	"github.com/juanfont/headscale/hscontrol/derp"
 	derpServer "github.com/juanfont/headscale/hscontrol/derp/server"
+	"github.com/juanfont/headscale/hscontrol/events"
+	"github.com/juanfont/headscale/hscontrol/leader"
 	"github.com/juanfont/headscale/hscontrol/notifier"
 	"github.com/juanfont/headscale/hscontrol/policy"
+	"github.com/juanfont/headscale/hscontrol/tracing"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
-	"github.com/patrickmn/go-cache"
 	zerolog "github.com/philip-bui/grpc-zerolog"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	zl "github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/crypto/acme"
 	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/oauth2"
@@ -72,11 +80,21 @@ const (
 
 	registerCacheExpiration = time.Minute * 15
 	registerCacheCleanup    = time.Minute * 20
+
+	// expiryNotifyInterval is how often the owners of the nodes
+	// expiring soon are notified, and the failed notifications retried.
+	expiryNotifyInterval = time.Minute
+	// expiryNotifyTimeout bounds the delivery of a notification.
+	expiryNotifyTimeout = 30 * time.Second
+
+	leaderHolderSuffixLength = 8
 )
 
 // Headscale represents the base app of the service.
 type Headscale struct {
-	cfg             *types.Config
+	// cfg is the active configuration, read through config as it is
+	// replaced when the configuration is reloaded.
+	cfg             atomic.Pointer[types.Config]
 	db              *db.HSDatabase
 	dbString        string
 	dbType          string
@@ -86,14 +104,32 @@ type Headscale struct {
 	DERPMap    *tailcfg.DERPMap
 	DERPServer *derpServer.DERPServer
 
-	ACLPolicy *policy.ACLPolicy
+	// aclPolicy is the active ACL policy, read through ACLPolicy as it
+	// is replaced when the policy is reloaded.
+	aclPolicy atomic.Pointer[policy.ACLPolicy]
+
+	// aclPolicyLock is held while the ACL policy is loaded and replaced,
+	// so the file watcher, SIGHUP, the cluster and SetPolicy can't
+	// interleave their updates.
+	aclPolicyLock sync.Mutex
 
 	nodeNotifier *notifier.Notifier
+	events       *events.Broker
+	cluster      *cluster.Cluster
+	leader       leader.Elector
 
 	oidcProvider *oidc.Provider
 	oauth2Config *oauth2.Config
 
-	registrationCache *cache.Cache
+	auditSink *auditSink
+
+	// aclPolicyWatcher watches the ACL policy file, nil if it is not
+	// watched.
+	aclPolicyWatcher *policyFileWatcher
+
+	// expiryNotifyLock is held while the owners of the nodes expiring
+	// soon are notified.
+	expiryNotifyLock sync.Mutex
 
 	shutdownChan       chan struct{}
 	pollNetMapStreamWG sync.WaitGroup
@@ -106,6 +142,8 @@ var (
 	tailsqlTSKey     = envknob.String("TS_AUTHKEY")
 )
 
+var tracer = otel.Tracer("github.com/juanfont/headscale/hscontrol")
+
 func NewHeadscale(cfg *types.Config) (*Headscale, error) {
 	if profilingEnabled {
 		runtime.SetBlockProfileRate(1)
@@ -116,58 +154,31 @@ func NewHeadscale(cfg *types.Config) (*Headscale, error) {
 		return nil, fmt.Errorf("failed to read or create Noise protocol private key: %w", err)
 	}
 
-	var dbString string
-	switch cfg.DBtype {
-	case db.Postgres:
-		dbString = fmt.Sprintf(
-			"host=%s dbname=%s user=%s",
-			cfg.DBhost,
-			cfg.DBname,
-			cfg.DBuser,
-		)
-
-		if sslEnabled, err := strconv.ParseBool(cfg.DBssl); err == nil {
-			if !sslEnabled {
-				dbString += " sslmode=disable"
-			}
-		} else {
-			dbString += fmt.Sprintf(" sslmode=%s", cfg.DBssl)
-		}
-
-		if cfg.DBport != 0 {
-			dbString += fmt.Sprintf(" port=%d", cfg.DBport)
-		}
-
-		if cfg.DBpass != "" {
-			dbString += fmt.Sprintf(" password=%s", cfg.DBpass)
-		}
-	case db.Sqlite:
-		dbString = cfg.DBpath
-	default:
-		return nil, errUnsupportedDatabase
+	dbString, err := DatabaseConnectionString(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	registrationCache := cache.New(
-		registerCacheExpiration,
-		registerCacheCleanup,
-	)
-
 	app := Headscale{
-		cfg:                cfg,
 		dbType:             cfg.DBtype,
 		dbString:           dbString,
 		noisePrivateKey:    noisePrivateKey,
-		registrationCache:  registrationCache,
 		pollNetMapStreamWG: sync.WaitGroup{},
 		nodeNotifier:       notifier.NewNotifier(),
+		events:             events.NewBroker(),
 	}
 
+	app.cfg.Store(cfg)
+
 	database, err := db.NewHeadscaleDatabase(
 		cfg.DBtype,
 		dbString,
 		app.dbDebug,
 		app.nodeNotifier,
+		app.events,
 		cfg.IPPrefixes,
+		cfg.IPPools,
+		cfg.IPAllocation,
 		cfg.BaseDomain)
 	if err != nil {
 		return nil, err
@@ -175,6 +186,46 @@ func NewHeadscale(cfg *types.Config) (*Headscale, error) {
 
 	app.db = database
 
+	// Processes sharing the database, replicas or the two instances of
+	// a blue/green deployment, need distinct holders.
+	suffix, err := util.GenerateRandomStringDNSSafe(leaderHolderSuffixLength)
+	if err != nil {
+		return nil, err
+	}
+	app.leader = leader.New(cfg.DBtype, database, cfg.HA.ReplicaName+"-"+suffix)
+
+	if cfg.HA.Enabled {
+		app.cluster = cluster.New(cfg.HA, dbString, database, app.nodeNotifier)
+		app.cluster.OnPolicyChanged = func() {
+			if app.config().ACL.PolicyMode == types.PolicyModeDB {
+				_ = app.reloadACLPolicy("cluster")
+			}
+		}
+		app.cluster.OnDERPMapChanged = app.refreshDERPMap
+		app.cluster.OnNodeLost = func(node *types.Node) {
+			app.updateNodeOnlineStatus(context.Background(), false, node)
+
+			err := app.db.FailoverNodeRoutesWithNotify(node)
+			if err != nil {
+				log.Error().Err(err).Str("node", node.Hostname).Msg("Failed to fail over routes of lost node")
+			}
+		}
+		app.nodeNotifier.SetCluster(app.cluster)
+	}
+
+	if cfg.Audit.LogPath != "" {
+		app.auditSink, err = newAuditSink(cfg.Audit.LogPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = app.loadACLPolicy()
+	if err != nil {
+		return nil, err
+	}
+	aclPolicyLastReloadSuccessful.Set(1)
+
 	if cfg.OIDC.Issuer != "" {
 		err = app.initOIDC()
 		if err != nil {
@@ -186,16 +237,7 @@ func NewHeadscale(cfg *types.Config) (*Headscale, error) {
 		}
 	}
 
-	if app.cfg.DNSConfig != nil && app.cfg.DNSConfig.Proxied { // if MagicDNS
-		magicDNSDomains := util.GenerateMagicDNSRootDomains(app.cfg.IPPrefixes)
-		// we might have routes already from Split DNS
-		if app.cfg.DNSConfig.Routes == nil {
-			app.cfg.DNSConfig.Routes = make(map[string][]*dnstype.Resolver)
-		}
-		for _, d := range magicDNSDomains {
-			app.cfg.DNSConfig.Routes[d.WithoutTrailingDot()] = nil
-		}
-	}
+	addMagicDNSRoutes(app.config().DNSConfig, app.config().IPPrefixes)
 
 	if cfg.DERP.ServerEnabled {
 		derpServerKey, err := readOrCreatePrivateKey(cfg.DERP.ServerPrivateKeyPath)
@@ -224,31 +266,160 @@ func NewHeadscale(cfg *types.Config) (*Headscale, error) {
 	return &app, nil
 }
 
+// DatabaseConnectionString returns the connection string of the database
+// configured in cfg.
+func DatabaseConnectionString(cfg *types.Config) (string, error) {
+	var dbString string
+	switch cfg.DBtype {
+	case db.Postgres:
+		dbString = fmt.Sprintf(
+			"host=%s dbname=%s user=%s",
+			cfg.DBhost,
+			cfg.DBname,
+			cfg.DBuser,
+		)
+
+		if sslEnabled, err := strconv.ParseBool(cfg.DBssl); err == nil {
+			if !sslEnabled {
+				dbString += " sslmode=disable"
+			}
+		} else {
+			dbString += fmt.Sprintf(" sslmode=%s", cfg.DBssl)
+		}
+
+		if cfg.DBport != 0 {
+			dbString += fmt.Sprintf(" port=%d", cfg.DBport)
+		}
+
+		if cfg.DBpass != "" {
+			dbString += fmt.Sprintf(" password=%s", cfg.DBpass)
+		}
+	case db.Sqlite:
+		dbString = cfg.DBpath
+	default:
+		return "", errUnsupportedDatabase
+	}
+
+	return dbString, nil
+}
+
+// addMagicDNSRoutes adds the reverse DNS domains of the IP prefixes to
+// the routes of the DNS configuration when MagicDNS is enabled.
+func addMagicDNSRoutes(dnsConfig *tailcfg.DNSConfig, prefixes []netip.Prefix) {
+	if dnsConfig != nil && dnsConfig.Proxied { // if MagicDNS
+		magicDNSDomains := util.GenerateMagicDNSRootDomains(prefixes)
+		// we might have routes already from Split DNS
+		if dnsConfig.Routes == nil {
+			dnsConfig.Routes = make(map[string][]*dnstype.Resolver)
+		}
+		for _, d := range magicDNSDomains {
+			dnsConfig.Routes[d.WithoutTrailingDot()] = nil
+		}
+	}
+}
+
 // Redirect to our TLS url.
 func (h *Headscale) redirect(w http.ResponseWriter, req *http.Request) {
-	target := h.cfg.ServerURL + req.URL.RequestURI()
+	target := h.config().ServerURL + req.URL.RequestURI()
 	http.Redirect(w, req, target, http.StatusFound)
 }
 
 // expireEphemeralNodes deletes ephemeral node records that have not been
-// seen for longer than h.cfg.EphemeralNodeInactivityTimeout.
+// seen for longer than h.config().EphemeralNodeInactivityTimeout.
 func (h *Headscale) expireEphemeralNodes(milliSeconds int64) {
 	ticker := time.NewTicker(time.Duration(milliSeconds) * time.Millisecond)
 	for range ticker.C {
-		h.db.ExpireEphemeralNodes(h.cfg.EphemeralNodeInactivityTimeout)
+		if !h.leader.IsLeader() {
+			continue
+		}
+
+		h.db.ExpireEphemeralNodes(h.config().EphemeralNodeInactivityTimeout)
 	}
 }
 
 // expireExpiredMachines expires nodes that have an explicit expiry set
-// after that expiry time has passed.
+// after that expiry time has passed, sets the expiry of the nodes
+// without one from the node_expiry policy and notifies the owners of the
+// nodes expiring soon.
 func (h *Headscale) expireExpiredMachines(intervalMs int64) {
 	interval := time.Duration(intervalMs) * time.Millisecond
 	ticker := time.NewTicker(interval)
 
 	lastCheck := time.Unix(0, 0)
+	lastNotify := time.Unix(0, 0)
+
+	for range ticker.C {
+		if !h.leader.IsLeader() {
+			continue
+		}
+
+		lastCheck = h.db.ExpireExpiredNodes(lastCheck, h.config().NodeExpiry, h.ACLPolicy())
+
+		if time.Since(lastNotify) >= expiryNotifyInterval {
+			lastNotify = time.Now()
+
+			go h.notifyExpiringNodes(
+				context.Background(),
+				events.ExpiryNotifiers(h.config().NodeExpiry.Notify),
+			)
+		}
+	}
+}
+
+// notifyExpiringNodes tells the owners of the nodes whose key expires
+// soon through notifiers. The notification is only recorded once every
+// notifier delivered it, the others are sent again on the next check
+// until the node expires. It returns right away if the previous check is
+// still sending notifications.
+func (h *Headscale) notifyExpiringNodes(ctx context.Context, notifiers []events.ExpiryNotifier) {
+	if !h.expiryNotifyLock.TryLock() {
+		return
+	}
+	defer h.expiryNotifyLock.Unlock()
+
+	nodes, err := h.db.ListExpiringNodes(h.config().NodeExpiry.Notify.Before)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list the nodes expiring soon")
+
+		return
+	}
+
+	for _, node := range nodes {
+		notifyCtx, cancel := context.WithTimeout(ctx, expiryNotifyTimeout)
+		err := events.NotifyExpiring(notifyCtx, notifiers, events.Event{
+			Type: events.NodeExpiring,
+			Time: time.Now(),
+			Node: node,
+		})
+		cancel()
 
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("node", node.Hostname).
+				Msg("Failed to notify node expiry, retrying on the next check")
+
+			continue
+		}
+
+		if err := h.db.SetNodeExpiryNotified(node); err != nil {
+			log.Error().Err(err).Str("node", node.Hostname).Msg("Failed to record node expiry notification")
+		}
+	}
+}
+
+// expirePendingRegistrations removes pending registrations that have not
+// been completed before their expiry.
+func (h *Headscale) expirePendingRegistrations(interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	for range ticker.C {
-		lastCheck = h.db.ExpireExpiredNodes(lastCheck)
+		if !h.leader.IsLeader() {
+			continue
+		}
+
+		if err := h.db.DeleteExpiredPendingRegistrations(); err != nil {
+			log.Error().Err(err).Msg("Failed to delete expired pending registrations")
+		}
 	}
 }
 
@@ -256,9 +427,9 @@ func (h *Headscale) expireExpiredMachines(intervalMs int64) {
 // at a set interval.
 func (h *Headscale) scheduledDERPMapUpdateWorker(cancelChan <-chan struct{}) {
 	log.Info().
-		Dur("frequency", h.cfg.DERP.UpdateFrequency).
+		Dur("frequency", h.config().DERP.UpdateFrequency).
 		Msg("Setting up a DERPMap update worker")
-	ticker := time.NewTicker(h.cfg.DERP.UpdateFrequency)
+	ticker := time.NewTicker(h.config().DERP.UpdateFrequency)
 
 	for {
 		select {
@@ -266,29 +437,90 @@ func (h *Headscale) scheduledDERPMapUpdateWorker(cancelChan <-chan struct{}) {
 			return
 
 		case <-ticker.C:
-			log.Info().Msg("Fetching DERPMap updates")
-			h.DERPMap = derp.GetDERPMap(h.cfg.DERP)
-			if h.cfg.DERP.ServerEnabled && h.cfg.DERP.AutomaticallyAddEmbeddedDerpRegion {
-				region, _ := h.DERPServer.GenerateRegion()
-				h.DERPMap.Regions[region.RegionID] = &region
-			}
-
-			stateUpdate := types.StateUpdate{
-				Type:    types.StateDERPUpdated,
-				DERPMap: h.DERPMap,
-			}
-			if stateUpdate.Valid() {
-				h.nodeNotifier.NotifyAll(stateUpdate)
+			h.refreshDERPMap()
+
+			// The other replicas fetch the DERPMap when the leader
+			// tells them, so they do not wait for their own ticker.
+			if h.cluster != nil && h.leader.IsLeader() {
+				h.cluster.Publish(types.StateUpdate{
+					Type: types.StateDERPUpdated,
+				}, nil, nil)
 			}
 		}
 	}
 }
 
+// refreshDERPMap fetches the DERPMap and sends it to the nodes connected
+// to this replica.
+func (h *Headscale) refreshDERPMap() {
+	log.Info().Msg("Fetching DERPMap updates")
+	h.DERPMap = h.fetchDERPMap(h.config().DERP)
+
+	stateUpdate := types.StateUpdate{
+		Type:    types.StateDERPUpdated,
+		DERPMap: h.DERPMap,
+	}
+	if stateUpdate.Valid() {
+		h.nodeNotifier.NotifyLocalWithIgnore(context.Background(), stateUpdate)
+	}
+}
+
+// fetchDERPMap builds the DERPMap from the given configuration, including
+// the region of the embedded DERP server if it is enabled.
+func (h *Headscale) fetchDERPMap(cfg types.DERPConfig) *tailcfg.DERPMap {
+	derpMap := derp.GetDERPMap(cfg)
+	if cfg.ServerEnabled && cfg.AutomaticallyAddEmbeddedDerpRegion {
+		region, _ := h.DERPServer.GenerateRegion()
+		derpMap.Regions[region.RegionID] = &region
+	}
+
+	return derpMap
+}
+
 func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 	req interface{},
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
+	key, err := h.grpcAuthenticate(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	if err := h.authorizeAPIKey(key, info.FullMethod, req); err != nil {
+		return ctx, err
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	redactAPIResponse(key, info.FullMethod, resp)
+
+	return resp, nil
+}
+
+func (h *Headscale) grpcStreamAuthenticationInterceptor(srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	key, err := h.grpcAuthenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorizeAPIKey(key, info.FullMethod, nil); err != nil {
+		return err
+	}
+
+	return handler(srv, &redactingServerStream{stream, key, info.FullMethod})
+}
+
+// grpcAuthenticate validates the API key carried in the metadata of a
+// remote gRPC call and returns it.
+func (h *Headscale) grpcAuthenticate(ctx context.Context) (*types.APIKey, error) {
 	// Check if the request is coming from the on-server client.
 	// This is not secure, but it is to maintain maintainability
 	// with the "legacy" database-based client
@@ -308,7 +540,7 @@ func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 			Str("client_address", client.Addr.String()).
 			Msg("Retrieving metadata is failed")
 
-		return ctx, status.Errorf(
+		return nil, status.Errorf(
 			codes.InvalidArgument,
 			"Retrieving metadata is failed",
 		)
@@ -321,7 +553,7 @@ func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 			Str("client_address", client.Addr.String()).
 			Msg("Authorization token is not supplied")
 
-		return ctx, status.Errorf(
+		return nil, status.Errorf(
 			codes.Unauthenticated,
 			"Authorization token is not supplied",
 		)
@@ -335,13 +567,13 @@ func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 			Str("client_address", client.Addr.String()).
 			Msg(`missing "Bearer " prefix in "Authorization" header`)
 
-		return ctx, status.Error(
+		return nil, status.Error(
 			codes.Unauthenticated,
 			`missing "Bearer " prefix in "Authorization" header`,
 		)
 	}
 
-	valid, err := h.db.ValidateAPIKey(strings.TrimPrefix(token, AuthPrefix))
+	key, err := h.db.AuthenticateAPIKey(strings.TrimPrefix(token, AuthPrefix))
 	if err != nil {
 		log.Error().
 			Caller().
@@ -349,18 +581,18 @@ func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 			Str("client_address", client.Addr.String()).
 			Msg("failed to validate token")
 
-		return ctx, status.Error(codes.Internal, "failed to validate token")
+		return nil, status.Error(codes.Internal, "failed to validate token")
 	}
 
-	if !valid {
+	if key == nil {
 		log.Info().
 			Str("client_address", client.Addr.String()).
 			Msg("invalid token")
 
-		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
 
-	return handler(ctx, req)
+	return key, nil
 }
 
 func (h *Headscale) httpAuthenticationMiddleware(next http.Handler) http.Handler {
@@ -429,6 +661,9 @@ func (h *Headscale) httpAuthenticationMiddleware(next http.Handler) http.Handler
 			return
 		}
 
+		// The gateway forwards the authorization header to the gRPC
+		// server, where the scope of the key is enforced by
+		// grpcSocketAuthorizationInterceptor.
 		next.ServeHTTP(writer, req)
 	})
 }
@@ -437,15 +672,18 @@ func (h *Headscale) httpAuthenticationMiddleware(next http.Handler) http.Handler
 // and will remove it if it is not.
 func (h *Headscale) ensureUnixSocketIsAbsent() error {
 	// File does not exist, all fine
-	if _, err := os.Stat(h.cfg.UnixSocket); errors.Is(err, os.ErrNotExist) {
+	if _, err := os.Stat(h.config().UnixSocket); errors.Is(err, os.ErrNotExist) {
 		return nil
 	}
 
-	return os.Remove(h.cfg.UnixSocket)
+	return os.Remove(h.config().UnixSocket)
 }
 
 func (h *Headscale) createRouter(grpcMux *grpcRuntime.ServeMux) *mux.Router {
 	router := mux.NewRouter()
+	// The Noise and DERP connections are upgraded and kept open, the
+	// Noise requests are traced on their own router.
+	router.Use(tracing.Middleware("headscale", ts2021UpgradePath, "/derp"))
 	router.PathPrefix("/debug/pprof/").Handler(http.DefaultServeMux)
 
 	router.HandleFunc(ts2021UpgradePath, h.NoiseUpgradeHandler).Methods(http.MethodPost)
@@ -468,7 +706,7 @@ func (h *Headscale) createRouter(grpcMux *grpcRuntime.ServeMux) *mux.Router {
 	router.HandleFunc("/swagger/v1/openapiv2.json", headscale.SwaggerAPIv1).
 		Methods(http.MethodGet)
 
-	if h.cfg.DERP.ServerEnabled {
+	if h.config().DERP.ServerEnabled {
 		router.HandleFunc("/derp", h.DERPServer.DERPHandler)
 		router.HandleFunc("/derp/probe", derpServer.DERPProbeHandler)
 		router.HandleFunc("/bootstrap-dns", derpServer.DERPBootstrapDNSHandler(h.DERPMap))
@@ -488,11 +726,11 @@ func (h *Headscale) Serve() error {
 	var err error
 
 	// Fetch an initial DERP Map before we start serving
-	h.DERPMap = derp.GetDERPMap(h.cfg.DERP)
+	h.DERPMap = derp.GetDERPMap(h.config().DERP)
 
-	if h.cfg.DERP.ServerEnabled {
+	if h.config().DERP.ServerEnabled {
 		// When embedded DERP is enabled we always need a STUN server
-		if h.cfg.DERP.STUNAddr == "" {
+		if h.config().DERP.STUNAddr == "" {
 			return errSTUNAddressNotSet
 		}
 
@@ -501,14 +739,14 @@ func (h *Headscale) Serve() error {
 			return err
 		}
 
-		if h.cfg.DERP.AutomaticallyAddEmbeddedDerpRegion {
+		if h.config().DERP.AutomaticallyAddEmbeddedDerpRegion {
 			h.DERPMap.Regions[region.RegionID] = &region
 		}
 
 		go h.DERPServer.ServeSTUN()
 	}
 
-	if h.cfg.DERP.AutoUpdate {
+	if h.config().DERP.AutoUpdate {
 		derpMapCancelChannel := make(chan struct{})
 		defer func() { derpMapCancelChannel <- struct{}{} }()
 		go h.scheduledDERPMapUpdateWorker(derpMapCancelChannel)
@@ -522,6 +760,7 @@ func (h *Headscale) Serve() error {
 	// up on shutdown.
 	go h.expireEphemeralNodes(updateInterval)
 	go h.expireExpiredMachines(updateInterval)
+	go h.expirePendingRegistrations(registerCacheCleanup)
 
 	if zl.GlobalLevel() == zl.TraceLevel {
 		zerolog.RespLog = true
@@ -529,6 +768,11 @@ func (h *Headscale) Serve() error {
 		zerolog.RespLog = false
 	}
 
+	traces, err := tracing.Setup(h.config().Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+
 	// Prepare group for running listeners
 	errorGroup := new(errgroup.Group)
 
@@ -536,6 +780,39 @@ func (h *Headscale) Serve() error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	if h.cluster != nil {
+		err = h.cluster.Start(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to join the cluster: %w", err)
+		}
+	}
+
+	go h.leader.Run(ctx, func(isLeader bool) {
+		if isLeader {
+			leaderStatus.Set(1)
+		} else {
+			leaderStatus.Set(0)
+		}
+	})
+
+	for _, webhookCfg := range h.config().Webhooks {
+		log.Info().
+			Str("url", webhookCfg.URL).
+			Strs("events", webhookCfg.Events).
+			Msg("Delivering events to webhook")
+
+		go events.NewWebhook(webhookCfg).Run(ctx, h.events)
+	}
+
+	if h.config().ACL.WatchPolicyFile &&
+		h.config().ACL.PolicyMode != types.PolicyModeDB &&
+		h.config().ACL.PolicyPath != "" {
+		err = h.watchACLPolicyFile(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	//
 	//
 	// Set up LOCAL listeners
@@ -546,13 +823,13 @@ func (h *Headscale) Serve() error {
 		return fmt.Errorf("unable to remove old socket file: %w", err)
 	}
 
-	socketListener, err := net.Listen("unix", h.cfg.UnixSocket)
+	socketListener, err := net.Listen("unix", h.config().UnixSocket)
 	if err != nil {
 		return fmt.Errorf("failed to set up gRPC socket: %w", err)
 	}
 
 	// Change socket permissions
-	if err := os.Chmod(h.cfg.UnixSocket, h.cfg.UnixSocketPermission); err != nil {
+	if err := os.Chmod(h.config().UnixSocket, h.config().UnixSocketPermission); err != nil {
 		return fmt.Errorf("failed change permission of gRPC socket: %w", err)
 	}
 
@@ -560,10 +837,11 @@ func (h *Headscale) Serve() error {
 
 	// Make the grpc-gateway connect to grpc over socket
 	grpcGatewayConn, err := grpc.Dial(
-		h.cfg.UnixSocket,
+		h.config().UnixSocket,
 		[]grpc.DialOption{
 			grpc.WithTransportCredentials(insecure.NewCredentials()),
 			grpc.WithContextDialer(util.GrpcSocketDialer),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		}...,
 	)
 	if err != nil {
@@ -577,10 +855,15 @@ func (h *Headscale) Serve() error {
 		return err
 	}
 
-	// Start the local gRPC server without TLS and without authentication
+	// Start the local gRPC server without TLS and without authentication,
+	// requests forwarded from the HTTP API are limited to the scope
+	// of their API key.
 	grpcSocket := grpc.NewServer(
-	// Uncomment to debug grpc communication.
-	// zerolog.UnaryInterceptor(),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(h.grpcSocketAuthorizationInterceptor),
+		grpc.StreamInterceptor(h.grpcSocketStreamAuthorizationInterceptor),
+		// Uncomment to debug grpc communication.
+		// zerolog.UnaryInterceptor(),
 	)
 
 	v1.RegisterHeadscaleServiceServer(grpcSocket, newHeadscaleV1APIServer(h))
@@ -614,10 +897,11 @@ func (h *Headscale) Serve() error {
 
 	var grpcServer *grpc.Server
 	var grpcListener net.Listener
-	if tlsConfig != nil || h.cfg.GRPCAllowInsecure {
-		log.Info().Msgf("Enabling remote gRPC at %s", h.cfg.GRPCAddr)
+	if tlsConfig != nil || h.config().GRPCAllowInsecure {
+		log.Info().Msgf("Enabling remote gRPC at %s", h.config().GRPCAddr)
 
 		grpcOptions := []grpc.ServerOption{
+			grpc.StatsHandler(otelgrpc.NewServerHandler()),
 			grpc.UnaryInterceptor(
 				grpcMiddleware.ChainUnaryServer(
 					h.grpcAuthenticationInterceptor,
@@ -625,6 +909,7 @@ func (h *Headscale) Serve() error {
 					// zerolog.NewUnaryServerInterceptor(),
 				),
 			),
+			grpc.StreamInterceptor(h.grpcStreamAuthenticationInterceptor),
 		}
 
 		if tlsConfig != nil {
@@ -640,7 +925,7 @@ func (h *Headscale) Serve() error {
 		v1.RegisterHeadscaleServiceServer(grpcServer, newHeadscaleV1APIServer(h))
 		reflection.Register(grpcServer)
 
-		grpcListener, err = net.Listen("tcp", h.cfg.GRPCAddr)
+		grpcListener, err = net.Listen("tcp", h.config().GRPCAddr)
 		if err != nil {
 			return fmt.Errorf("failed to bind to TCP address: %w", err)
 		}
@@ -648,7 +933,7 @@ func (h *Headscale) Serve() error {
 		errorGroup.Go(func() error { return grpcServer.Serve(grpcListener) })
 
 		log.Info().
-			Msgf("listening and serving gRPC on: %s", h.cfg.GRPCAddr)
+			Msgf("listening and serving gRPC on: %s", h.config().GRPCAddr)
 	}
 
 	//
@@ -660,7 +945,7 @@ func (h *Headscale) Serve() error {
 	router := h.createRouter(grpcGatewayMux)
 
 	httpServer := &http.Server{
-		Addr:        h.cfg.Addr,
+		Addr:        h.config().Addr,
 		Handler:     router,
 		ReadTimeout: types.HTTPReadTimeout,
 		// Go does not handle timeouts in HTTP very well, and there is
@@ -673,9 +958,9 @@ func (h *Headscale) Serve() error {
 	var httpListener net.Listener
 	if tlsConfig != nil {
 		httpServer.TLSConfig = tlsConfig
-		httpListener, err = tls.Listen("tcp", h.cfg.Addr, tlsConfig)
+		httpListener, err = tls.Listen("tcp", h.config().Addr, tlsConfig)
 	} else {
-		httpListener, err = net.Listen("tcp", h.cfg.Addr)
+		httpListener, err = net.Listen("tcp", h.config().Addr)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to bind to TCP address: %w", err)
@@ -684,20 +969,28 @@ func (h *Headscale) Serve() error {
 	errorGroup.Go(func() error { return httpServer.Serve(httpListener) })
 
 	log.Info().
-		Msgf("listening and serving HTTP on: %s", h.cfg.Addr)
+		Msgf("listening and serving HTTP on: %s", h.config().Addr)
+
+	err = prometheus.Register(stateCollector{h: h})
+	if err != nil {
+		return fmt.Errorf("failed to register metrics: %w", err)
+	}
 
 	promMux := http.NewServeMux()
 	promMux.Handle("/metrics", promhttp.Handler())
+	if traces != nil && traces.Memory != nil {
+		promMux.Handle("/debug/traces", traces.Memory)
+	}
 
 	promHTTPServer := &http.Server{
-		Addr:         h.cfg.MetricsAddr,
+		Addr:         h.config().MetricsAddr,
 		Handler:      promMux,
 		ReadTimeout:  types.HTTPReadTimeout,
 		WriteTimeout: 0,
 	}
 
 	var promHTTPListener net.Listener
-	promHTTPListener, err = net.Listen("tcp", h.cfg.MetricsAddr)
+	promHTTPListener, err = net.Listen("tcp", h.config().MetricsAddr)
 
 	if err != nil {
 		return fmt.Errorf("failed to bind to TCP address: %w", err)
@@ -706,18 +999,18 @@ func (h *Headscale) Serve() error {
 	errorGroup.Go(func() error { return promHTTPServer.Serve(promHTTPListener) })
 
 	log.Info().
-		Msgf("listening and serving metrics on: %s", h.cfg.MetricsAddr)
+		Msgf("listening and serving metrics on: %s", h.config().MetricsAddr)
 
 	var tailsqlContext context.Context
 	if tailsqlEnabled {
-		if h.cfg.DBtype != db.Sqlite {
-			log.Fatal().Str("type", h.cfg.DBtype).Msgf("tailsql only support %q", db.Sqlite)
+		if h.config().DBtype != db.Sqlite {
+			log.Fatal().Str("type", h.config().DBtype).Msgf("tailsql only support %q", db.Sqlite)
 		}
 		if tailsqlTSKey == "" {
 			log.Fatal().Msg("tailsql requires TS_AUTHKEY to be set")
 		}
 		tailsqlContext = context.Background()
-		go runTailSQLService(ctx, util.TSLogfWrapper(), tailsqlStateDir, h.cfg.DBpath)
+		go runTailSQLService(ctx, util.TSLogfWrapper(), tailsqlStateDir, h.config().DBpath)
 	}
 
 	// Handle common process-killing signals so we can gracefully shut down:
@@ -739,21 +1032,30 @@ func (h *Headscale) Serve() error {
 					Str("signal", sig.String()).
 					Msg("Received SIGHUP, reloading ACL and Config")
 
-				// TODO(kradalby): Reload config on SIGHUP
+				changed := false
 
-				if h.cfg.ACL.PolicyPath != "" {
-					aclPath := util.AbsolutePathFromConfigPath(h.cfg.ACL.PolicyPath)
-					pol, err := policy.LoadACLPolicyFromPath(aclPath)
-					if err != nil {
-						log.Error().Err(err).Msg("Failed to reload ACL policy")
+				err := h.reloadConfig()
+				if err != nil {
+					log.Error().
+						Err(err).
+						Msg("Failed to reload config, keeping the previous config")
+				} else {
+					changed = true
+
+					h.retargetACLPolicyWatcher()
+				}
+
+				if h.config().ACL.PolicyPath != "" || h.config().ACL.PolicyMode == types.PolicyModeDB {
+					err := h.reloadACLPolicy("signal")
+					if err == nil {
+						changed = true
 					}
+				}
 
-					h.ACLPolicy = pol
-					log.Info().
-						Str("path", aclPath).
-						Msg("ACL policy successfully reloaded, notifying nodes of change")
+				if changed {
+					log.Info().Msg("Notifying nodes of the reloaded ACL and config")
 
-					h.nodeNotifier.NotifyAll(types.StateUpdate{
+					h.nodeNotifier.NotifyAll(context.Background(), types.StateUpdate{
 						Type: types.StateFullUpdate,
 					})
 				}
@@ -797,12 +1099,38 @@ func (h *Headscale) Serve() error {
 				// Stop listening (and unlink the socket if unix type):
 				socketListener.Close()
 
+				err = h.leader.Close()
+				if err != nil {
+					log.Error().Err(err).Msg("Failed to step down as leader")
+				}
+
+				if h.cluster != nil {
+					err = h.cluster.Close()
+					if err != nil {
+						log.Error().Err(err).Msg("Failed to leave the cluster")
+					}
+				}
+
 				// Close db connections
 				err = h.db.Close()
 				if err != nil {
 					log.Error().Err(err).Msg("Failed to close db")
 				}
 
+				if h.auditSink != nil {
+					err = h.auditSink.close()
+					if err != nil {
+						log.Error().Err(err).Msg("Failed to close audit log")
+					}
+				}
+
+				if traces != nil {
+					err = traces.Shutdown(ctx)
+					if err != nil {
+						log.Error().Err(err).Msg("Failed to send the remaining traces")
+					}
+				}
+
 				log.Info().
 					Msg("Headscale stopped")
 
@@ -822,25 +1150,180 @@ func (h *Headscale) Serve() error {
 	return errorGroup.Wait()
 }
 
+// reloadConfig reads the configuration file again and applies the
+// settings that can be changed while headscale is running: the DNS
+// configuration, the DERP map sources, the OIDC allow-lists and login
+// settings, the ephemeral node inactivity timeout, randomize_client_port,
+// the ACL policy source and node approval.
+// Changes to any other setting are reported and ignored until restart.
+// Nodes have to be sent a full update for the changes to reach them.
+func (h *Headscale) reloadConfig() error {
+	cfg, err := types.ReloadConfig()
+	if err != nil {
+		return err
+	}
+
+	current := h.config()
+	for _, setting := range current.RestartRequired(cfg) {
+		log.Warn().
+			Str("setting", setting).
+			Msg("Setting changed but can only be applied by restarting headscale, ignoring")
+	}
+
+	derpMap := h.fetchDERPMap(cfg.DERP)
+	if len(derpMap.Regions) == 0 {
+		return errEmptyInitialDERPMap
+	}
+
+	reloaded := *current
+
+	reloaded.DNSConfig = cfg.DNSConfig
+	addMagicDNSRoutes(reloaded.DNSConfig, reloaded.IPPrefixes)
+
+	reloaded.DERP.URLs = cfg.DERP.URLs
+	reloaded.DERP.Paths = cfg.DERP.Paths
+
+	reloaded.OIDC.AllowedDomains = cfg.OIDC.AllowedDomains
+	reloaded.OIDC.AllowedUsers = cfg.OIDC.AllowedUsers
+	reloaded.OIDC.AllowedGroups = cfg.OIDC.AllowedGroups
+	reloaded.OIDC.ExtraParams = cfg.OIDC.ExtraParams
+	reloaded.OIDC.Expiry = cfg.OIDC.Expiry
+	reloaded.OIDC.UseExpiryFromToken = cfg.OIDC.UseExpiryFromToken
+	reloaded.OIDC.StripEmaildomain = cfg.OIDC.StripEmaildomain
+
+	reloaded.EphemeralNodeInactivityTimeout = cfg.EphemeralNodeInactivityTimeout
+	reloaded.RandomizeClientPort = cfg.RandomizeClientPort
+	reloaded.ACL = cfg.ACL
+	reloaded.NodeApproval = cfg.NodeApproval
+	reloaded.NodeExpiry = cfg.NodeExpiry
+
+	h.cfg.Store(&reloaded)
+	h.DERPMap = derpMap
+
+	log.Info().Msg("Config successfully reloaded")
+
+	return nil
+}
+
+// notifyPolicyChanged tells the other replicas, in high availability
+// mode, that the policy stored in the database changed.
+func (h *Headscale) notifyPolicyChanged() {
+	if h.cluster != nil {
+		h.cluster.PolicyChanged()
+	}
+}
+
+// reloadACLPolicy loads the ACL policy again, logging the outcome and
+// recording it in the reload metrics under the given trigger.
+// The previous policy is kept if the new one fails to load.
+func (h *Headscale) reloadACLPolicy(trigger string) error {
+	err := h.loadACLPolicy()
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("trigger", trigger).
+			Msg("Failed to reload ACL policy, keeping the previous policy")
+
+		aclPolicyReloads.WithLabelValues(trigger, "failed").Inc()
+		aclPolicyLastReloadSuccessful.Set(0)
+
+		return err
+	}
+
+	log.Info().
+		Str("mode", h.config().ACL.PolicyMode).
+		Str("trigger", trigger).
+		Msg("ACL policy successfully reloaded")
+
+	aclPolicyReloads.WithLabelValues(trigger, "success").Inc()
+	aclPolicyLastReloadSuccessful.Set(1)
+
+	return nil
+}
+
+// config returns the active configuration.
+func (h *Headscale) config() *types.Config {
+	return h.cfg.Load()
+}
+
+// ACLPolicy returns the active ACL policy, nil if there is none.
+func (h *Headscale) ACLPolicy() *policy.ACLPolicy {
+	return h.aclPolicy.Load()
+}
+
+// setACLPolicy makes pol the active ACL policy.
+func (h *Headscale) setACLPolicy(pol *policy.ACLPolicy) {
+	h.aclPolicy.Store(pol)
+	h.db.SetACLPolicy(pol)
+}
+
+// loadACLPolicy loads the ACL policy from the configured source, the
+// policy file or the database, and makes it the active policy.
+// The active policy is left untouched if the new policy fails to load.
+func (h *Headscale) loadACLPolicy() error {
+	h.aclPolicyLock.Lock()
+	defer h.aclPolicyLock.Unlock()
+
+	var (
+		pol *policy.ACLPolicy
+		err error
+	)
+
+	acl := h.config().ACL
+	switch acl.PolicyMode {
+	case types.PolicyModeDB:
+		var dbPolicy *types.Policy
+		dbPolicy, err = h.db.GetPolicy()
+		if err != nil {
+			// No policy has been stored yet, allow all.
+			if errors.Is(err, types.ErrPolicyNotFound) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to get policy from database: %w", err)
+		}
+
+		pol, err = policy.LoadACLPolicyFromBytes([]byte(dbPolicy.Data), "hujson")
+		if err != nil {
+			return fmt.Errorf("failed to parse policy from database: %w", err)
+		}
+
+	default:
+		if acl.PolicyPath == "" {
+			return nil
+		}
+
+		aclPath := util.AbsolutePathFromConfigPath(acl.PolicyPath)
+		pol, err = policy.LoadACLPolicyFromPath(aclPath)
+		if err != nil {
+			return fmt.Errorf("failed to load ACL policy from %q: %w", aclPath, err)
+		}
+	}
+
+	h.setACLPolicy(pol)
+
+	return nil
+}
+
 func (h *Headscale) getTLSSettings() (*tls.Config, error) {
 	var err error
-	if h.cfg.TLS.LetsEncrypt.Hostname != "" {
-		if !strings.HasPrefix(h.cfg.ServerURL, "https://") {
+	if h.config().TLS.LetsEncrypt.Hostname != "" {
+		if !strings.HasPrefix(h.config().ServerURL, "https://") {
 			log.Warn().
 				Msg("Listening with TLS but ServerURL does not start with https://")
 		}
 
 		certManager := autocert.Manager{
 			Prompt:     autocert.AcceptTOS,
-			HostPolicy: autocert.HostWhitelist(h.cfg.TLS.LetsEncrypt.Hostname),
-			Cache:      autocert.DirCache(h.cfg.TLS.LetsEncrypt.CacheDir),
+			HostPolicy: autocert.HostWhitelist(h.config().TLS.LetsEncrypt.Hostname),
+			Cache:      autocert.DirCache(h.config().TLS.LetsEncrypt.CacheDir),
 			Client: &acme.Client{
-				DirectoryURL: h.cfg.ACMEURL,
+				DirectoryURL: h.config().ACMEURL,
 			},
-			Email: h.cfg.ACMEEmail,
+			Email: h.config().ACMEEmail,
 		}
 
-		switch h.cfg.TLS.LetsEncrypt.ChallengeType {
+		switch h.config().TLS.LetsEncrypt.ChallengeType {
 		case types.TLSALPN01ChallengeType:
 			// Configuration via autocert with TLS-ALPN-01 (https://tools.ietf.org/html/rfc8737)
 			// The RFC requires that the validation is done on port 443; in other words, headscale
@@ -853,7 +1336,7 @@ func (h *Headscale) getTLSSettings() (*tls.Config, error) {
 			// service, which can be configured to run on any other port.
 
 			server := &http.Server{
-				Addr:        h.cfg.TLS.LetsEncrypt.Listen,
+				Addr:        h.config().TLS.LetsEncrypt.Listen,
 				Handler:     certManager.HTTPHandler(http.HandlerFunc(h.redirect)),
 				ReadTimeout: types.HTTPReadTimeout,
 			}
@@ -871,14 +1354,14 @@ func (h *Headscale) getTLSSettings() (*tls.Config, error) {
 		default:
 			return nil, errUnsupportedLetsEncryptChallengeType
 		}
-	} else if h.cfg.TLS.CertPath == "" {
-		if !strings.HasPrefix(h.cfg.ServerURL, "http://") {
+	} else if h.config().TLS.CertPath == "" {
+		if !strings.HasPrefix(h.config().ServerURL, "http://") {
 			log.Warn().Msg("Listening without TLS but ServerURL does not start with http://")
 		}
 
 		return nil, err
 	} else {
-		if !strings.HasPrefix(h.cfg.ServerURL, "https://") {
+		if !strings.HasPrefix(h.config().ServerURL, "https://") {
 			log.Warn().Msg("Listening with TLS but ServerURL does not start with https://")
 		}
 
@@ -888,7 +1371,7 @@ func (h *Headscale) getTLSSettings() (*tls.Config, error) {
 			MinVersion:   tls.VersionTLS12,
 		}
 
-		tlsConfig.Certificates[0], err = tls.LoadX509KeyPair(h.cfg.TLS.CertPath, h.cfg.TLS.KeyPath)
+		tlsConfig.Certificates[0], err = tls.LoadX509KeyPair(h.config().TLS.CertPath, h.config().TLS.KeyPath)
 
 		return tlsConfig, err
 	}
@@ -0,0 +1,73 @@
+package hscontrol
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/events"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"gopkg.in/check.v1"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+)
+
+var errDeliveryFailed = errors.New("delivery failed")
+
+// flakyNotifier fails to deliver the notifications until ok is set.
+type flakyNotifier struct {
+	ok        bool
+	delivered []string
+}
+
+func (n *flakyNotifier) NotifyExpiring(_ context.Context, event events.Event) error {
+	if !n.ok {
+		return errDeliveryFailed
+	}
+
+	n.delivered = append(n.delivered, event.Node.Hostname)
+
+	return nil
+}
+
+func (s *Suite) TestNotifyExpiringNodesRetries(c *check.C) {
+	user, err := app.db.CreateUser("expiring")
+	c.Assert(err, check.IsNil)
+
+	expiry := time.Now().Add(24 * time.Hour)
+	_, err = app.db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		DiscoKey:       key.NewDisco().Public(),
+		Hostname:       "laptop",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodCLI,
+		Expiry:         &expiry,
+		Hostinfo:       &tailcfg.Hostinfo{},
+	})
+	c.Assert(err, check.IsNil)
+
+	app.config().NodeExpiry.Notify.Before = 7 * 24 * time.Hour
+
+	notifier := &flakyNotifier{}
+
+	// A failed delivery is not recorded, it is sent again.
+	app.notifyExpiringNodes(context.Background(), []events.ExpiryNotifier{notifier})
+
+	nodes, err := app.db.ListExpiringNodes(app.config().NodeExpiry.Notify.Before)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.HasLen, 1)
+
+	notifier.ok = true
+	app.notifyExpiringNodes(context.Background(), []events.ExpiryNotifier{notifier})
+	c.Assert(notifier.delivered, check.DeepEquals, []string{"laptop"})
+
+	nodes, err = app.db.ListExpiringNodes(app.config().NodeExpiry.Notify.Before)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.HasLen, 0)
+
+	// The owner is only notified once of an expiry.
+	app.notifyExpiringNodes(context.Background(), []events.ExpiryNotifier{notifier})
+	c.Assert(notifier.delivered, check.HasLen, 1)
+}
@@ -0,0 +1,216 @@
+package hscontrol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// auditSink appends audit events as JSON lines to a file, in addition
+// to the database.
+type auditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+type auditLine struct {
+	ID        uint64          `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+func newAuditSink(path string) (*auditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	return &auditSink{file: file}, nil
+}
+
+func (sink *auditSink) write(event *types.AuditEvent) error {
+	line, err := json.Marshal(auditLine{
+		ID:        event.ID,
+		CreatedAt: event.CreatedAt,
+		Actor:     event.Actor,
+		Action:    event.Action,
+		Target:    event.Target,
+		Before:    json.RawMessage(event.Before),
+		After:     json.RawMessage(event.After),
+	})
+	if err != nil {
+		return err
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	_, err = sink.file.Write(append(line, '\n'))
+
+	return err
+}
+
+func (sink *auditSink) close() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	return sink.file.Close()
+}
+
+// audit records an administrative change made through the API, the
+// actor is derived from the request context.
+func (h *Headscale) audit(
+	ctx context.Context,
+	action string,
+	target string,
+	before, after proto.Message,
+) {
+	h.recordAuditEvent(auditActor(ctx), action, target, before, after)
+}
+
+// recordAuditEvent stores an audit event in the database and the audit
+// log file, if one is configured.
+// Failing to record an event is logged and does not undo the change.
+func (h *Headscale) recordAuditEvent(
+	actor string,
+	action string,
+	target string,
+	before, after proto.Message,
+) {
+	event := types.AuditEvent{
+		Actor:  actor,
+		Action: action,
+		Target: target,
+		Before: auditJSON(before),
+		After:  auditJSON(after),
+	}
+
+	err := h.db.CreateAuditEvent(&event)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("actor", actor).
+			Str("action", action).
+			Str("target", target).
+			Msg("Failed to store audit event")
+	}
+
+	if h.auditSink != nil {
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+
+		err = h.auditSink.write(&event)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("actor", actor).
+				Str("action", action).
+				Str("target", target).
+				Msg("Failed to write audit event to the audit log")
+		}
+	}
+}
+
+// auditActor describes who made the request in ctx. Requests carrying
+// an API key, including the ones forwarded by the HTTP API, are
+// attributed to the key, other requests over the unix socket to the
+// socket itself.
+func auditActor(ctx context.Context) string {
+	if meta, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, token := range meta.Get("authorization") {
+			if !strings.HasPrefix(token, AuthPrefix) {
+				continue
+			}
+
+			prefix, _, found := strings.Cut(strings.TrimPrefix(token, AuthPrefix), ".")
+			if found {
+				return types.AuditActorAPIKeyPrefix + prefix
+			}
+		}
+	}
+
+	if client, ok := peer.FromContext(ctx); ok && client.Addr.Network() == "unix" {
+		return types.AuditActorUnixSocket
+	}
+
+	return types.AuditActorUnknown
+}
+
+func auditJSON(msg proto.Message) string {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return ""
+	}
+
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+func auditTarget(kind string, id any) string {
+	return fmt.Sprintf("%s:%v", kind, id)
+}
+
+// auditRoute returns the current state of a route for the audit log, or
+// nil if it does not exist.
+func (h *Headscale) auditRoute(id uint64) *v1.Route {
+	route, err := h.db.GetRoute(id)
+	if err != nil {
+		return nil
+	}
+
+	protoRoute := types.Routes{*route}.Proto()[0]
+	protoRoute.Node = auditNode(&route.Node)
+
+	return protoRoute
+}
+
+// auditNode returns a node for the audit log, without the key of its pre
+// auth key.
+func auditNode(node *types.Node) *v1.Node {
+	protoNode := node.Proto()
+	if protoNode.GetPreAuthKey() != nil {
+		protoNode.PreAuthKey.Key = ""
+	}
+
+	return protoNode
+}
+
+// auditPreAuthKey returns a pre auth key for the audit log, without the
+// key itself.
+func auditPreAuthKey(key *types.PreAuthKey) *v1.PreAuthKey {
+	protoKey := key.Proto()
+	protoKey.Key = ""
+
+	return protoKey
+}
+
+// auditIPReservation returns an IP reservation for the audit log,
+// without the key of its pre auth key.
+func auditIPReservation(reservation *types.IPReservation) *v1.IPReservation {
+	protoRes := reservation.Proto()
+	if protoRes.GetPreAuthKey() != nil {
+		protoRes.PreAuthKey.Key = ""
+	}
+
+	return protoRes
+}
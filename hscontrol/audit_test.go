@@ -0,0 +1,118 @@
+package hscontrol
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/check.v1"
+	"tailscale.com/types/key"
+)
+
+func (s *Suite) TestAuditActor(c *check.C) {
+	c.Assert(auditActor(context.Background()), check.Equals, types.AuditActorUnknown)
+
+	socket := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.UnixAddr{Name: "/var/run/headscale/headscale.sock", Net: "unix"},
+	})
+	c.Assert(auditActor(socket), check.Equals, types.AuditActorUnixSocket)
+
+	apiKey := metadata.NewIncomingContext(socket, metadata.Pairs("authorization", "Bearer abcdef.secret"))
+	c.Assert(auditActor(apiKey), check.Equals, "api_key:abcdef")
+}
+
+func (s *Suite) TestAuditUserMutations(c *check.C) {
+	logPath := filepath.Join(tmpDir, "audit.log")
+
+	var err error
+	app.auditSink, err = newAuditSink(logPath)
+	c.Assert(err, check.IsNil)
+
+	api := newHeadscaleV1APIServer(app)
+	ctx := metadata.NewIncomingContext(
+		context.Background(),
+		metadata.Pairs("authorization", "Bearer abcdef.secret"),
+	)
+
+	_, err = api.CreateUser(ctx, &v1.CreateUserRequest{Name: "audited"})
+	c.Assert(err, check.IsNil)
+
+	renamed, err := api.RenameUser(ctx, &v1.RenameUserRequest{OldName: "audited", NewName: "renamed"})
+	c.Assert(err, check.IsNil)
+
+	events, err := api.ListAuditEvents(ctx, &v1.ListAuditEventsRequest{})
+	c.Assert(err, check.IsNil)
+	c.Assert(events.GetEvents(), check.HasLen, 2)
+
+	rename := events.GetEvents()[0]
+	c.Assert(rename.GetAction(), check.Equals, types.AuditUserRename)
+	c.Assert(rename.GetActor(), check.Equals, "api_key:abcdef")
+	c.Assert(rename.GetTarget(), check.Equals, auditTarget("user", renamed.GetUser().GetId()))
+	c.Assert(rename.GetBefore(), check.Matches, `.*"audited".*`)
+	c.Assert(rename.GetAfter(), check.Matches, `.*"renamed".*`)
+
+	created, err := api.ListAuditEvents(ctx, &v1.ListAuditEventsRequest{Action: types.AuditUserCreate})
+	c.Assert(err, check.IsNil)
+	c.Assert(created.GetEvents(), check.HasLen, 1)
+	c.Assert(created.GetEvents()[0].GetBefore(), check.Equals, "")
+
+	file, err := os.Open(logPath)
+	c.Assert(err, check.IsNil)
+	defer file.Close()
+
+	lines := []auditLine{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line auditLine
+		c.Assert(json.Unmarshal(scanner.Bytes(), &line), check.IsNil)
+		lines = append(lines, line)
+	}
+	c.Assert(lines, check.HasLen, 2)
+	c.Assert(lines[1].Action, check.Equals, types.AuditUserRename)
+	c.Assert(lines[1].ID, check.Equals, rename.GetId())
+}
+
+func (s *Suite) TestAuditNodeHidesPreAuthKey(c *check.C) {
+	user, err := app.db.CreateUser("audited")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.db.CreatePreAuthKey(user.Name, true, false, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	node, err := app.db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "audited",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		AuthKeyID:      uint(pak.ID),
+	})
+	c.Assert(err, check.IsNil)
+
+	api := newHeadscaleV1APIServer(app)
+	_, err = api.RenameNode(context.Background(), &v1.RenameNodeRequest{
+		NodeId:  node.ID,
+		NewName: "renamed",
+	})
+	c.Assert(err, check.IsNil)
+
+	events, err := api.ListAuditEvents(context.Background(), &v1.ListAuditEventsRequest{
+		Action: types.AuditNodeRename,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(events.GetEvents(), check.HasLen, 1)
+
+	event := events.GetEvents()[0]
+	c.Assert(event.GetBefore(), check.Matches, `(?s).*"preAuthKey".*`)
+	c.Assert(strings.Contains(event.GetBefore(), pak.Key), check.Equals, false)
+	c.Assert(strings.Contains(event.GetAfter(), pak.Key), check.Equals, false)
+}
@@ -1,6 +1,7 @@
 package hscontrol
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,9 +9,12 @@ import (
 	"strings"
 	"time"
 
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/key"
@@ -63,15 +67,31 @@ func (h *Headscale) handleRegister(
 	registerRequest tailcfg.RegisterRequest,
 	machineKey key.MachinePublic,
 ) {
+	ctx, span := tracer.Start(req.Context(), "handleRegister", trace.WithAttributes(
+		attribute.String("machine_key", machineKey.ShortString()),
+		attribute.String("node_key", registerRequest.NodeKey.ShortString()),
+		attribute.Bool("auth_key", registerRequest.Auth.AuthKey != ""),
+		attribute.Bool("followup", registerRequest.Followup != ""),
+	))
+	defer span.End()
+
 	logInfo, logTrace, logErr := logAuthFunc(registerRequest, machineKey)
 	now := time.Now().UTC()
 	logTrace("handleRegister called, looking up machine in DB")
-	node, err := h.db.GetNodeByAnyKey(machineKey, registerRequest.NodeKey, registerRequest.OldNodeKey)
+	node, err := h.db.WithContext(ctx).GetNodeByAnyKey(machineKey, registerRequest.NodeKey, registerRequest.OldNodeKey)
 	logTrace("handleRegister database lookup has returned")
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		// If the node has AuthKey set, handle registration via PreAuthKeys
 		if registerRequest.Auth.AuthKey != "" {
-			h.handleAuthKey(writer, registerRequest, machineKey)
+			h.handleAuthKey(ctx, writer, registerRequest, machineKey)
+
+			return
+		}
+
+		// Refuse nodes whose registration has been rejected by an
+		// administrator until the rejection expires.
+		if reg, err := h.db.WithContext(ctx).GetPendingRegistration(machineKey); err == nil && reg.Rejected {
+			h.handleRejectedRegistration(ctx, writer, registerRequest, machineKey)
 
 			return
 		}
@@ -86,14 +106,14 @@ func (h *Headscale) handleRegister(
 		// successful RegisterResponse.
 		if registerRequest.Followup != "" {
 			logTrace("register request is a followup")
-			if _, ok := h.registrationCache.Get(machineKey.String()); ok {
+			if _, err := h.db.WithContext(ctx).GetPendingRegistration(machineKey); err == nil {
 				logTrace("Node is waiting for interactive login")
 
 				select {
 				case <-req.Context().Done():
 					return
 				case <-time.After(registrationHoldoff):
-					h.handleNewNode(writer, registerRequest, machineKey)
+					h.handleNewNode(ctx, writer, registerRequest, machineKey)
 
 					return
 				}
@@ -102,7 +122,7 @@ func (h *Headscale) handleRegister(
 
 		logInfo("Node not found in database, creating new")
 
-		givenName, err := h.db.GenerateGivenName(
+		givenName, err := h.db.WithContext(ctx).GenerateGivenName(
 			machineKey,
 			registerRequest.Hostinfo.Hostname,
 		)
@@ -130,13 +150,18 @@ func (h *Headscale) handleRegister(
 			newNode.Expiry = &registerRequest.Expiry
 		}
 
-		h.registrationCache.Set(
-			machineKey.String(),
+		err = h.db.WithContext(ctx).SetPendingRegistration(
 			newNode,
-			registerCacheExpiration,
+			time.Now().Add(registerCacheExpiration),
 		)
+		if err != nil {
+			logErr(err, "Failed to store pending registration")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+
+			return
+		}
 
-		h.handleNewNode(writer, registerRequest, machineKey)
+		h.handleNewNode(ctx, writer, registerRequest, machineKey)
 
 		return
 	}
@@ -150,7 +175,7 @@ func (h *Headscale) handleRegister(
 		// due to a misunderstanding of the protocol https://github.com/juanfont/headscale/issues/1054
 		// So if we have a not valid MachineKey (but we were able to fetch the node with the NodeKeys), we update it.
 		if err != nil || node.MachineKey.IsZero() {
-			if err := h.db.NodeSetMachineKey(node, machineKey); err != nil {
+			if err := h.db.WithContext(ctx).NodeSetMachineKey(node, machineKey); err != nil {
 				log.Error().
 					Caller().
 					Str("func", "RegistrationHandler").
@@ -172,7 +197,7 @@ func (h *Headscale) handleRegister(
 			//   https://github.com/tailscale/tailscale/blob/main/tailcfg/tailcfg.go#L648
 			if !registerRequest.Expiry.IsZero() &&
 				registerRequest.Expiry.UTC().Before(now) {
-				h.handleNodeLogOut(writer, *node, machineKey)
+				h.handleNodeLogOut(ctx, writer, *node, machineKey)
 
 				return
 			}
@@ -180,7 +205,7 @@ func (h *Headscale) handleRegister(
 			// If node is not expired, and it is register, we have a already accepted this node,
 			// let it proceed with a valid registration
 			if !node.IsExpired() {
-				h.handleNodeWithValidRegistration(writer, *node, machineKey)
+				h.handleNodeWithValidRegistration(ctx, writer, *node, machineKey)
 
 				return
 			}
@@ -190,6 +215,7 @@ func (h *Headscale) handleRegister(
 		if node.NodeKey.String() == registerRequest.OldNodeKey.String() &&
 			!node.IsExpired() {
 			h.handleNodeKeyRefresh(
+				ctx,
 				writer,
 				registerRequest,
 				*node,
@@ -208,7 +234,7 @@ func (h *Headscale) handleRegister(
 		}
 
 		// The node has expired or it is logged out
-		h.handleNodeExpiredOrLoggedOut(writer, registerRequest, *node, machineKey)
+		h.handleNodeExpiredOrLoggedOut(ctx, writer, registerRequest, *node, machineKey)
 
 		// TODO(juan): RegisterRequest includes an Expiry time, that we could optionally use
 		node.Expiry = &time.Time{}
@@ -218,11 +244,17 @@ func (h *Headscale) handleRegister(
 		// TODO(juan): What happens when using fast user switching between two
 		// headscale-managed tailnets?
 		node.NodeKey = registerRequest.NodeKey
-		h.registrationCache.Set(
-			machineKey.String(),
+		err = h.db.WithContext(ctx).SetPendingRegistration(
 			*node,
-			registerCacheExpiration,
+			time.Now().Add(registerCacheExpiration),
 		)
+		if err != nil {
+			log.Error().
+				Caller().
+				Str("node", node.Hostname).
+				Err(err).
+				Msg("Failed to store pending registration")
+		}
 
 		return
 	}
@@ -233,6 +265,7 @@ func (h *Headscale) handleRegister(
 //
 // TODO: check if any locks are needed around IP allocation.
 func (h *Headscale) handleAuthKey(
+	ctx context.Context,
 	writer http.ResponseWriter,
 	registerRequest tailcfg.RegisterRequest,
 	machineKey key.MachinePublic,
@@ -243,7 +276,7 @@ func (h *Headscale) handleAuthKey(
 		Msgf("Processing auth key for %s", registerRequest.Hostinfo.Hostname)
 	resp := tailcfg.RegisterResponse{}
 
-	pak, err := h.db.ValidatePreAuthKey(registerRequest.Auth.AuthKey)
+	pak, err := h.db.WithContext(ctx).ValidatePreAuthKey(registerRequest.Auth.AuthKey)
 	if err != nil {
 		log.Error().
 			Caller().
@@ -302,8 +335,11 @@ func (h *Headscale) handleAuthKey(
 	// The error is not important, because if it does not
 	// exist, then this is a new node and we will move
 	// on to registration.
-	node, _ := h.db.GetNodeByAnyKey(machineKey, registerRequest.NodeKey, registerRequest.OldNodeKey)
+	node, _ := h.db.WithContext(ctx).GetNodeByAnyKey(machineKey, registerRequest.NodeKey, registerRequest.OldNodeKey)
+	var before *v1.Node
 	if node != nil {
+		before = auditNode(node)
+
 		log.Trace().
 			Caller().
 			Str("node", node.Hostname).
@@ -311,21 +347,11 @@ func (h *Headscale) handleAuthKey(
 
 		node.NodeKey = nodeKey
 		node.AuthKeyID = uint(pak.ID)
-		err := h.db.NodeSetExpiry(node, registerRequest.Expiry)
-		if err != nil {
-			log.Error().
-				Caller().
-				Str("node", node.Hostname).
-				Err(err).
-				Msg("Failed to refresh node")
-
-			return
-		}
 
 		aclTags := pak.Proto().GetAclTags()
 		if len(aclTags) > 0 {
 			// This conditional preserves the existing behaviour, although SaaS would reset the tags on auth-key login
-			err = h.db.SetTags(node, aclTags)
+			err := h.db.WithContext(ctx).SetTags(node, aclTags)
 
 			if err != nil {
 				log.Error().
@@ -338,10 +364,25 @@ func (h *Headscale) handleAuthKey(
 				return
 			}
 		}
+
+		// The expiry is set after the tags, which can change it.
+		err := h.db.WithContext(ctx).NodeSetExpiry(
+			node,
+			h.keyExpiry(node, &node.User, registerRequest.Expiry),
+		)
+		if err != nil {
+			log.Error().
+				Caller().
+				Str("node", node.Hostname).
+				Err(err).
+				Msg("Failed to refresh node")
+
+			return
+		}
 	} else {
 		now := time.Now().UTC()
 
-		givenName, err := h.db.GenerateGivenName(machineKey, registerRequest.Hostinfo.Hostname)
+		givenName, err := h.db.WithContext(ctx).GenerateGivenName(machineKey, registerRequest.Hostinfo.Hostname)
 		if err != nil {
 			log.Error().
 				Caller().
@@ -359,14 +400,18 @@ func (h *Headscale) handleAuthKey(
 			UserID:         pak.User.ID,
 			MachineKey:     machineKey,
 			RegisterMethod: util.RegisterMethodAuthKey,
-			Expiry:         &registerRequest.Expiry,
 			NodeKey:        nodeKey,
 			LastSeen:       &now,
 			AuthKeyID:      uint(pak.ID),
 			ForcedTags:     pak.Proto().GetAclTags(),
+
+			PendingApproval: h.config().NodeApproval.NeedsApproval(pak.Proto().GetAclTags()),
 		}
 
-		node, err = h.db.RegisterNode(
+		expiry := h.keyExpiry(&nodeToRegister, &pak.User, registerRequest.Expiry)
+		nodeToRegister.Expiry = &expiry
+
+		node, err = h.db.WithContext(ctx).RegisterNode(
 			nodeToRegister,
 		)
 		if err != nil {
@@ -382,7 +427,7 @@ func (h *Headscale) handleAuthKey(
 		}
 	}
 
-	err = h.db.UsePreAuthKey(pak)
+	err = h.db.WithContext(ctx).UsePreAuthKey(pak)
 	if err != nil {
 		log.Error().
 			Caller().
@@ -395,7 +440,15 @@ func (h *Headscale) handleAuthKey(
 		return
 	}
 
-	resp.MachineAuthorized = true
+	h.recordAuditEvent(
+		fmt.Sprintf("%s%d", types.AuditActorPreAuthKeyPrefix, pak.ID),
+		types.AuditNodeRegister,
+		auditTarget("node", node.ID),
+		before,
+		auditNode(node),
+	)
+
+	resp.MachineAuthorized = !node.PendingApproval
 	resp.User = *pak.User.TailscaleUser()
 	// Provide LoginName when registering with pre-auth key
 	// Otherwise it will need to exec `tailscale up` twice to fetch the *LoginName*
@@ -436,6 +489,7 @@ func (h *Headscale) handleAuthKey(
 // of registration headscale is configured with.
 // This url is then showed to the user by the local Tailscale client.
 func (h *Headscale) handleNewNode(
+	ctx context.Context,
 	writer http.ResponseWriter,
 	registerRequest tailcfg.RegisterRequest,
 	machineKey key.MachinePublic,
@@ -450,12 +504,12 @@ func (h *Headscale) handleNewNode(
 	if h.oauth2Config != nil {
 		resp.AuthURL = fmt.Sprintf(
 			"%s/oidc/register/%s",
-			strings.TrimSuffix(h.cfg.ServerURL, "/"),
+			strings.TrimSuffix(h.config().ServerURL, "/"),
 			machineKey.String(),
 		)
 	} else {
 		resp.AuthURL = fmt.Sprintf("%s/register/%s",
-			strings.TrimSuffix(h.cfg.ServerURL, "/"),
+			strings.TrimSuffix(h.config().ServerURL, "/"),
 			machineKey.String())
 	}
 
@@ -477,7 +531,41 @@ func (h *Headscale) handleNewNode(
 	logInfo(fmt.Sprintf("Successfully sent auth url: %s", resp.AuthURL))
 }
 
+// handleRejectedRegistration refuses a node whose pending registration
+// has been rejected by an administrator.
+func (h *Headscale) handleRejectedRegistration(
+	ctx context.Context,
+	writer http.ResponseWriter,
+	registerRequest tailcfg.RegisterRequest,
+	machineKey key.MachinePublic,
+) {
+	logInfo, _, logErr := logAuthFunc(registerRequest, machineKey)
+
+	resp := tailcfg.RegisterResponse{
+		MachineAuthorized: false,
+		Error:             "registration has been rejected by an administrator",
+	}
+
+	respBody, err := json.Marshal(resp)
+	if err != nil {
+		logErr(err, "Cannot encode message")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.WriteHeader(http.StatusUnauthorized)
+	_, err = writer.Write(respBody)
+	if err != nil {
+		logErr(err, "Failed to write response")
+	}
+
+	logInfo("Refused node with a rejected registration")
+}
+
 func (h *Headscale) handleNodeLogOut(
+	ctx context.Context,
 	writer http.ResponseWriter,
 	node types.Node,
 	machineKey key.MachinePublic,
@@ -489,7 +577,7 @@ func (h *Headscale) handleNodeLogOut(
 		Msg("Client requested logout")
 
 	now := time.Now()
-	err := h.db.NodeSetExpiry(&node, now)
+	err := h.db.WithContext(ctx).NodeSetExpiry(&node, now)
 	if err != nil {
 		log.Error().
 			Caller().
@@ -510,7 +598,7 @@ func (h *Headscale) handleNodeLogOut(
 		},
 	}
 	if stateUpdate.Valid() {
-		h.nodeNotifier.NotifyWithIgnore(stateUpdate, node.MachineKey.String())
+		h.nodeNotifier.NotifyWithIgnore(ctx, stateUpdate, node.MachineKey.String())
 	}
 
 	resp.AuthURL = ""
@@ -541,7 +629,7 @@ func (h *Headscale) handleNodeLogOut(
 	}
 
 	if node.IsEphemeral() {
-		err = h.db.DeleteNode(&node)
+		err = h.db.WithContext(ctx).DeleteNode(&node)
 		if err != nil {
 			log.Error().
 				Err(err).
@@ -559,6 +647,7 @@ func (h *Headscale) handleNodeLogOut(
 }
 
 func (h *Headscale) handleNodeWithValidRegistration(
+	ctx context.Context,
 	writer http.ResponseWriter,
 	node types.Node,
 	machineKey key.MachinePublic,
@@ -572,7 +661,7 @@ func (h *Headscale) handleNodeWithValidRegistration(
 		Msg("Client is registered and we have the current NodeKey. All clear to /map")
 
 	resp.AuthURL = ""
-	resp.MachineAuthorized = true
+	resp.MachineAuthorized = !node.PendingApproval
 	resp.User = *node.User.TailscaleUser()
 	resp.Login = *node.User.TailscaleLogin()
 
@@ -608,6 +697,7 @@ func (h *Headscale) handleNodeWithValidRegistration(
 }
 
 func (h *Headscale) handleNodeKeyRefresh(
+	ctx context.Context,
 	writer http.ResponseWriter,
 	registerRequest tailcfg.RegisterRequest,
 	node types.Node,
@@ -620,7 +710,7 @@ func (h *Headscale) handleNodeKeyRefresh(
 		Str("node", node.Hostname).
 		Msg("We have the OldNodeKey in the database. This is a key refresh")
 
-	err := h.db.NodeSetNodeKey(&node, registerRequest.NodeKey)
+	err := h.db.WithContext(ctx).NodeSetNodeKey(&node, registerRequest.NodeKey)
 	if err != nil {
 		log.Error().
 			Caller().
@@ -663,6 +753,7 @@ func (h *Headscale) handleNodeKeyRefresh(
 }
 
 func (h *Headscale) handleNodeExpiredOrLoggedOut(
+	ctx context.Context,
 	writer http.ResponseWriter,
 	registerRequest tailcfg.RegisterRequest,
 	node types.Node,
@@ -671,7 +762,7 @@ func (h *Headscale) handleNodeExpiredOrLoggedOut(
 	resp := tailcfg.RegisterResponse{}
 
 	if registerRequest.Auth.AuthKey != "" {
-		h.handleAuthKey(writer, registerRequest, machineKey)
+		h.handleAuthKey(ctx, writer, registerRequest, machineKey)
 
 		return
 	}
@@ -687,11 +778,11 @@ func (h *Headscale) handleNodeExpiredOrLoggedOut(
 
 	if h.oauth2Config != nil {
 		resp.AuthURL = fmt.Sprintf("%s/oidc/register/%s",
-			strings.TrimSuffix(h.cfg.ServerURL, "/"),
+			strings.TrimSuffix(h.config().ServerURL, "/"),
 			machineKey.String())
 	} else {
 		resp.AuthURL = fmt.Sprintf("%s/register/%s",
-			strings.TrimSuffix(h.cfg.ServerURL, "/"),
+			strings.TrimSuffix(h.config().ServerURL, "/"),
 			machineKey.String())
 	}
 
@@ -728,3 +819,24 @@ func (h *Headscale) handleNodeExpiredOrLoggedOut(
 		Str("node", node.Hostname).
 		Msg("Node logged out. Sent AuthURL for reauthentication")
 }
+
+// keyExpiry returns the expiry of the key of node, registering now to
+// user, from the node_expiry policy. The expiry requested by the client
+// is kept if it is earlier, the returned time is zero if the key does not
+// expire.
+func (h *Headscale) keyExpiry(node *types.Node, user *types.User, requested time.Time) time.Time {
+	withUser := *node
+	withUser.User = *user
+
+	keyExpiry := h.config().NodeExpiry.ForNode(&withUser, h.ACLPolicy().EffectiveTags(&withUser))
+	if keyExpiry == 0 {
+		return requested
+	}
+
+	expiry := time.Now().Add(keyExpiry)
+	if !requested.IsZero() && requested.Before(expiry) {
+		return requested
+	}
+
+	return expiry
+}
@@ -0,0 +1,627 @@
+// Package cluster lets several headscale replicas share a PostgreSQL
+// database. The replicas exchange the updates of their notifiers, and
+// the nodes connected to them, through PostgreSQL LISTEN/NOTIFY.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juanfont/headscale/hscontrol/db"
+	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+)
+
+const (
+	// channel is the PostgreSQL notification channel of the replicas.
+	channel = "headscale"
+
+	// maxPayloadSize is below the 8000 bytes PostgreSQL accepts as the
+	// payload of a notification.
+	maxPayloadSize = 7900
+
+	// staleHeartbeats is the number of heartbeats a replica can miss
+	// before it is considered gone.
+	staleHeartbeats = 3
+
+	outboxSize = 1024
+
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+const (
+	kindUpdate       = "update"
+	kindConnected    = "connected"
+	kindDisconnected = "disconnected"
+	kindPolicy       = "policy"
+	kindDERP         = "derp"
+)
+
+// message is the payload of the notifications sent between replicas.
+type message struct {
+	Replica string `json:"replica"`
+	Kind    string `json:"kind"`
+
+	// MachineKey is the node that connected or disconnected, or the
+	// only node an update is for.
+	MachineKey string  `json:"machine_key,omitempty"`
+	Update     *update `json:"update,omitempty"`
+}
+
+// update is a types.StateUpdate where nodes are replaced by their IDs,
+// the receiving replicas load them from the database.
+type update struct {
+	Type    types.StateUpdateType `json:"type"`
+	NodeIDs []uint64              `json:"node_ids,omitempty"`
+	Patches []*tailcfg.PeerChange `json:"patches,omitempty"`
+	Removed []tailcfg.NodeID      `json:"removed,omitempty"`
+	Message string                `json:"message,omitempty"`
+	Ignore  []string              `json:"ignore,omitempty"`
+}
+
+// Cluster is the replica of headscale running in this process, it
+// implements notifier.Cluster.
+type Cluster struct {
+	cfg      types.HAConfig
+	dsn      string
+	db       *db.HSDatabase
+	notifier *notifier.Notifier
+
+	// OnPolicyChanged is called when another replica changed the
+	// policy stored in the database.
+	OnPolicyChanged func()
+
+	// OnDERPMapChanged is called when the leader replica updated its
+	// DERPMap, as the DERPMap does not fit in a notification.
+	OnDERPMapChanged func()
+
+	// OnNodeLost is called for the nodes connected to a replica that
+	// stopped without closing their sessions.
+	OnNodeLost func(node *types.Node)
+
+	outbox chan func()
+
+	sessionsMu sync.Mutex
+	// sessions holds the session changes of the nodes connected to this
+	// replica not written to the database yet, whether the node is
+	// connected by machine key.
+	sessions        map[key.MachinePublic]bool
+	sessionsChanged chan struct{}
+
+	mu sync.RWMutex
+	// remote holds the replicas every remotely connected node, by
+	// machine key, has a session open on.
+	remote map[string]map[string]struct{}
+}
+
+var _ notifier.Cluster = (*Cluster)(nil)
+
+func New(
+	cfg types.HAConfig,
+	dsn string,
+	database *db.HSDatabase,
+	notif *notifier.Notifier,
+) *Cluster {
+	return &Cluster{
+		cfg:      cfg,
+		dsn:      dsn,
+		db:       database,
+		notifier: notif,
+		outbox:   make(chan func(), outboxSize),
+		remote:   map[string]map[string]struct{}{},
+
+		sessions:        map[key.MachinePublic]bool{},
+		sessionsChanged: make(chan struct{}, 1),
+	}
+}
+
+// Start registers the replica, loads the nodes connected to the other
+// replicas and exchanges updates with them until ctx is cancelled.
+func (c *Cluster) Start(ctx context.Context) error {
+	// Sessions left by a previous run of this replica are stale.
+	if err := c.db.RemoveReplica(c.cfg.ReplicaName); err != nil {
+		return err
+	}
+
+	if err := c.db.TouchReplica(c.cfg.ReplicaName); err != nil {
+		return err
+	}
+
+	if err := c.loadSessions(); err != nil {
+		return err
+	}
+
+	conn, err := c.listen(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("replica", c.cfg.ReplicaName).
+		Dur("heartbeat_interval", c.cfg.HeartbeatInterval).
+		Msg("High availability mode enabled, joined the cluster")
+
+	go c.receive(ctx, conn)
+	go c.send(ctx)
+	go c.writeSessions(ctx)
+	go c.heartbeat(ctx)
+
+	return nil
+}
+
+// Close removes the replica from the cluster, its nodes are seen as
+// disconnected by the other replicas.
+func (c *Cluster) Close() error {
+	return c.db.RemoveReplica(c.cfg.ReplicaName)
+}
+
+// Publish implements notifier.Cluster.
+func (c *Cluster) Publish(
+	stateUpdate types.StateUpdate,
+	machineKey *key.MachinePublic,
+	ignore []string,
+) {
+	if stateUpdate.Type == types.StateDERPUpdated {
+		c.enqueue(func() {
+			c.notify(message{Kind: kindDERP})
+		})
+
+		return
+	}
+
+	msg := message{
+		Kind: kindUpdate,
+		Update: &update{
+			Type:    stateUpdate.Type,
+			Patches: stateUpdate.ChangePatches,
+			Removed: stateUpdate.Removed,
+			Message: stateUpdate.Message,
+			Ignore:  ignore,
+		},
+	}
+	for _, node := range stateUpdate.ChangeNodes {
+		msg.Update.NodeIDs = append(msg.Update.NodeIDs, node.ID)
+	}
+	if machineKey != nil {
+		msg.MachineKey = machineKey.String()
+	}
+
+	c.enqueue(func() {
+		c.notify(msg)
+	})
+}
+
+// Connected implements notifier.Cluster.
+func (c *Cluster) Connected(machineKey key.MachinePublic) {
+	c.setSession(machineKey, true)
+}
+
+// Disconnected implements notifier.Cluster.
+func (c *Cluster) Disconnected(machineKey key.MachinePublic) {
+	c.setSession(machineKey, false)
+}
+
+// setSession queues the session change of a node for writeSessions.
+// Only the latest change of each node is kept, it replaces the ones not
+// written yet.
+func (c *Cluster) setSession(machineKey key.MachinePublic, connected bool) {
+	c.sessionsMu.Lock()
+	c.sessions[machineKey] = connected
+	c.sessionsMu.Unlock()
+
+	select {
+	case c.sessionsChanged <- struct{}{}:
+	default:
+	}
+}
+
+// writeSessions writes the session changes of the nodes to the database
+// until ctx is cancelled. Unlike notifications they are never dropped,
+// the other replicas rely on the database when they missed notifications,
+// so failed writes are retried.
+func (c *Cluster) writeSessions(ctx context.Context) {
+	delay := minReconnectDelay
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.sessionsChanged:
+		}
+
+		for !c.flushSessions() {
+			log.Error().Dur("retry_in", delay).Msg("Failed to write node sessions, retrying")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			delay = min(delay*2, maxReconnectDelay)
+		}
+
+		delay = minReconnectDelay
+	}
+}
+
+// flushSessions writes the pending session changes and notifies the
+// other replicas of the ones written. It returns false if some of them
+// failed, they are kept to be written again.
+func (c *Cluster) flushSessions() bool {
+	c.sessionsMu.Lock()
+	pending := c.sessions
+	c.sessions = map[key.MachinePublic]bool{}
+	c.sessionsMu.Unlock()
+
+	written := true
+	for machineKey, connected := range pending {
+		var err error
+		kind := kindConnected
+		if connected {
+			err = c.db.AddNodeSession(c.cfg.ReplicaName, machineKey)
+		} else {
+			err = c.db.RemoveNodeSession(c.cfg.ReplicaName, machineKey)
+			kind = kindDisconnected
+		}
+
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("machine_key", machineKey.ShortString()).
+				Bool("connected", connected).
+				Msg("Failed to write node session")
+
+			// Keep the change, unless a newer one replaced it
+			// meanwhile.
+			c.sessionsMu.Lock()
+			if _, ok := c.sessions[machineKey]; !ok {
+				c.sessions[machineKey] = connected
+			}
+			c.sessionsMu.Unlock()
+
+			written = false
+
+			continue
+		}
+
+		msg := message{Kind: kind, MachineKey: machineKey.String()}
+		c.enqueue(func() {
+			c.notify(msg)
+		})
+	}
+
+	return written
+}
+
+// IsConnected implements notifier.Cluster.
+func (c *Cluster) IsConnected(machineKey key.MachinePublic) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.remote[machineKey.String()]) > 0
+}
+
+// PolicyChanged tells the other replicas to load the policy from the
+// database again. It must be called before the nodes are notified of
+// the change.
+func (c *Cluster) PolicyChanged() {
+	c.enqueue(func() {
+		c.notify(message{Kind: kindPolicy})
+	})
+}
+
+// enqueue runs work on the sender goroutine, keeping the order of the
+// notifications without blocking the notifier on the database. The work
+// is dropped if the outbox is full, when the database is too slow or the
+// sender stopped with the context.
+func (c *Cluster) enqueue(work func()) {
+	select {
+	case c.outbox <- work:
+	default:
+		log.Warn().Msg("Cluster outbox is full, dropping notification")
+	}
+}
+
+func (c *Cluster) send(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case work := <-c.outbox:
+			work()
+		}
+	}
+}
+
+func (c *Cluster) notify(msg message) {
+	msg.Replica = c.cfg.ReplicaName
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode cluster message")
+
+		return
+	}
+
+	// Updates too large for a notification are replaced by a full
+	// update of the same nodes.
+	if len(payload) > maxPayloadSize && msg.Update != nil {
+		msg.Update = &update{
+			Type:    types.StateFullUpdate,
+			Message: msg.Update.Message,
+			Ignore:  msg.Update.Ignore,
+		}
+
+		payload, err = json.Marshal(msg)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to encode cluster message")
+
+			return
+		}
+	}
+
+	if err := c.db.Notify(channel, string(payload)); err != nil {
+		log.Error().Err(err).Str("kind", msg.Kind).Msg("Failed to notify the cluster")
+	}
+}
+
+// listen opens a connection listening to the notifications of the
+// other replicas.
+func (c *Cluster) listen(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, c.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		conn.Close(ctx)
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *Cluster) receive(ctx context.Context, conn *pgx.Conn) {
+	delay := minReconnectDelay
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err == nil {
+			delay = minReconnectDelay
+			c.handle(ctx, notification.Payload)
+
+			continue
+		}
+
+		conn.Close(context.Background())
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Error().Err(err).Msg("Lost the connection to the cluster, reconnecting")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			conn, err = c.listen(ctx)
+			if err == nil {
+				break
+			}
+
+			log.Error().Err(err).Dur("retry_in", delay).Msg("Failed to reconnect to the cluster")
+			delay = min(delay*2, maxReconnectDelay)
+		}
+
+		// Notifications sent meanwhile are lost, everything is
+		// loaded again.
+		log.Info().Msg("Reconnected to the cluster")
+
+		if err := c.loadSessions(); err != nil {
+			log.Error().Err(err).Msg("Failed to load node sessions")
+		}
+
+		if c.OnPolicyChanged != nil {
+			c.OnPolicyChanged()
+		}
+
+		c.notifier.NotifyLocalWithIgnore(ctx, types.StateUpdate{
+			Type: types.StateFullUpdate,
+		})
+	}
+}
+
+func (c *Cluster) handle(ctx context.Context, payload string) {
+	var msg message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		log.Error().Err(err).Msg("Failed to decode cluster message")
+
+		return
+	}
+
+	if msg.Replica == c.cfg.ReplicaName {
+		return
+	}
+
+	switch msg.Kind {
+	case kindConnected:
+		c.mu.Lock()
+		if c.remote[msg.MachineKey] == nil {
+			c.remote[msg.MachineKey] = map[string]struct{}{}
+		}
+		c.remote[msg.MachineKey][msg.Replica] = struct{}{}
+		c.mu.Unlock()
+
+	case kindDisconnected:
+		c.mu.Lock()
+		delete(c.remote[msg.MachineKey], msg.Replica)
+		if len(c.remote[msg.MachineKey]) == 0 {
+			delete(c.remote, msg.MachineKey)
+		}
+		c.mu.Unlock()
+
+	case kindPolicy:
+		if c.OnPolicyChanged != nil {
+			c.OnPolicyChanged()
+		}
+
+	case kindDERP:
+		if c.OnDERPMapChanged != nil {
+			c.OnDERPMapChanged()
+		}
+
+	case kindUpdate:
+		if msg.Update != nil {
+			c.deliver(ctx, &msg)
+		}
+	}
+}
+
+// deliver sends an update received from another replica to the nodes
+// connected to this one.
+func (c *Cluster) deliver(ctx context.Context, msg *message) {
+	stateUpdate := types.StateUpdate{
+		Type:          msg.Update.Type,
+		ChangePatches: msg.Update.Patches,
+		Removed:       msg.Update.Removed,
+		Message:       msg.Update.Message,
+	}
+
+	for _, id := range msg.Update.NodeIDs {
+		node, err := c.db.GetNodeByID(id)
+		if err != nil {
+			// The node might have been deleted since.
+			log.Debug().Err(err).Uint64("node_id", id).Msg("Failed to load node of cluster update")
+
+			continue
+		}
+
+		online := c.notifier.IsConnected(node.MachineKey)
+		node.IsOnline = &online
+
+		stateUpdate.ChangeNodes = append(stateUpdate.ChangeNodes, node)
+	}
+
+	if len(msg.Update.NodeIDs) > 0 && len(stateUpdate.ChangeNodes) == 0 {
+		return
+	}
+
+	if msg.MachineKey != "" {
+		var machineKey key.MachinePublic
+		if err := machineKey.UnmarshalText([]byte(msg.MachineKey)); err != nil {
+			log.Error().Err(err).Msg("Failed to decode machine key of cluster update")
+
+			return
+		}
+
+		c.notifier.NotifyLocalByMachineKey(ctx, stateUpdate, machineKey)
+
+		return
+	}
+
+	c.notifier.NotifyLocalWithIgnore(ctx, stateUpdate, msg.Update.Ignore...)
+}
+
+func (c *Cluster) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.db.TouchReplica(c.cfg.ReplicaName); err != nil {
+			log.Error().Err(err).Msg("Failed to send cluster heartbeat")
+
+			continue
+		}
+
+		lost, err := c.db.RemoveStaleReplicas(c.staleBefore())
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to remove stale replicas")
+		}
+
+		if err := c.loadSessions(); err != nil {
+			log.Error().Err(err).Msg("Failed to load node sessions")
+		}
+
+		for _, session := range lost {
+			c.nodeLost(session)
+		}
+	}
+}
+
+func (c *Cluster) nodeLost(session types.NodeSession) {
+	var machineKey key.MachinePublic
+	if err := machineKey.UnmarshalText([]byte(session.MachineKey)); err != nil {
+		return
+	}
+
+	// The node might have reconnected to another replica already.
+	if c.notifier.IsConnected(machineKey) {
+		return
+	}
+
+	node, err := c.db.GetNodeByMachineKey(machineKey)
+	if err != nil {
+		return
+	}
+
+	log.Info().
+		Str("node", node.Hostname).
+		Str("replica", session.Replica).
+		Msg("Replica of node is gone, marking the node offline")
+
+	if c.OnNodeLost != nil {
+		c.OnNodeLost(node)
+	}
+}
+
+// staleBefore returns the time replicas must have sent a heartbeat
+// after to be alive.
+func (c *Cluster) staleBefore() time.Time {
+	return time.Now().Add(-staleHeartbeats * c.cfg.HeartbeatInterval)
+}
+
+// loadSessions loads the nodes connected to the other replicas that are
+// alive.
+func (c *Cluster) loadSessions() error {
+	sessions, err := c.db.ListNodeSessions(c.staleBefore())
+	if err != nil {
+		return err
+	}
+
+	remote := map[string]map[string]struct{}{}
+	for _, session := range sessions {
+		if session.Replica == c.cfg.ReplicaName {
+			continue
+		}
+
+		if remote[session.MachineKey] == nil {
+			remote[session.MachineKey] = map[string]struct{}{}
+		}
+		remote[session.MachineKey][session.Replica] = struct{}{}
+	}
+
+	c.mu.Lock()
+	c.remote = remote
+	c.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/db"
+	"github.com/juanfont/headscale/hscontrol/events"
+	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"tailscale.com/types/key"
+)
+
+func TestHandleSessions(t *testing.T) {
+	cluster := New(types.HAConfig{ReplicaName: "a"}, "", nil, nil)
+	machineKey := key.NewMachine().Public()
+
+	send := func(replica, kind string) {
+		payload, err := json.Marshal(message{
+			Replica:    replica,
+			Kind:       kind,
+			MachineKey: machineKey.String(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cluster.handle(context.Background(), string(payload))
+	}
+
+	tests := []struct {
+		name    string
+		replica string
+		kind    string
+		want    bool
+	}{
+		{"own replica is ignored", "a", kindConnected, false},
+		{"connected", "b", kindConnected, true},
+		{"connected to another replica", "c", kindConnected, true},
+		{"disconnected from one replica", "b", kindDisconnected, true},
+		{"disconnected from all replicas", "c", kindDisconnected, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			send(tt.replica, tt.kind)
+
+			if got := cluster.IsConnected(machineKey); got != tt.want {
+				t.Errorf("IsConnected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueDoesNotBlock(t *testing.T) {
+	cluster := New(types.HAConfig{ReplicaName: "a"}, "", nil, nil)
+
+	done := make(chan struct{})
+	go func() {
+		// Nothing drains the outbox, the work past its size is dropped.
+		for i := 0; i < outboxSize+1; i++ {
+			cluster.enqueue(func() {})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("enqueue blocked on a full outbox")
+	}
+
+	if got := len(cluster.outbox); got != outboxSize {
+		t.Errorf("len(outbox) = %d, want %d", got, outboxSize)
+	}
+}
+
+func TestSessionsAreNotDroppedWithNotifications(t *testing.T) {
+	database, err := db.NewHeadscaleDatabase(
+		"sqlite3",
+		t.TempDir()+"/headscale_test.db",
+		false,
+		notifier.NewNotifier(),
+		events.NewBroker(),
+		[]netip.Prefix{netip.MustParsePrefix("10.27.0.0/23")},
+		nil,
+		types.IPAllocationConfig{},
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := New(types.HAConfig{ReplicaName: "a"}, "", database, nil)
+	if err := database.TouchReplica("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing drains the outbox, the notifications are dropped but the
+	// sessions must still be written.
+	for i := 0; i < outboxSize; i++ {
+		cluster.enqueue(func() {})
+	}
+
+	connected := key.NewMachine().Public()
+	disconnected := key.NewMachine().Public()
+
+	cluster.Connected(connected)
+	cluster.Connected(disconnected)
+	cluster.Disconnected(disconnected)
+
+	if !cluster.flushSessions() {
+		t.Fatal("flushSessions() failed")
+	}
+
+	sessions, err := database.ListNodeSessions(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sessions) != 1 || sessions[0].MachineKey != connected.String() {
+		t.Errorf("sessions = %+v, want only %s", sessions, connected.ShortString())
+	}
+}
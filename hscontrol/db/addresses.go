@@ -6,62 +6,523 @@
 package db
 
 import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/netip"
+	"time"
 
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
+	"github.com/rs/zerolog/log"
 	"go4.org/netipx"
 )
 
-var ErrCouldNotAllocateIP = errors.New("could not find any suitable IP")
+var (
+	ErrCouldNotAllocateIP = errors.New("could not find any suitable IP")
+	ErrIPOutsidePrefixes  = errors.New("address is not within ip_prefixes")
+	ErrIPNotAssignable    = errors.New(
+		"address is the network or broadcast address of its prefix",
+	)
+	ErrIPInUse       = errors.New("address is used by another node")
+	ErrIPReserved    = errors.New("address is reserved")
+	ErrIPOutsidePool = errors.New("address is not within the ip pool of the node")
+	ErrIPQuarantined = errors.New("address is quarantined after being released")
+)
+
+// ipAllocationLockID is the key of the PostgreSQL advisory lock held
+// while allocating addresses, "hsip" in ASCII.
+const ipAllocationLockID = 0x68736970
+
+// lockIPAllocation serialises the allocation of addresses within this
+// process and, on PostgreSQL, with the other replicas sharing the
+// database. The returned function releases the lock.
+func (hsdb *HSDatabase) lockIPAllocation() (func(), error) {
+	hsdb.ipAllocationMutex.Lock()
+
+	if hsdb.dbType != Postgres {
+		return hsdb.ipAllocationMutex.Unlock, nil
+	}
+
+	ctx := context.Background()
+
+	sqlDB, err := hsdb.db.DB()
+	if err != nil {
+		hsdb.ipAllocationMutex.Unlock()
+
+		return nil, err
+	}
+
+	// Advisory locks belong to a connection, the same one has to
+	// release it.
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		hsdb.ipAllocationMutex.Unlock()
 
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", ipAllocationLockID); err != nil {
+		conn.Close()
+		hsdb.ipAllocationMutex.Unlock()
+
+		return nil, fmt.Errorf("failed to lock IP allocation: %w", err)
+	}
+
+	return func() {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", ipAllocationLockID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to unlock IP allocation, closing the connection")
+
+			// Closing the connection releases the lock, it must not go
+			// back to the pool still holding it.
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+		hsdb.ipAllocationMutex.Unlock()
+	}, nil
+}
+
+// getAvailableIPs returns an available address of every ip_prefixes
+// entry for a node that is not in any IP pool and has no reserved
+// address.
 func (hsdb *HSDatabase) getAvailableIPs() (types.NodeAddresses, error) {
+	return hsdb.getAvailableIPsFromPool(nil, nil)
+}
+
+// getAvailableIPsForNode returns an available address of every
+// ip_prefixes entry for node, taking its IP pool and the addresses
+// reserved for it into account.
+func (hsdb *HSDatabase) getAvailableIPsForNode(node *types.Node) (types.NodeAddresses, error) {
+	pool, err := hsdb.ipPoolForNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := hsdb.listIPReservations()
+	if err != nil {
+		return nil, err
+	}
+
+	return hsdb.getAvailableIPsFromPool(pool, reservations.ForNode(node))
+}
+
+// getAvailableIPsFromPool returns an available address of every
+// ip_prefixes entry for a node of pool. A reserved address within the
+// entry is used if it is free, otherwise the address comes from the
+// prefixes of the pool within the entry if it has any. Nodes outside of
+// a pool never get an address of a pool, and no node gets an address
+// reserved for another one or still quarantined after being released.
+func (hsdb *HSDatabase) getAvailableIPsFromPool(
+	pool *types.IPPool,
+	reservedIps []netip.Addr,
+) (types.NodeAddresses, error) {
+	usedIps, err := hsdb.getUsedIPs()
+	if err != nil {
+		return nil, err
+	}
+
+	allReservedIps, err := hsdb.getReservedIPs()
+	if err != nil {
+		return nil, err
+	}
+
+	quarantinedIps, err := hsdb.getQuarantinedIPs()
+	if err != nil {
+		return nil, err
+	}
+
+	poolIps, err := hsdb.ipPools.IPSet()
+	if err != nil {
+		return nil, err
+	}
+
+	var poolExcluded netipx.IPSetBuilder
+	poolExcluded.AddSet(allReservedIps)
+	poolExcluded.AddSet(quarantinedIps)
+
+	poolExcludedIps, err := poolExcluded.IPSet()
+	if err != nil {
+		return nil, err
+	}
+
+	var excluded netipx.IPSetBuilder
+	excluded.AddSet(poolExcludedIps)
+	excluded.AddSet(poolIps)
+
+	excludedIps, err := excluded.IPSet()
+	if err != nil {
+		return nil, err
+	}
+
 	var ips types.NodeAddresses
-	var err error
 	for _, ipPrefix := range hsdb.ipPrefixes {
+		if ip, ok := reservedIPWithin(reservedIps, ipPrefix, usedIps); ok {
+			ips = append(ips, ip)
+
+			continue
+		}
+
 		var ip *netip.Addr
-		ip, err = hsdb.getAvailableIP(ipPrefix)
+
+		poolPrefixes := poolPrefixesWithin(pool, ipPrefix)
+		if len(poolPrefixes) == 0 {
+			ip, err = getAvailableIP(ipPrefix, usedIps, excludedIps, hsdb.ipAllocation.Strategy)
+		}
+
+		for _, poolPrefix := range poolPrefixes {
+			ip, err = getAvailableIP(poolPrefix, usedIps, poolExcludedIps, hsdb.ipAllocation.Strategy)
+			if err == nil {
+				break
+			}
+		}
+
 		if err != nil {
 			return ips, err
 		}
 		ips = append(ips, *ip)
 	}
 
-	return ips, err
+	return ips, nil
 }
 
-func (hsdb *HSDatabase) getAvailableIP(ipPrefix netip.Prefix) (*netip.Addr, error) {
-	usedIps, err := hsdb.getUsedIPs()
+// reservedIPWithin returns the first of reservedIps within ipPrefix
+// that is not used by a node.
+func reservedIPWithin(
+	reservedIps []netip.Addr,
+	ipPrefix netip.Prefix,
+	usedIps *netipx.IPSet,
+) (netip.Addr, bool) {
+	for _, ip := range reservedIps {
+		if !ipPrefix.Contains(ip) {
+			continue
+		}
+
+		if usedIps.Contains(ip) {
+			log.Warn().
+				Str("ip", ip.String()).
+				Msg("Reserved address is already used by another node, allocating a new one")
+
+			continue
+		}
+
+		return ip, true
+	}
+
+	return netip.Addr{}, false
+}
+
+// poolPrefixesWithin returns the prefixes of pool within ipPrefix.
+func poolPrefixesWithin(pool *types.IPPool, ipPrefix netip.Prefix) []netip.Prefix {
+	if pool == nil {
+		return nil
+	}
+
+	var prefixes []netip.Prefix
+	for _, prefix := range pool.Prefixes {
+		if ipPrefix.Bits() <= prefix.Bits() && ipPrefix.Contains(prefix.Addr()) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return prefixes
+}
+
+// getAvailableIP returns an address of ipPrefix that is neither used
+// nor excluded, the first one or one picked at random depending on
+// strategy. The free space is computed as a set of ranges so it stays
+// fast on large prefixes with many nodes.
+func getAvailableIP(
+	ipPrefix netip.Prefix,
+	usedIps *netipx.IPSet,
+	excludedIps *netipx.IPSet,
+	strategy string,
+) (*netip.Addr, error) {
+	ipPrefixNetworkAddress, ipPrefixBroadcastAddress := util.GetIPPrefixEndpoints(ipPrefix)
+
+	var free netipx.IPSetBuilder
+	free.AddPrefix(ipPrefix)
+	free.Remove(ipPrefixNetworkAddress)
+	free.Remove(ipPrefixBroadcastAddress)
+	free.RemovePrefix(netip.MustParsePrefix("127.0.0.0/8"))
+	free.Remove(netip.IPv6Loopback())
+	free.RemoveSet(usedIps)
+	if excludedIps != nil {
+		free.RemoveSet(excludedIps)
+	}
+
+	freeIps, err := free.IPSet()
 	if err != nil {
 		return nil, err
 	}
 
-	ipPrefixNetworkAddress, ipPrefixBroadcastAddress := util.GetIPPrefixEndpoints(ipPrefix)
+	ranges := freeIps.Ranges()
+	if len(ranges) == 0 {
+		return nil, ErrCouldNotAllocateIP
+	}
+
+	if strategy != types.IPAllocationRandom {
+		ip := ranges[0].From()
+
+		return &ip, nil
+	}
+
+	return randomIP(ranges)
+}
+
+// randomIP returns an address picked uniformly from ranges.
+func randomIP(ranges []netipx.IPRange) (*netip.Addr, error) {
+	sizes := make([]*big.Int, len(ranges))
+	total := new(big.Int)
+	for index, ipRange := range ranges {
+		size := new(big.Int).Sub(addrToInt(ipRange.To()), addrToInt(ipRange.From()))
+		size.Add(size, big.NewInt(1))
 
-	// Get the first IP in our prefix
-	ip := ipPrefixNetworkAddress.Next()
+		sizes[index] = size
+		total.Add(total, size)
+	}
+
+	offset, err := rand.Int(rand.Reader, total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick a random address: %w", err)
+	}
+
+	for index, ipRange := range ranges {
+		if offset.Cmp(sizes[index]) < 0 {
+			ip := intToAddr(
+				new(big.Int).Add(addrToInt(ipRange.From()), offset),
+				ipRange.From().Is4(),
+			)
+
+			return &ip, nil
+		}
+
+		offset.Sub(offset, sizes[index])
+	}
+
+	return nil, ErrCouldNotAllocateIP
+}
+
+func addrToInt(ip netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(ip.AsSlice())
+}
+
+func intToAddr(value *big.Int, is4 bool) netip.Addr {
+	if is4 {
+		var bytes [4]byte
+		value.FillBytes(bytes[:])
+
+		return netip.AddrFrom4(bytes)
+	}
+
+	var bytes [16]byte
+	value.FillBytes(bytes[:])
+
+	return netip.AddrFrom16(bytes)
+}
+
+// releaseIPs quarantines the addresses a node no longer uses.
+func (hsdb *HSDatabase) releaseIPs(ips []netip.Addr) error {
+	if hsdb.ipAllocation.Quarantine <= 0 {
+		return nil
+	}
+
+	for _, ip := range ips {
+		err := hsdb.db.Where("ip = ?", ip.String()).Delete(&types.ReleasedIP{}).Error
+		if err != nil {
+			return err
+		}
 
-	for {
+		err = hsdb.db.Create(&types.ReleasedIP{
+			IP:         ip.String(),
+			ReleasedAt: time.Now().UTC(),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to quarantine released address: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getQuarantinedIPs returns the addresses released during the
+// quarantine period, and forgets the older ones.
+func (hsdb *HSDatabase) getQuarantinedIPs() (*netipx.IPSet, error) {
+	var ips netipx.IPSetBuilder
+	if hsdb.ipAllocation.Quarantine <= 0 {
+		return ips.IPSet()
+	}
+
+	err := hsdb.db.
+		Where("released_at < ?", time.Now().UTC().Add(-hsdb.ipAllocation.Quarantine)).
+		Delete(&types.ReleasedIP{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var released []string
+	if err := hsdb.db.Model(&types.ReleasedIP{}).Pluck("ip", &released).Error; err != nil {
+		return nil, err
+	}
+
+	for _, str := range released {
+		ip, err := netip.ParseAddr(str)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read released ip from database: %w", err)
+		}
+		ips.Add(ip)
+	}
+
+	return ips.IPSet()
+}
+
+// validateStaticIP checks that an address set by an administrator is
+// within ip_prefixes and can be given to a node.
+func (hsdb *HSDatabase) validateStaticIP(ip netip.Addr) error {
+	for _, ipPrefix := range hsdb.ipPrefixes {
 		if !ipPrefix.Contains(ip) {
-			return nil, ErrCouldNotAllocateIP
+			continue
+		}
+
+		network, broadcast := util.GetIPPrefixEndpoints(ipPrefix)
+		if ip == network || ip == broadcast {
+			return ErrIPNotAssignable
 		}
 
-		switch {
-		case ip.Compare(ipPrefixBroadcastAddress) == 0:
-			fallthrough
-		case usedIps.Contains(ip):
-			fallthrough
-		case ip == netip.Addr{} || ip.IsLoopback():
-			ip = ip.Next()
+		return nil
+	}
+
+	return ErrIPOutsidePrefixes
+}
+
+// validateNodeIP checks that ip can be given to node, it must be within
+// the IP pool of node, or outside of every pool if node is not in one,
+// and not quarantined.
+func (hsdb *HSDatabase) validateNodeIP(node *types.Node, ip netip.Addr) error {
+	pool, err := hsdb.ipPoolForNode(node)
+	if err != nil {
+		return err
+	}
+
+	poolIps, err := hsdb.ipPools.IPSet()
+	if err != nil {
+		return err
+	}
+
+	if !hsdb.addressMatchesPool(ip, pool, poolIps) {
+		return ErrIPOutsidePool
+	}
+
+	quarantinedIps, err := hsdb.getQuarantinedIPs()
+	if err != nil {
+		return err
+	}
+
+	if quarantinedIps.Contains(ip) {
+		return ErrIPQuarantined
+	}
+
+	return nil
+}
+
+// ipPoolForNode returns the IP pool the addresses of node are allocated
+// from, or nil if it is not in any pool.
+func (hsdb *HSDatabase) ipPoolForNode(node *types.Node) (*types.IPPool, error) {
+	if len(hsdb.ipPools) == 0 {
+		return nil, nil
+	}
+
+	if node.User.Name == "" && node.UserID != 0 {
+		user := types.User{}
+		if err := hsdb.db.First(&user, node.UserID).Error; err != nil {
+			return nil, fmt.Errorf("failed to find user of node: %w", err)
+		}
+
+		withUser := *node
+		withUser.User = user
+		node = &withUser
+	}
+
+	return hsdb.ipPools.ForNode(node, hsdb.aclPolicy.EffectiveTags(node)), nil
+}
 
+// NodeOutsideIPPool is a node with addresses that do not match its IP
+// pool, Pool is nil for nodes that are not in any pool.
+type NodeOutsideIPPool struct {
+	Node      types.Node
+	Pool      *types.IPPool
+	Addresses []netip.Addr
+}
+
+// ListNodesOutsideIPPools returns the nodes with an address outside of
+// their IP pool, or for nodes outside of any pool, an address of a pool
+// or outside of ip_prefixes.
+func (hsdb *HSDatabase) ListNodesOutsideIPPools() ([]NodeOutsideIPPool, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	nodes, err := hsdb.listNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	poolIps, err := hsdb.ipPools.IPSet()
+	if err != nil {
+		return nil, err
+	}
+
+	outside := []NodeOutsideIPPool{}
+	for index := range nodes {
+		node := &nodes[index]
+		pool := hsdb.ipPools.ForNode(node, hsdb.aclPolicy.EffectiveTags(node))
+
+		var addrs []netip.Addr
+		for _, addr := range node.IPAddresses {
+			if !hsdb.addressMatchesPool(addr, pool, poolIps) {
+				addrs = append(addrs, addr)
+			}
+		}
+
+		if len(addrs) > 0 {
+			outside = append(outside, NodeOutsideIPPool{
+				Node:      *node,
+				Pool:      pool,
+				Addresses: addrs,
+			})
+		}
+	}
+
+	return outside, nil
+}
+
+func (hsdb *HSDatabase) addressMatchesPool(
+	addr netip.Addr,
+	pool *types.IPPool,
+	poolIps *netipx.IPSet,
+) bool {
+	for _, ipPrefix := range hsdb.ipPrefixes {
+		if !ipPrefix.Contains(addr) {
 			continue
+		}
 
-		default:
-			return &ip, nil
+		poolPrefixes := poolPrefixesWithin(pool, ipPrefix)
+		if len(poolPrefixes) == 0 {
+			return !poolIps.Contains(addr)
+		}
+
+		for _, prefix := range poolPrefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
 		}
+
+		return false
 	}
+
+	return false
 }
 
 func (hsdb *HSDatabase) getUsedIPs() (*netipx.IPSet, error) {
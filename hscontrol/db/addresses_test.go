@@ -2,11 +2,16 @@ package db
 
 import (
 	"net/netip"
+	"time"
 
+	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"go4.org/netipx"
 	"gopkg.in/check.v1"
+	"gorm.io/gorm"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
 )
 
 func (s *Suite) TestGetAvailableIp(c *check.C) {
@@ -180,3 +185,190 @@ func (s *Suite) TestGetAvailableIpNodeWithoutIP(c *check.C) {
 	c.Assert(len(ips2), check.Equals, 1)
 	c.Assert(ips2[0].String(), check.Equals, expected.String())
 }
+
+func (s *Suite) TestIPPools(c *check.C) {
+	db.ipPools = types.IPPools{
+		{Tag: "tag:server", Prefixes: []netip.Prefix{netip.MustParsePrefix("10.27.1.0/28")}},
+		{User: "ci", Prefixes: []netip.Prefix{netip.MustParsePrefix("10.27.0.0/30")}},
+	}
+	db.SetACLPolicy(&policy.ACLPolicy{
+		TagOwners: map[string][]string{"tag:server": {"other"}},
+	})
+	defer func() {
+		db.ipPools = nil
+		db.SetACLPolicy(nil)
+	}()
+
+	ci, err := db.CreateUser("ci")
+	c.Assert(err, check.IsNil)
+
+	other, err := db.CreateUser("other")
+	c.Assert(err, check.IsNil)
+
+	register := func(hostname string, user *types.User, tags ...string) (*types.Node, error) {
+		return db.RegisterNode(types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			Hostname:       hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodCLI,
+			ForcedTags:     tags,
+		})
+	}
+
+	// Nodes outside of any pool skip the addresses of the pools.
+	node, err := register("other", other)
+	c.Assert(err, check.IsNil)
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.0.4")
+
+	node, err = register("ci-1", ci)
+	c.Assert(err, check.IsNil)
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.0.1")
+
+	node, err = register("ci-2", ci)
+	c.Assert(err, check.IsNil)
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.0.2")
+
+	// Tags take precedence over the pool of the user.
+	node, err = register("ci-server", ci, "tag:server")
+	c.Assert(err, check.IsNil)
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.1.1")
+
+	// And so do the tags the node requests, once the ACL policy allows them.
+	requested, err := db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "other-server",
+		UserID:         other.ID,
+		RegisterMethod: util.RegisterMethodCLI,
+		Hostinfo:       &tailcfg.Hostinfo{RequestTags: []string{"tag:server"}},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(requested.IPAddresses[0].String(), check.Equals, "10.27.1.2")
+
+	// The pool of ci is exhausted, the broadcast address is skipped.
+	_, err = register("ci-3", ci)
+	c.Assert(err, check.Equals, ErrCouldNotAllocateIP)
+
+	outside, err := db.ListNodesOutsideIPPools()
+	c.Assert(err, check.IsNil)
+	c.Assert(outside, check.HasLen, 0)
+
+	// A node keeps its address when it is moved to another pool.
+	err = db.SetTags(node, []string{"tag:laptop"})
+	c.Assert(err, check.IsNil)
+
+	outside, err = db.ListNodesOutsideIPPools()
+	c.Assert(err, check.IsNil)
+	c.Assert(outside, check.HasLen, 1)
+	c.Assert(outside[0].Node.Hostname, check.Equals, "ci-server")
+	c.Assert(outside[0].Pool.String(), check.Equals, "user:ci")
+	c.Assert(outside[0].Addresses, check.DeepEquals, []netip.Addr{netip.MustParseAddr("10.27.1.1")})
+}
+
+func (s *Suite) TestGetAvailableIPRandom(c *check.C) {
+	prefix := netip.MustParsePrefix("100.64.0.0/10")
+
+	// Leave a single free address in the first /16 and fill the rest.
+	var used netipx.IPSetBuilder
+	used.AddPrefix(netip.MustParsePrefix("100.64.0.0/16"))
+	used.Remove(netip.MustParseAddr("100.64.12.34"))
+	used.AddPrefix(netip.MustParsePrefix("100.65.0.0/16"))
+	used.AddPrefix(netip.MustParsePrefix("100.66.0.0/15"))
+	used.AddPrefix(netip.MustParsePrefix("100.68.0.0/14"))
+	used.AddPrefix(netip.MustParsePrefix("100.72.0.0/13"))
+	used.AddPrefix(netip.MustParsePrefix("100.80.0.0/12"))
+	used.AddPrefix(netip.MustParsePrefix("100.96.0.0/11"))
+	used.Remove(netip.MustParseAddr("100.127.255.254"))
+	usedIps, err := used.IPSet()
+	c.Assert(err, check.IsNil)
+
+	ip, err := getAvailableIP(prefix, usedIps, nil, types.IPAllocationSequential)
+	c.Assert(err, check.IsNil)
+	c.Assert(ip.String(), check.Equals, "100.64.12.34")
+
+	seen := map[netip.Addr]bool{}
+	for i := 0; i < 100; i++ {
+		ip, err := getAvailableIP(prefix, usedIps, nil, types.IPAllocationRandom)
+		c.Assert(err, check.IsNil)
+		c.Assert(usedIps.Contains(*ip), check.Equals, false)
+		seen[*ip] = true
+	}
+	c.Assert(seen, check.HasLen, 2)
+
+	// The whole /10 is free, addresses are spread over it.
+	seen = map[netip.Addr]bool{}
+	for i := 0; i < 100; i++ {
+		ip, err := getAvailableIP(prefix, &netipx.IPSet{}, nil, types.IPAllocationRandom)
+		c.Assert(err, check.IsNil)
+		c.Assert(prefix.Contains(*ip), check.Equals, true)
+		c.Assert(ip.String(), check.Not(check.Equals), "100.64.0.0")
+		c.Assert(ip.String(), check.Not(check.Equals), "100.127.255.255")
+		seen[*ip] = true
+	}
+	c.Assert(len(seen) > 90, check.Equals, true)
+
+	ip, err = getAvailableIP(
+		netip.MustParsePrefix("fd7a:115c:a1e0::/48"),
+		&netipx.IPSet{},
+		nil,
+		types.IPAllocationRandom,
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(netip.MustParsePrefix("fd7a:115c:a1e0::/48").Contains(*ip), check.Equals, true)
+}
+
+func (s *Suite) TestIPQuarantine(c *check.C) {
+	db.ipAllocation = types.IPAllocationConfig{
+		Strategy:   types.IPAllocationSequential,
+		Quarantine: time.Hour,
+	}
+	defer func() { db.ipAllocation = types.IPAllocationConfig{} }()
+
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	register := func(hostname string) *types.Node {
+		node, err := db.RegisterNode(types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			Hostname:       hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodCLI,
+		})
+		c.Assert(err, check.IsNil)
+
+		return node
+	}
+
+	node := register("deleted")
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.0.1")
+
+	err = db.DeleteNode(node)
+	c.Assert(err, check.IsNil)
+
+	// The address of the deleted node is not reused right away.
+	node = register("new")
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.0.2")
+
+	// Nor is the address replaced by SetNodeIP.
+	err = db.SetNodeIP(node, netip.MustParseAddr("10.27.0.100"))
+	c.Assert(err, check.IsNil)
+
+	node = register("newer")
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.0.3")
+
+	// Once the quarantine is over the addresses are free again.
+	err = db.db.Session(&gorm.Session{AllowGlobalUpdate: true}).
+		Model(&types.ReleasedIP{}).
+		Update("released_at", time.Now().Add(-2*time.Hour)).Error
+	c.Assert(err, check.IsNil)
+
+	node = register("latest")
+	c.Assert(node.IPAddresses[0].String(), check.Equals, "10.27.0.1")
+
+	var count int64
+	err = db.db.Model(&types.ReleasedIP{}).Count(&count).Error
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int64(0))
+}
@@ -19,12 +19,25 @@ const (
 var ErrAPIKeyFailedToParse = errors.New("failed to parse ApiKey")
 
 // CreateAPIKey creates a new ApiKey in a user, and returns it.
+// The key is limited to the given scopes and users, if any.
 func (hsdb *HSDatabase) CreateAPIKey(
 	expiration *time.Time,
+	scopes []string,
+	users []string,
 ) (string, *types.APIKey, error) {
 	hsdb.mu.Lock()
 	defer hsdb.mu.Unlock()
 
+	if err := types.ValidateAPIKeyScopes(scopes); err != nil {
+		return "", nil, err
+	}
+
+	for _, user := range users {
+		if _, err := hsdb.getUser(user); err != nil {
+			return "", nil, fmt.Errorf("failed to limit API key to user %q: %w", user, err)
+		}
+	}
+
 	prefix, err := util.GenerateRandomStringURLSafe(apiPrefixLength)
 	if err != nil {
 		return "", nil, err
@@ -47,6 +60,8 @@ func (hsdb *HSDatabase) CreateAPIKey(
 		Prefix:     prefix,
 		Hash:       hash,
 		Expiration: expiration,
+		Scopes:     scopes,
+		Users:      users,
 	}
 
 	if err := hsdb.db.Save(&key).Error; err != nil {
@@ -69,6 +84,25 @@ func (hsdb *HSDatabase) ListAPIKeys() ([]types.APIKey, error) {
 	return keys, nil
 }
 
+// ListAPIKeysPage returns the API keys of page, and the number of API
+// keys.
+func (hsdb *HSDatabase) ListAPIKeysPage(page Page) ([]types.APIKey, int64, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	var total int64
+	if err := hsdb.db.Model(&types.APIKey{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	keys := []types.APIKey{}
+	if err := hsdb.db.Scopes(page.scope).Find(&keys).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return keys, total, nil
+}
+
 // GetAPIKey returns a ApiKey for a given key.
 func (hsdb *HSDatabase) GetAPIKey(prefix string) (*types.APIKey, error) {
 	hsdb.mu.RLock()
@@ -121,26 +155,37 @@ func (hsdb *HSDatabase) ExpireAPIKey(key *types.APIKey) error {
 }
 
 func (hsdb *HSDatabase) ValidateAPIKey(keyStr string) (bool, error) {
+	key, err := hsdb.AuthenticateAPIKey(keyStr)
+	if err != nil {
+		return false, err
+	}
+
+	return key != nil, nil
+}
+
+// AuthenticateAPIKey returns the ApiKey matching the given key string,
+// or nil if the key has expired.
+func (hsdb *HSDatabase) AuthenticateAPIKey(keyStr string) (*types.APIKey, error) {
 	hsdb.mu.RLock()
 	defer hsdb.mu.RUnlock()
 
 	prefix, hash, found := strings.Cut(keyStr, ".")
 	if !found {
-		return false, ErrAPIKeyFailedToParse
+		return nil, ErrAPIKeyFailedToParse
 	}
 
 	key, err := hsdb.GetAPIKey(prefix)
 	if err != nil {
-		return false, fmt.Errorf("failed to validate api key: %w", err)
+		return nil, fmt.Errorf("failed to validate api key: %w", err)
 	}
 
 	if key.Expiration.Before(time.Now()) {
-		return false, nil
+		return nil, nil
 	}
 
 	if err := bcrypt.CompareHashAndPassword(key.Hash, []byte(hash)); err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return true, nil
+	return key, nil
 }
@@ -1,13 +1,15 @@
 package db
 
 import (
+	"errors"
 	"time"
 
+	"github.com/juanfont/headscale/hscontrol/types"
 	"gopkg.in/check.v1"
 )
 
 func (*Suite) TestCreateAPIKey(c *check.C) {
-	apiKeyStr, apiKey, err := db.CreateAPIKey(nil)
+	apiKeyStr, apiKey, err := db.CreateAPIKey(nil, nil, nil)
 	c.Assert(err, check.IsNil)
 	c.Assert(apiKey, check.NotNil)
 
@@ -24,6 +26,29 @@ func (*Suite) TestCreateAPIKey(c *check.C) {
 	c.Assert(len(keys), check.Equals, 1)
 }
 
+func (*Suite) TestCreateScopedAPIKey(c *check.C) {
+	_, err := db.CreateUser("ci")
+	c.Assert(err, check.IsNil)
+
+	_, apiKey, err := db.CreateAPIKey(
+		nil,
+		[]string{types.APIKeyRolePreAuthKeyIssuer, "ListNodes"},
+		[]string{"ci"},
+	)
+	c.Assert(err, check.IsNil)
+
+	key, err := db.GetAPIKey(apiKey.Prefix)
+	c.Assert(err, check.IsNil)
+	c.Assert(key.Scopes, check.DeepEquals, types.StringList{types.APIKeyRolePreAuthKeyIssuer, "ListNodes"})
+	c.Assert(key.Users, check.DeepEquals, types.StringList{"ci"})
+
+	_, _, err = db.CreateAPIKey(nil, []string{"superuser"}, nil)
+	c.Assert(errors.Is(err, types.ErrAPIKeyInvalidScope), check.Equals, true)
+
+	_, _, err = db.CreateAPIKey(nil, []string{types.APIKeyRoleReadOnly}, []string{"does-not-exist"})
+	c.Assert(errors.Is(err, ErrUserNotFound), check.Equals, true)
+}
+
 func (*Suite) TestAPIKeyDoesNotExist(c *check.C) {
 	key, err := db.GetAPIKey("does-not-exist")
 	c.Assert(err, check.NotNil)
@@ -32,7 +57,7 @@ func (*Suite) TestAPIKeyDoesNotExist(c *check.C) {
 
 func (*Suite) TestValidateAPIKeyOk(c *check.C) {
 	nowPlus2 := time.Now().Add(2 * time.Hour)
-	apiKeyStr, apiKey, err := db.CreateAPIKey(&nowPlus2)
+	apiKeyStr, apiKey, err := db.CreateAPIKey(&nowPlus2, nil, nil)
 	c.Assert(err, check.IsNil)
 	c.Assert(apiKey, check.NotNil)
 
@@ -43,7 +68,7 @@ func (*Suite) TestValidateAPIKeyOk(c *check.C) {
 
 func (*Suite) TestValidateAPIKeyNotOk(c *check.C) {
 	nowMinus2 := time.Now().Add(time.Duration(-2) * time.Hour)
-	apiKeyStr, apiKey, err := db.CreateAPIKey(&nowMinus2)
+	apiKeyStr, apiKey, err := db.CreateAPIKey(&nowMinus2, nil, nil)
 	c.Assert(err, check.IsNil)
 	c.Assert(apiKey, check.NotNil)
 
@@ -52,7 +77,7 @@ func (*Suite) TestValidateAPIKeyNotOk(c *check.C) {
 	c.Assert(valid, check.Equals, false)
 
 	now := time.Now()
-	apiKeyStrNow, apiKey, err := db.CreateAPIKey(&now)
+	apiKeyStrNow, apiKey, err := db.CreateAPIKey(&now, nil, nil)
 	c.Assert(err, check.IsNil)
 	c.Assert(apiKey, check.NotNil)
 
@@ -71,7 +96,7 @@ func (*Suite) TestValidateAPIKeyNotOk(c *check.C) {
 
 func (*Suite) TestExpireAPIKey(c *check.C) {
 	nowPlus2 := time.Now().Add(2 * time.Hour)
-	apiKeyStr, apiKey, err := db.CreateAPIKey(&nowPlus2)
+	apiKeyStr, apiKey, err := db.CreateAPIKey(&nowPlus2, nil, nil)
 	c.Assert(err, check.IsNil)
 	c.Assert(apiKey, check.NotNil)
 
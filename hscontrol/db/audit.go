@@ -0,0 +1,63 @@
+package db
+
+import (
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+)
+
+// AuditEventFilter narrows down the audit events returned by
+// ListAuditEvents, zero values match every event.
+type AuditEventFilter struct {
+	Actor  string
+	Action string
+	Target string
+	Since  time.Time
+	Limit  int
+}
+
+// CreateAuditEvent stores an audit event.
+func (hsdb *HSDatabase) CreateAuditEvent(event *types.AuditEvent) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	return hsdb.db.Create(event).Error
+}
+
+// ListAuditEvents returns the audit events matching the filter, newest
+// first.
+func (hsdb *HSDatabase) ListAuditEvents(
+	filter AuditEventFilter,
+) ([]types.AuditEvent, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	query := hsdb.db.Order("id DESC")
+
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	if filter.Target != "" {
+		query = query.Where("target = ?", filter.Target)
+	}
+
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	events := []types.AuditEvent{}
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"gopkg.in/check.v1"
+)
+
+func (*Suite) TestListAuditEvents(c *check.C) {
+	events := []types.AuditEvent{
+		{Actor: "unix_socket", Action: types.AuditUserCreate, Target: "user:alice"},
+		{Actor: "api_key:abcdef", Action: types.AuditRouteEnable, Target: "route:1"},
+		{Actor: "api_key:abcdef", Action: types.AuditRouteDisable, Target: "route:1"},
+	}
+	for index := range events {
+		err := db.CreateAuditEvent(&events[index])
+		c.Assert(err, check.IsNil)
+	}
+
+	all, err := db.ListAuditEvents(AuditEventFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 3)
+	c.Assert(all[0].Action, check.Equals, types.AuditRouteDisable)
+
+	byActor, err := db.ListAuditEvents(AuditEventFilter{Actor: "api_key:abcdef"})
+	c.Assert(err, check.IsNil)
+	c.Assert(byActor, check.HasLen, 2)
+
+	byTarget, err := db.ListAuditEvents(AuditEventFilter{Target: "route:1", Limit: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(byTarget, check.HasLen, 1)
+	c.Assert(byTarget[0].Action, check.Equals, types.AuditRouteDisable)
+
+	future, err := db.ListAuditEvents(AuditEventFilter{Since: time.Now().Add(time.Hour)})
+	c.Assert(err, check.IsNil)
+	c.Assert(future, check.HasLen, 0)
+}
@@ -0,0 +1,465 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// postgresBackupFormat identifies the logical export made of a
+	// PostgreSQL database, SQLite databases are backed up as a copy of
+	// the database file.
+	postgresBackupFormat = "headscale-postgres-backup"
+
+	migrationsTable = "migrations"
+)
+
+// unbackedTables are the tables left out of PostgreSQL backups: the
+// migrations are recorded on their own, and the replicas and node
+// sessions of high availability mode, and the leases, only describe
+// running instances, as for copyTables.
+var unbackedTables = []string{migrationsTable, "replicas", "node_sessions", "leases"}
+
+// backedTables returns tables without the ones left out of backups.
+func backedTables(tables []string) []string {
+	return slices.DeleteFunc(tables, func(table string) bool {
+		return slices.Contains(unbackedTables, table)
+	})
+}
+
+var (
+	ErrBackupInvalid = errors.New("not a headscale database backup")
+	ErrBackupDBType  = errors.New(
+		"backup was made from another type of database",
+	)
+	ErrBackupSchemaUnknown = errors.New(
+		"backup was made by a newer version of headscale",
+	)
+	ErrBackupSchemaMismatch = errors.New(
+		"backup was made by another version of headscale",
+	)
+)
+
+// sqliteHeader starts every SQLite database file.
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// postgresBackup is the logical export of a PostgreSQL database, the
+// rows of every table are stored as returned by json_agg so they can be
+// restored with json_populate_recordset.
+type postgresBackup struct {
+	Format     string                     `json:"format"`
+	Migrations []string                   `json:"migrations"`
+	Tables     map[string]json.RawMessage `json:"tables"`
+}
+
+// tableRestoreOrder lists the tables other tables refer to, they are
+// restored first and emptied last.
+var tableRestoreOrder = []string{
+	"users",
+	"pre_auth_keys",
+	"pre_auth_key_acl_tags",
+	"nodes",
+	"routes",
+}
+
+// Backup writes a consistent snapshot of the database to w while
+// headscale keeps running. SQLite databases are copied with VACUUM INTO,
+// PostgreSQL databases are exported within a single transaction.
+// The database is only locked while the snapshot is taken, not while it
+// is written to w.
+func (hsdb *HSDatabase) Backup(w io.Writer) error {
+	switch hsdb.dbType {
+	case Sqlite:
+		return hsdb.backupSqlite(w)
+	case Postgres:
+		return hsdb.backupPostgres(w)
+	}
+
+	return fmt.Errorf("%s: %w", hsdb.dbType, errDatabaseNotSupported)
+}
+
+func (hsdb *HSDatabase) backupSqlite(w io.Writer) error {
+	snapshot, err := os.CreateTemp(
+		filepath.Dir(hsdb.connectionAddr),
+		"headscale-backup-*.sqlite",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	snapshot.Close()
+	defer os.Remove(snapshot.Name())
+
+	if err := hsdb.vacuumInto(snapshot.Name()); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	file, err := os.Open(snapshot.Name())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+
+	return err
+}
+
+// vacuumInto writes a copy of the SQLite database to path.
+func (hsdb *HSDatabase) vacuumInto(path string) error {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	return hsdb.db.Exec("VACUUM INTO ?", path).Error
+}
+
+func (hsdb *HSDatabase) backupPostgres(w io.Writer) error {
+	backup, err := hsdb.exportPostgres()
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(backup)
+}
+
+// exportPostgres reads the rows of every table of the PostgreSQL
+// database within a single transaction.
+func (hsdb *HSDatabase) exportPostgres() (*postgresBackup, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	backup := postgresBackup{
+		Format: postgresBackupFormat,
+		Tables: map[string]json.RawMessage{},
+	}
+
+	err := hsdb.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		backup.Migrations, err = appliedMigrations(tx)
+		if err != nil {
+			return err
+		}
+
+		tables, err := tx.Migrator().GetTables()
+		if err != nil {
+			return err
+		}
+
+		for _, table := range backedTables(tables) {
+			var rows string
+			err := tx.Raw(
+				fmt.Sprintf(
+					`SELECT COALESCE(json_agg(t), '[]'::json) FROM %s t`,
+					tx.Statement.Quote(table),
+				),
+			).Scan(&rows).Error
+			if err != nil {
+				return fmt.Errorf("failed to export table %s: %w", table, err)
+			}
+
+			backup.Tables[table] = json.RawMessage(rows)
+		}
+
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+// Restore replaces the content of the database with the backup read
+// from r. The schema version of the backup is checked before anything is
+// replaced: SQLite backups made by an older version are migrated, and
+// PostgreSQL backups must have been made by the same version.
+// All nodes are sent a full update once the database is restored.
+func (hsdb *HSDatabase) Restore(r io.Reader) error {
+	var err error
+	switch hsdb.dbType {
+	case Sqlite:
+		err = hsdb.restoreSqlite(r)
+	case Postgres:
+		err = hsdb.restorePostgres(r)
+	default:
+		err = fmt.Errorf("%s: %w", hsdb.dbType, errDatabaseNotSupported)
+	}
+	if err != nil {
+		return err
+	}
+
+	hsdb.notifier.NotifyAll(hsdb.ctx, types.StateUpdate{
+		Type: types.StateFullUpdate,
+	})
+
+	return nil
+}
+
+func (hsdb *HSDatabase) restoreSqlite(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	header, _ := reader.Peek(len(sqliteHeader))
+	if !bytes.Equal(header, sqliteHeader) {
+		if len(header) > 0 && header[0] == '{' {
+			return ErrBackupDBType
+		}
+
+		return ErrBackupInvalid
+	}
+
+	restorePath := hsdb.connectionAddr + ".restore"
+	defer removeSqliteFiles(restorePath)
+
+	file, err := os.Create(restorePath)
+	if err != nil {
+		return fmt.Errorf("failed to create restore file: %w", err)
+	}
+
+	_, err = io.Copy(file, reader)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write restore file: %w", err)
+	}
+
+	if err := hsdb.validateSqliteBackup(restorePath); err != nil {
+		return err
+	}
+
+	// Opening the backup migrates it to the current schema.
+	restored, err := NewHeadscaleDatabase(
+		Sqlite,
+		restorePath,
+		false,
+		hsdb.notifier,
+		nil,
+		hsdb.ipPrefixes,
+		hsdb.ipPools,
+		hsdb.ipAllocation,
+		hsdb.baseDomain,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	if err := restored.Close(); err != nil {
+		return err
+	}
+
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	hsdb.ipAllocationMutex.Lock()
+	defer hsdb.ipAllocationMutex.Unlock()
+
+	if err := hsdb.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+
+	// Closing the database checkpoints its WAL, leftovers would be
+	// applied to the restored database.
+	os.Remove(hsdb.connectionAddr + "-wal")
+	os.Remove(hsdb.connectionAddr + "-shm")
+	swapErr := os.Rename(restorePath, hsdb.connectionAddr)
+
+	// The database has to be opened again even if the swap failed.
+	dbConn, err := openDB(hsdb.dbType, hsdb.connectionAddr, hsdb.debug)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := instrumentDB(dbConn); err != nil {
+		return err
+	}
+	hsdb.db = dbConn
+
+	if swapErr != nil {
+		return fmt.Errorf("failed to replace database: %w", swapErr)
+	}
+
+	log.Info().Msg("Database restored from backup")
+
+	return nil
+}
+
+// validateSqliteBackup checks that the backup at path is a headscale
+// database with no migration unknown to this version.
+func (hsdb *HSDatabase) validateSqliteBackup(path string) error {
+	dbConn, err := openDB(Sqlite, path, false)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() {
+		if sqlDB, err := dbConn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	if !dbConn.Migrator().HasTable(migrationsTable) {
+		return ErrBackupInvalid
+	}
+
+	migrations, err := appliedMigrations(dbConn)
+	if err != nil {
+		return err
+	}
+
+	return hsdb.checkMigrations(migrations, false)
+}
+
+func (hsdb *HSDatabase) restorePostgres(r io.Reader) error {
+	var backup postgresBackup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return fmt.Errorf("%w: %s", ErrBackupInvalid, err)
+	}
+
+	if backup.Format != postgresBackupFormat {
+		return ErrBackupInvalid
+	}
+
+	if err := hsdb.checkMigrations(backup.Migrations, true); err != nil {
+		return err
+	}
+
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	hsdb.ipAllocationMutex.Lock()
+	defer hsdb.ipAllocationMutex.Unlock()
+
+	return hsdb.db.Transaction(func(tx *gorm.DB) error {
+		tables, err := tx.Migrator().GetTables()
+		if err != nil {
+			return err
+		}
+
+		tables = backedTables(tables)
+		sortTablesForRestore(tables)
+
+		for index := len(tables) - 1; index >= 0; index-- {
+			err := tx.Exec(fmt.Sprintf("DELETE FROM %s", tx.Statement.Quote(tables[index]))).Error
+			if err != nil {
+				return fmt.Errorf("failed to empty table %s: %w", tables[index], err)
+			}
+		}
+
+		for _, table := range tables {
+			rows, ok := backup.Tables[table]
+			if !ok {
+				continue
+			}
+
+			quoted := tx.Statement.Quote(table)
+			err := tx.Exec(
+				fmt.Sprintf(
+					"INSERT INTO %s SELECT * FROM json_populate_recordset(NULL::%s, ?)",
+					quoted,
+					quoted,
+				),
+				string(rows),
+			).Error
+			if err != nil {
+				return fmt.Errorf("failed to restore table %s: %w", table, err)
+			}
+
+			// Continue the ID sequences after the restored rows.
+			if tx.Migrator().HasColumn(table, "id") {
+				if err := resetSequence(tx, table); err != nil {
+					return err
+				}
+			}
+		}
+
+		log.Info().Msg("Database restored from backup")
+
+		return nil
+	})
+}
+
+// checkMigrations returns an error if migrations, the migrations applied
+// to a backup, contains a migration unknown to this version. With exact,
+// the backup must also have all the migrations of this version.
+func (hsdb *HSDatabase) checkMigrations(migrations []string, exact bool) error {
+	for _, id := range migrations {
+		if !slices.Contains(hsdb.migrationIDs, id) {
+			return fmt.Errorf("%w: unknown migration %s", ErrBackupSchemaUnknown, id)
+		}
+	}
+
+	if exact {
+		for _, id := range hsdb.migrationIDs {
+			if !slices.Contains(migrations, id) {
+				return fmt.Errorf("%w: missing migration %s", ErrBackupSchemaMismatch, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// appliedMigrations returns the IDs of the migrations gormigrate applied
+// to the database.
+func appliedMigrations(tx *gorm.DB) ([]string, error) {
+	var migrations []string
+	err := tx.Table(migrationsTable).Order("id").Pluck("id", &migrations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	return migrations, nil
+}
+
+// resetSequence makes the ID sequence of a PostgreSQL table continue
+// after the highest ID of its rows, which were inserted with their IDs.
+func resetSequence(tx *gorm.DB, table string) error {
+	err := tx.Exec(
+		fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence(?, 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s",
+			tx.Statement.Quote(table),
+		),
+		table,
+	).Error
+	if err != nil {
+		return fmt.Errorf("failed to reset sequence of table %s: %w", table, err)
+	}
+
+	return nil
+}
+
+// sortTablesForRestore puts the tables of tableRestoreOrder first, in
+// that order, followed by the other tables by name.
+func sortTablesForRestore(tables []string) {
+	rank := func(table string) int {
+		if index := slices.Index(tableRestoreOrder, table); index >= 0 {
+			return index
+		}
+
+		return len(tableRestoreOrder)
+	}
+
+	slices.SortFunc(tables, func(a, b string) int {
+		if rank(a) != rank(b) {
+			return rank(a) - rank(b)
+		}
+
+		return cmp.Compare(a, b)
+	})
+}
+
+// removeSqliteFiles removes the database at path and its WAL files.
+func removeSqliteFiles(path string) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warn().Err(err).Str("path", path+suffix).Msg("Failed to remove database file")
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *Suite) TestBackupRestore(c *check.C) {
+	_, err := db.CreateUser("before-backup")
+	c.Assert(err, check.IsNil)
+
+	var backup bytes.Buffer
+	err = db.Backup(&backup)
+	c.Assert(err, check.IsNil)
+	c.Assert(bytes.HasPrefix(backup.Bytes(), sqliteHeader), check.Equals, true)
+
+	_, err = db.CreateUser("after-backup")
+	c.Assert(err, check.IsNil)
+
+	err = db.Restore(bytes.NewReader(backup.Bytes()))
+	c.Assert(err, check.IsNil)
+
+	users, err := db.ListUsers()
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 1)
+	c.Assert(users[0].Name, check.Equals, "before-backup")
+
+	// The restored database is used from now on.
+	_, err = db.CreateUser("after-restore")
+	c.Assert(err, check.IsNil)
+
+	_, err = os.Stat(db.connectionAddr + ".restore")
+	c.Assert(errors.Is(err, os.ErrNotExist), check.Equals, true)
+}
+
+// stalledWriter blocks every write until release is closed.
+type stalledWriter struct {
+	writing chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (w *stalledWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.writing) })
+	<-w.release
+
+	return len(p), nil
+}
+
+func (s *Suite) TestBackupDoesNotLockWhileWriting(c *check.C) {
+	w := &stalledWriter{
+		writing: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- db.Backup(w)
+	}()
+
+	<-w.writing
+
+	// The database can be written while the backup is sent.
+	created := make(chan error)
+	go func() {
+		_, err := db.CreateUser("during-backup")
+		created <- err
+	}()
+
+	select {
+	case err := <-created:
+		c.Assert(err, check.IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatal("database locked while the backup is written")
+	}
+
+	close(w.release)
+	c.Assert(<-done, check.IsNil)
+}
+
+func (s *Suite) TestRestoreInvalidBackup(c *check.C) {
+	_, err := db.CreateUser("kept")
+	c.Assert(err, check.IsNil)
+
+	err = db.Restore(strings.NewReader("not a database"))
+	c.Assert(err, check.Equals, ErrBackupInvalid)
+
+	err = db.Restore(strings.NewReader(`{"format": "headscale-postgres-backup"}`))
+	c.Assert(err, check.Equals, ErrBackupDBType)
+
+	// A backup made by a newer version is refused.
+	var backup bytes.Buffer
+	err = db.Backup(&backup)
+	c.Assert(err, check.IsNil)
+
+	path := tmpDir + "/newer.sqlite"
+	err = os.WriteFile(path, backup.Bytes(), 0o600)
+	c.Assert(err, check.IsNil)
+
+	newer, err := openDB(Sqlite, path, false)
+	c.Assert(err, check.IsNil)
+	err = newer.Exec("INSERT INTO migrations (id) VALUES ('299912311200')").Error
+	c.Assert(err, check.IsNil)
+	sqlDB, err := newer.DB()
+	c.Assert(err, check.IsNil)
+	c.Assert(sqlDB.Close(), check.IsNil)
+
+	newerBackup, err := os.Open(path)
+	c.Assert(err, check.IsNil)
+	defer newerBackup.Close()
+
+	err = db.Restore(newerBackup)
+	c.Assert(errors.Is(err, ErrBackupSchemaUnknown), check.Equals, true)
+
+	users, err := db.ListUsers()
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 1)
+}
+
+func (s *Suite) TestCheckMigrations(c *check.C) {
+	older := slices.Clone(db.migrationIDs[:len(db.migrationIDs)-1])
+
+	c.Assert(db.checkMigrations(db.migrationIDs, true), check.IsNil)
+	c.Assert(db.checkMigrations(older, false), check.IsNil)
+	c.Assert(errors.Is(db.checkMigrations(older, true), ErrBackupSchemaMismatch), check.Equals, true)
+	c.Assert(
+		errors.Is(db.checkMigrations(append(older, "299912311200"), false), ErrBackupSchemaUnknown),
+		check.Equals,
+		true,
+	)
+}
+
+func (s *Suite) TestSortTablesForRestore(c *check.C) {
+	tables := []string{"routes", "api_keys", "nodes", "users", "audit_events"}
+	sortTablesForRestore(tables)
+
+	c.Assert(tables, check.DeepEquals, []string{"users", "nodes", "routes", "api_keys", "audit_events"})
+}
+
+func (s *Suite) TestBackedTables(c *check.C) {
+	tables := []string{
+		"users", "migrations", "nodes", "replicas", "node_sessions", "leases", "routes",
+	}
+
+	c.Assert(backedTables(tables), check.DeepEquals, []string{"users", "nodes", "routes"})
+}
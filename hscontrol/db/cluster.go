@@ -0,0 +1,125 @@
+package db
+
+import (
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tailscale.com/types/key"
+)
+
+// Notify sends payload to the replicas listening on the PostgreSQL
+// notification channel.
+func (hsdb *HSDatabase) Notify(channel, payload string) error {
+	return hsdb.db.Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+// TouchReplica records that the replica is alive.
+func (hsdb *HSDatabase) TouchReplica(name string) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	replica := types.Replica{
+		Name:     name,
+		LastSeen: time.Now().UTC(),
+	}
+
+	return hsdb.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&replica).Error
+}
+
+// RemoveReplica removes the replica and the node sessions open on it.
+func (hsdb *HSDatabase) RemoveReplica(name string) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	return hsdb.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("replica = ?", name).Delete(&types.NodeSession{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("name = ?", name).Delete(&types.Replica{}).Error
+	})
+}
+
+// RemoveStaleReplicas removes the replicas not seen since the given time,
+// and returns the node sessions that were open on them. When replicas
+// race to remove the same replica, its sessions are only returned to
+// one of them.
+func (hsdb *HSDatabase) RemoveStaleReplicas(since time.Time) ([]types.NodeSession, error) {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	var sessions []types.NodeSession
+
+	err := hsdb.db.Transaction(func(tx *gorm.DB) error {
+		var stale []types.Replica
+		err := tx.Clauses(clause.Returning{}).
+			Where("last_seen < ?", since.UTC()).
+			Delete(&stale).Error
+		if err != nil {
+			return err
+		}
+
+		if len(stale) == 0 {
+			return nil
+		}
+
+		names := make([]string, len(stale))
+		for index, replica := range stale {
+			names[index] = replica.Name
+		}
+
+		return tx.Clauses(clause.Returning{}).
+			Where("replica IN ?", names).
+			Delete(&sessions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// AddNodeSession records that the node with machineKey opened a poll
+// session on the replica.
+func (hsdb *HSDatabase) AddNodeSession(replica string, machineKey key.MachinePublic) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	session := types.NodeSession{
+		MachineKey: machineKey.String(),
+		Replica:    replica,
+	}
+
+	return hsdb.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&session).Error
+}
+
+// RemoveNodeSession records that the node with machineKey closed its
+// poll session on the replica.
+func (hsdb *HSDatabase) RemoveNodeSession(replica string, machineKey key.MachinePublic) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	return hsdb.db.
+		Where("machine_key = ? AND replica = ?", machineKey.String(), replica).
+		Delete(&types.NodeSession{}).Error
+}
+
+// ListNodeSessions returns the node sessions open on the replicas seen
+// since the given time.
+func (hsdb *HSDatabase) ListNodeSessions(since time.Time) ([]types.NodeSession, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	var sessions []types.NodeSession
+	err := hsdb.db.
+		Joins("JOIN replicas ON replicas.name = node_sessions.replica").
+		Where("replicas.last_seen >= ?", since.UTC()).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
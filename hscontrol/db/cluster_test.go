@@ -0,0 +1,73 @@
+package db
+
+import (
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"gopkg.in/check.v1"
+	"tailscale.com/types/key"
+)
+
+func (s *Suite) TestNodeSessions(c *check.C) {
+	first := key.NewMachine().Public()
+	second := key.NewMachine().Public()
+
+	c.Assert(db.TouchReplica("a"), check.IsNil)
+	c.Assert(db.TouchReplica("b"), check.IsNil)
+
+	c.Assert(db.AddNodeSession("a", first), check.IsNil)
+	c.Assert(db.AddNodeSession("a", first), check.IsNil)
+	c.Assert(db.AddNodeSession("b", second), check.IsNil)
+
+	sessions, err := db.ListNodeSessions(time.Now().Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 2)
+
+	c.Assert(db.RemoveNodeSession("b", second), check.IsNil)
+
+	sessions, err = db.ListNodeSessions(time.Now().Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+	c.Assert(sessions[0].MachineKey, check.Equals, first.String())
+
+	// Sessions of replicas not seen since are not listed.
+	sessions, err = db.ListNodeSessions(time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+
+	c.Assert(db.RemoveReplica("a"), check.IsNil)
+
+	var count int64
+	c.Assert(db.db.Model(&types.NodeSession{}).Count(&count).Error, check.IsNil)
+	c.Assert(count, check.Equals, int64(0))
+}
+
+func (s *Suite) TestRemoveStaleReplicas(c *check.C) {
+	machineKey := key.NewMachine().Public()
+
+	c.Assert(db.TouchReplica("stale"), check.IsNil)
+	c.Assert(db.AddNodeSession("stale", machineKey), check.IsNil)
+
+	err := db.db.Model(&types.Replica{}).
+		Where("name = ?", "stale").
+		Update("last_seen", time.Now().Add(-time.Hour).UTC()).Error
+	c.Assert(err, check.IsNil)
+
+	c.Assert(db.TouchReplica("alive"), check.IsNil)
+
+	lost, err := db.RemoveStaleReplicas(time.Now().Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+	c.Assert(lost, check.HasLen, 1)
+	c.Assert(lost[0].MachineKey, check.Equals, machineKey.String())
+	c.Assert(lost[0].Replica, check.Equals, "stale")
+
+	// The sessions are only returned once.
+	lost, err = db.RemoveStaleReplicas(time.Now().Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+	c.Assert(lost, check.HasLen, 0)
+
+	var replicas []types.Replica
+	c.Assert(db.db.Find(&replicas).Error, check.IsNil)
+	c.Assert(replicas, check.HasLen, 1)
+	c.Assert(replicas[0].Name, check.Equals, "alive")
+}
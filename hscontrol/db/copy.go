@@ -0,0 +1,198 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// copyBatchSize is the number of rows read and written at once when
+// copying a database.
+const copyBatchSize = 500
+
+var (
+	ErrCopyNotEmpty = errors.New(
+		"the destination database is not empty",
+	)
+	ErrCopyCountMismatch = errors.New(
+		"the number of rows copied does not match the source database",
+	)
+	ErrCopySchemaMismatch = errors.New(
+		"the source database was migrated by another version of headscale, " +
+			"start this version on it before copying it",
+	)
+)
+
+// CopiedTable is the number of rows copied from a table.
+type CopiedTable struct {
+	Table string
+	Rows  int64
+}
+
+// copyTable is a table of the schema with the function copying its rows
+// from src to dst, which returns the number of rows in src and in dst.
+type copyTable struct {
+	name string
+	copy func(src, dst *gorm.DB) (int64, int64, error)
+}
+
+// copyTables lists every table of the schema, the tables other tables
+// refer to come first. The replicas and node sessions of high
+// availability mode, and the leases, only describe running instances and
+// are not copied.
+var copyTables = []copyTable{
+	{"users", copyRows[types.User]},
+	{"pre_auth_keys", copyRows[types.PreAuthKey]},
+	{"pre_auth_key_acl_tags", copyRows[types.PreAuthKeyACLTag]},
+	{"nodes", copyRows[types.Node]},
+	{"routes", copyRows[types.Route]},
+	{"api_keys", copyRows[types.APIKey]},
+	{"policies", copyRows[types.Policy]},
+	{"audit_events", copyRows[types.AuditEvent]},
+	{"pending_registrations", copyRows[types.PendingRegistration]},
+	{"ip_reservations", copyRows[types.IPReservation]},
+	{"released_ips", copyRows[types.ReleasedIP]},
+}
+
+// CopyDatabase copies every row of the source database into the
+// destination database, which is migrated to the current schema first
+// and must be empty. Rows keep their primary keys, and are read and
+// written through the models so columns stored as JSON are converted
+// for the destination. The source must have been migrated by this
+// version of headscale, it is read within a single transaction and can
+// be used by a running headscale meanwhile.
+func CopyDatabase(
+	srcType, srcConnectionAddr string,
+	dstType, dstConnectionAddr string,
+) ([]CopiedTable, error) {
+	dst, err := NewHeadscaleDatabase(
+		dstType,
+		dstConnectionAddr,
+		false,
+		notifier.NewNotifier(),
+		nil,
+		nil,
+		nil,
+		types.IPAllocationConfig{},
+		"",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open destination database: %w", err)
+	}
+	defer dst.Close()
+
+	src, err := openDB(srcType, srcConnectionAddr, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open source database: %w", err)
+	}
+	defer func() {
+		if sqlDB, err := src.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	migrations, err := appliedMigrations(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dst.checkMigrations(migrations, true); err != nil {
+		log.Debug().Err(err).Msg("Source database schema does not match")
+
+		return nil, ErrCopySchemaMismatch
+	}
+
+	// PostgreSQL is read from a snapshot, SQLite transactions always
+	// read one.
+	var txOptions []*sql.TxOptions
+	if srcType == Postgres {
+		txOptions = append(txOptions, &sql.TxOptions{
+			Isolation: sql.LevelRepeatableRead,
+			ReadOnly:  true,
+		})
+	}
+
+	var copied []CopiedTable
+
+	err = src.Transaction(func(srcTx *gorm.DB) error {
+		return dst.db.Transaction(func(dstTx *gorm.DB) error {
+			for _, table := range copyTables {
+				var count int64
+				if err := dstTx.Table(table.name).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return fmt.Errorf("%w: table %s has rows", ErrCopyNotEmpty, table.name)
+				}
+			}
+
+			for _, table := range copyTables {
+				srcRows, dstRows, err := table.copy(srcTx, dstTx)
+				if err != nil {
+					return fmt.Errorf("failed to copy table %s: %w", table.name, err)
+				}
+
+				if srcRows != dstRows {
+					return fmt.Errorf(
+						"%w: table %s has %d rows, %d copied",
+						ErrCopyCountMismatch,
+						table.name,
+						srcRows,
+						dstRows,
+					)
+				}
+
+				if dstType == Postgres {
+					if err := resetSequence(dstTx, table.name); err != nil {
+						return err
+					}
+				}
+
+				log.Info().
+					Str("table", table.name).
+					Int64("rows", dstRows).
+					Msg("Table copied")
+
+				copied = append(copied, CopiedTable{Table: table.name, Rows: dstRows})
+			}
+
+			return nil
+		})
+	}, txOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
+
+// copyRows copies all the rows of the model T, soft deleted ones
+// included, and returns the number of rows in src and in dst after the
+// copy.
+func copyRows[T any](src, dst *gorm.DB) (int64, int64, error) {
+	var srcCount int64
+	if err := src.Unscoped().Model(new(T)).Count(&srcCount).Error; err != nil {
+		return 0, 0, err
+	}
+
+	var rows []T
+	err := src.Unscoped().FindInBatches(&rows, copyBatchSize, func(_ *gorm.DB, _ int) error {
+		return dst.Omit(clause.Associations).Create(&rows).Error
+	}).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var dstCount int64
+	if err := dst.Unscoped().Model(new(T)).Count(&dstCount).Error; err != nil {
+		return 0, 0, err
+	}
+
+	return srcCount, dstCount, nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"errors"
+	"net/netip"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"gopkg.in/check.v1"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+)
+
+func (s *Suite) TestCopyDatabase(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	deleted, err := db.CreateUser("deleted")
+	c.Assert(err, check.IsNil)
+	c.Assert(db.db.Delete(deleted).Error, check.IsNil)
+
+	pak, err := db.CreatePreAuthKey(user.Name, false, false, nil, []string{"tag:server"})
+	c.Assert(err, check.IsNil)
+
+	node, err := db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		DiscoKey:       key.NewDisco().Public(),
+		Hostname:       "server",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		AuthKeyID:      uint(pak.ID),
+		ForcedTags:     types.StringList{"tag:forced"},
+		Hostinfo: &tailcfg.Hostinfo{
+			RoutableIPs: []netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")},
+		},
+	})
+	c.Assert(err, check.IsNil)
+
+	_, _, err = db.CreateAPIKey(nil, []string{types.APIKeyRoleReadOnly}, []string{user.Name})
+	c.Assert(err, check.IsNil)
+
+	_, err = db.SetPolicy(`{"acls": []}`)
+	c.Assert(err, check.IsNil)
+
+	copied, err := CopyDatabase(Sqlite, db.connectionAddr, Sqlite, tmpDir+"/copy.db")
+	c.Assert(err, check.IsNil)
+	c.Assert(copied, check.HasLen, len(copyTables))
+
+	rows := map[string]int64{}
+	for _, table := range copied {
+		rows[table.Table] = table.Rows
+	}
+	// The soft deleted user is copied too.
+	c.Assert(rows["users"], check.Equals, int64(2))
+	c.Assert(rows["nodes"], check.Equals, int64(1))
+	c.Assert(rows["pre_auth_key_acl_tags"], check.Equals, int64(1))
+
+	dst, err := openDB(Sqlite, tmpDir+"/copy.db", false)
+	c.Assert(err, check.IsNil)
+
+	var copiedNode types.Node
+	c.Assert(dst.First(&copiedNode, node.ID).Error, check.IsNil)
+	c.Assert(copiedNode.MachineKey, check.Equals, node.MachineKey)
+	c.Assert(copiedNode.IPAddresses, check.DeepEquals, node.IPAddresses)
+	c.Assert(copiedNode.ForcedTags, check.DeepEquals, node.ForcedTags)
+	c.Assert(copiedNode.Hostinfo.RoutableIPs, check.DeepEquals, node.Hostinfo.RoutableIPs)
+
+	var apiKey types.APIKey
+	c.Assert(dst.First(&apiKey).Error, check.IsNil)
+	c.Assert([]string(apiKey.Users), check.DeepEquals, []string{user.Name})
+
+	sqlDB, err := dst.DB()
+	c.Assert(err, check.IsNil)
+	c.Assert(sqlDB.Close(), check.IsNil)
+
+	// The destination must be empty.
+	_, err = CopyDatabase(Sqlite, db.connectionAddr, Sqlite, tmpDir+"/copy.db")
+	c.Assert(errors.Is(err, ErrCopyNotEmpty), check.Equals, true)
+}
+
+func (s *Suite) TestCopyDatabaseSchemaMismatch(c *check.C) {
+	err := db.db.Exec("INSERT INTO migrations (id) VALUES ('299912311200')").Error
+	c.Assert(err, check.IsNil)
+
+	_, err = CopyDatabase(Sqlite, db.connectionAddr, Sqlite, tmpDir+"/copy.db")
+	c.Assert(err, check.Equals, ErrCopySchemaMismatch)
+}
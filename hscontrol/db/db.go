@@ -12,7 +12,9 @@ import (
 
 	"github.com/glebarez/sqlite"
 	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/juanfont/headscale/hscontrol/events"
 	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/rs/zerolog/log"
@@ -38,13 +40,33 @@ type KV struct {
 type HSDatabase struct {
 	db       *gorm.DB
 	notifier *notifier.Notifier
+	events   *events.Broker
 
-	mu sync.RWMutex
+	dbType         string
+	connectionAddr string
+	debug          bool
 
-	ipAllocationMutex sync.Mutex
+	// migrationIDs are the IDs of all the migrations known to this
+	// version of headscale, in order.
+	migrationIDs []string
 
-	ipPrefixes []netip.Prefix
-	baseDomain string
+	// ctx is the context of the queries and notifications, set by
+	// WithContext.
+	ctx context.Context
+
+	// The mutexes are shared with the copies made by WithContext.
+	mu *sync.RWMutex
+
+	ipAllocationMutex *sync.Mutex
+
+	ipPrefixes   []netip.Prefix
+	ipPools      types.IPPools
+	ipAllocation types.IPAllocationConfig
+	baseDomain   string
+
+	// aclPolicy gives the tags of the nodes for the IP pools, set by
+	// SetACLPolicy.
+	aclPolicy *policy.ACLPolicy
 }
 
 // TODO(kradalby): assemble this struct from toptions or something typed
@@ -53,7 +75,10 @@ func NewHeadscaleDatabase(
 	dbType, connectionAddr string,
 	debug bool,
 	notifier *notifier.Notifier,
+	eventBroker *events.Broker,
 	ipPrefixes []netip.Prefix,
+	ipPools types.IPPools,
+	ipAllocation types.IPAllocationConfig,
 	baseDomain string,
 ) (*HSDatabase, error) {
 	dbConn, err := openDB(dbType, connectionAddr, debug)
@@ -61,7 +86,12 @@ func NewHeadscaleDatabase(
 		return nil, err
 	}
 
-	migrations := gormigrate.New(dbConn, gormigrate.DefaultOptions, []*gormigrate.Migration{
+	err = instrumentDB(dbConn)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationList := []*gormigrate.Migration{
 		// New migrations should be added as transactions at the end of this list.
 		// The initial commit here is quite messy, completely out of order and
 		// has no versioning and is the tech debt of not having versioned migrations
@@ -310,23 +340,283 @@ func NewHeadscaleDatabase(
 				return nil
 			},
 		},
-	})
+		{
+			// Add a table for storing the ACL policy, with one row
+			// per version of the policy.
+			ID: "202312181200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.Policy{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&types.Policy{})
+			},
+		},
+		{
+			// Add the audit log of administrative changes.
+			ID: "202312201200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.AuditEvent{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&types.AuditEvent{})
+			},
+		},
+		{
+			// Add the scopes and user limits of API keys.
+			ID: "202312211200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.APIKey{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				err := tx.Migrator().DropColumn(&types.APIKey{}, "scopes")
+				if err != nil {
+					return err
+				}
 
+				return tx.Migrator().DropColumn(&types.APIKey{}, "users")
+			},
+		},
+		{
+			// Store pending registrations so they survive a restart.
+			ID: "202312221200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.PendingRegistration{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&types.PendingRegistration{})
+			},
+		},
+		{
+			// Add the approval state of nodes, existing nodes are
+			// approved.
+			ID: "202312231200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.Node{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&types.Node{}, "pending_approval")
+			},
+		},
+		{
+			// Store the addresses reserved for nodes by hostname or
+			// pre-auth key.
+			ID: "202312241200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.IPReservation{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&types.IPReservation{})
+			},
+		},
+		{
+			// Keep the addresses released by nodes to quarantine them.
+			ID: "202312251200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.ReleasedIP{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&types.ReleasedIP{})
+			},
+		},
+		{
+			// Track the replicas and the nodes connected to each of
+			// them in high availability mode.
+			ID: "202312261200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.Replica{}, &types.NodeSession{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&types.Replica{}, &types.NodeSession{})
+			},
+		},
+		{
+			// Add the leases electing the process running the
+			// background jobs.
+			ID: "202312271200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.Lease{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&types.Lease{})
+			},
+		},
+		{
+			// Record the capability version and the time of the
+			// latest map request of nodes, shown in their diagnostics.
+			ID: "202312281200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.Node{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				err := tx.Migrator().DropColumn(&types.Node{}, "last_map_request")
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().DropColumn(&types.Node{}, "capability_version")
+			},
+		},
+		{
+			// Let nodes opt out of the node_expiry policy.
+			ID: "202312291200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.Node{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&types.Node{}, "expiry_disabled")
+			},
+		},
+		{
+			// Remember the expiries the owners of the nodes were notified of.
+			ID: "202312301200",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&types.Node{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&types.Node{}, "expiry_notified")
+			},
+		},
+	}
+
+	migrations := gormigrate.New(dbConn, gormigrate.DefaultOptions, migrationList)
 	if err = migrations.Migrate(); err != nil {
-		log.Fatal().Err(err).Msgf("Migration failed: %v", err)
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
+	migrationIDs := make([]string, len(migrationList))
+	for index, migration := range migrationList {
+		migrationIDs[index] = migration.ID
 	}
 
 	db := HSDatabase{
 		db:       dbConn,
 		notifier: notifier,
+		events:   eventBroker,
+		ctx:      context.Background(),
+
+		mu:                &sync.RWMutex{},
+		ipAllocationMutex: &sync.Mutex{},
 
-		ipPrefixes: ipPrefixes,
-		baseDomain: baseDomain,
+		dbType:         dbType,
+		connectionAddr: connectionAddr,
+		debug:          debug,
+		migrationIDs:   migrationIDs,
+
+		ipPrefixes:   ipPrefixes,
+		ipPools:      ipPools,
+		ipAllocation: ipAllocation,
+		baseDomain:   baseDomain,
 	}
 
 	return &db, err
 }
 
+// instrumentDB records the duration of the queries made through db in
+// the metrics, and traces them.
+func instrumentDB(db *gorm.DB) error {
+	if err := registerQueryMetrics(db); err != nil {
+		return err
+	}
+
+	return registerQueryTracing(db)
+}
+
+// registerQueryCallbacks registers before and after around every kind of
+// query made through db, they are given the operation of the query.
+func registerQueryCallbacks(
+	db *gorm.DB,
+	name string,
+	before, after func(operation string, tx *gorm.DB),
+) error {
+	callbacks := db.Callback()
+
+	type register func(name string, fn func(*gorm.DB)) error
+
+	processors := []struct {
+		operation string
+		before    register
+		after     register
+	}{
+		{
+			"create",
+			callbacks.Create().Before("gorm:create").Register,
+			callbacks.Create().After("gorm:create").Register,
+		},
+		{
+			"query",
+			callbacks.Query().Before("gorm:query").Register,
+			callbacks.Query().After("gorm:query").Register,
+		},
+		{
+			"update",
+			callbacks.Update().Before("gorm:update").Register,
+			callbacks.Update().After("gorm:update").Register,
+		},
+		{
+			"delete",
+			callbacks.Delete().Before("gorm:delete").Register,
+			callbacks.Delete().After("gorm:delete").Register,
+		},
+		{
+			"row",
+			callbacks.Row().Before("gorm:row").Register,
+			callbacks.Row().After("gorm:row").Register,
+		},
+		{
+			"raw",
+			callbacks.Raw().Before("gorm:raw").Register,
+			callbacks.Raw().After("gorm:raw").Register,
+		},
+	}
+
+	for _, processor := range processors {
+		operation := processor.operation
+
+		err := processor.before(
+			fmt.Sprintf("headscale:%s_before_%s", name, operation),
+			func(tx *gorm.DB) { before(operation, tx) },
+		)
+		if err != nil {
+			return err
+		}
+
+		err = processor.after(
+			fmt.Sprintf("headscale:%s_after_%s", name, operation),
+			func(tx *gorm.DB) { after(operation, tx) },
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithContext returns a copy of the database making its queries, and
+// sending its notifications, within ctx, so they are traced as part of
+// the request of ctx. The copy must not be used to restore the
+// database.
+func (hsdb *HSDatabase) WithContext(ctx context.Context) *HSDatabase {
+	// The connection is replaced when the database is restored.
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	copied := *hsdb
+	copied.ctx = ctx
+	copied.db = hsdb.db.WithContext(ctx)
+
+	return &copied
+}
+
+// SetACLPolicy sets the ACL policy the tags requested by the nodes are
+// checked against.
+func (hsdb *HSDatabase) SetACLPolicy(pol *policy.ACLPolicy) {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	hsdb.aclPolicy = pol
+}
+
 func openDB(dbType, connectionAddr string, debug bool) (*gorm.DB, error) {
 	log.Debug().Str("type", dbType).Str("connection", connectionAddr).Msg("opening database")
 
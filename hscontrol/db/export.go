@@ -0,0 +1,229 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrExportVersion = errors.New(
+		"export was made by a newer version of headscale",
+	)
+	ErrImportNotEmpty = errors.New(
+		"the database must be empty to import an export",
+	)
+)
+
+// importTables are the tables that must be empty before an export is
+// imported. API keys are not checked, the key used to run the import
+// may be in the database already.
+var importTables = []any{
+	&types.User{},
+	&types.PreAuthKey{},
+	&types.Node{},
+	&types.Route{},
+	&types.IPReservation{},
+	&types.Policy{},
+}
+
+// Export returns the users, pre-auth keys, nodes, routes, API key hashes,
+// IP reservations and policy stored in the database, read within a
+// single transaction.
+func (hsdb *HSDatabase) Export() (*types.Export, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	export := &types.Export{
+		Version:        types.ExportVersion,
+		ExportedAt:     time.Now().UTC(),
+		Users:          []types.ExportUser{},
+		PreAuthKeys:    []types.ExportPreAuthKey{},
+		Nodes:          []types.ExportNode{},
+		Routes:         []types.ExportRoute{},
+		APIKeys:        []types.ExportAPIKey{},
+		IPReservations: []types.ExportIPReservation{},
+	}
+
+	err := hsdb.db.Transaction(func(tx *gorm.DB) error {
+		var users []types.User
+		if err := tx.Order("id").Find(&users).Error; err != nil {
+			return fmt.Errorf("failed to export users: %w", err)
+		}
+		for index := range users {
+			export.Users = append(export.Users, users[index].Export())
+		}
+
+		var keys []types.PreAuthKey
+		if err := tx.Preload("ACLTags").Order("id").Find(&keys).Error; err != nil {
+			return fmt.Errorf("failed to export pre-auth keys: %w", err)
+		}
+		for index := range keys {
+			export.PreAuthKeys = append(export.PreAuthKeys, keys[index].Export())
+		}
+
+		var nodes types.Nodes
+		if err := tx.Order("id").Find(&nodes).Error; err != nil {
+			return fmt.Errorf("failed to export nodes: %w", err)
+		}
+		for _, node := range nodes {
+			export.Nodes = append(export.Nodes, node.Export())
+		}
+
+		var routes types.Routes
+		if err := tx.Order("id").Find(&routes).Error; err != nil {
+			return fmt.Errorf("failed to export routes: %w", err)
+		}
+		for index := range routes {
+			export.Routes = append(export.Routes, routes[index].Export())
+		}
+
+		var apiKeys []types.APIKey
+		if err := tx.Order("id").Find(&apiKeys).Error; err != nil {
+			return fmt.Errorf("failed to export API keys: %w", err)
+		}
+		for index := range apiKeys {
+			export.APIKeys = append(export.APIKeys, apiKeys[index].Export())
+		}
+
+		var reservations types.IPReservations
+		if err := tx.Order("id").Find(&reservations).Error; err != nil {
+			return fmt.Errorf("failed to export IP reservations: %w", err)
+		}
+		for index := range reservations {
+			export.IPReservations = append(export.IPReservations, reservations[index].Export())
+		}
+
+		var pol types.Policy
+		err := tx.Order("id DESC").Limit(1).Find(&pol).Error
+		if err != nil {
+			return fmt.Errorf("failed to export policy: %w", err)
+		}
+		if pol.ID != 0 {
+			export.Policy = &types.ExportPolicy{
+				Data:      pol.Data,
+				UpdatedAt: pol.UpdatedAt,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// Import loads an export into the database, which must not hold any
+// user, node, pre-auth key, route, IP reservation or policy yet.
+// Everything is imported within a single transaction and keeps its ID,
+// except API keys which are added to the keys already in the database.
+func (hsdb *HSDatabase) Import(export *types.Export) error {
+	if export.Version < 1 || export.Version > types.ExportVersion {
+		return fmt.Errorf("%w: version %d", ErrExportVersion, export.Version)
+	}
+
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	hsdb.ipAllocationMutex.Lock()
+	defer hsdb.ipAllocationMutex.Unlock()
+
+	return hsdb.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range importTables {
+			var count int64
+			if err := tx.Model(model).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrImportNotEmpty
+			}
+		}
+
+		for index := range export.Users {
+			user := export.Users[index].User()
+			if err := tx.Create(&user).Error; err != nil {
+				return fmt.Errorf("failed to import user %q: %w", user.Name, err)
+			}
+		}
+
+		for index := range export.PreAuthKeys {
+			key := export.PreAuthKeys[index].PreAuthKey()
+			if err := tx.Omit("User").Create(&key).Error; err != nil {
+				return fmt.Errorf("failed to import pre-auth key %d: %w", key.ID, err)
+			}
+		}
+
+		for index := range export.Nodes {
+			node := export.Nodes[index].Node()
+			if err := tx.Omit(clause.Associations).Create(&node).Error; err != nil {
+				return fmt.Errorf("failed to import node %q: %w", node.Hostname, err)
+			}
+		}
+
+		for index := range export.Routes {
+			route := export.Routes[index].Route()
+			if err := tx.Omit(clause.Associations).Create(&route).Error; err != nil {
+				return fmt.Errorf("failed to import route %s: %w", route.String(), err)
+			}
+		}
+
+		for index := range export.IPReservations {
+			reservation := export.IPReservations[index].IPReservation()
+			if err := tx.Omit(clause.Associations).Create(&reservation).Error; err != nil {
+				return fmt.Errorf("failed to import IP reservation %s: %w", reservation.IP, err)
+			}
+		}
+
+		for index := range export.APIKeys {
+			key := export.APIKeys[index].APIKey()
+
+			var count int64
+			if err := tx.Model(&types.APIKey{}).Where("prefix = ?", key.Prefix).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				log.Warn().
+					Str("prefix", key.Prefix).
+					Msg("API key is already in the database, not importing it")
+
+				continue
+			}
+
+			if err := tx.Create(&key).Error; err != nil {
+				return fmt.Errorf("failed to import API key %s: %w", key.Prefix, err)
+			}
+		}
+
+		if export.Policy != nil {
+			pol := types.Policy{Data: export.Policy.Data}
+			pol.UpdatedAt = export.Policy.UpdatedAt
+			if err := tx.Create(&pol).Error; err != nil {
+				return fmt.Errorf("failed to import policy: %w", err)
+			}
+		}
+
+		// Objects were inserted with their IDs, the sequences of
+		// PostgreSQL must continue after them.
+		if hsdb.dbType == Postgres {
+			for _, table := range []string{"users", "pre_auth_keys", "nodes", "routes"} {
+				if err := resetSequence(tx, table); err != nil {
+					return err
+				}
+			}
+		}
+
+		log.Info().
+			Int("users", len(export.Users)).
+			Int("nodes", len(export.Nodes)).
+			Msg("Export imported")
+
+		return nil
+	})
+}
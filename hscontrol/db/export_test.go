@@ -0,0 +1,123 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+
+	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"gopkg.in/check.v1"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+)
+
+func (s *Suite) TestExportImport(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	pak, err := db.CreatePreAuthKey(user.Name, true, false, nil, []string{"tag:server"})
+	c.Assert(err, check.IsNil)
+
+	node, err := db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		DiscoKey:       key.NewDisco().Public(),
+		Hostname:       "server",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		AuthKeyID:      uint(pak.ID),
+		ForcedTags:     types.StringList{"tag:forced"},
+		Hostinfo:       &tailcfg.Hostinfo{OS: "linux"},
+	})
+	c.Assert(err, check.IsNil)
+
+	route := types.Route{
+		NodeID:     node.ID,
+		Prefix:     types.IPPrefix(netip.MustParsePrefix("192.168.1.0/24")),
+		Advertised: true,
+		Enabled:    true,
+		IsPrimary:  true,
+	}
+	c.Assert(db.db.Create(&route).Error, check.IsNil)
+
+	_, _, err = db.CreateAPIKey(nil, []string{types.APIKeyRoleReadOnly}, nil)
+	c.Assert(err, check.IsNil)
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:       netip.MustParseAddr("10.27.0.20"),
+		Hostname: "reserved",
+	})
+	c.Assert(err, check.IsNil)
+
+	_, err = db.SetPolicy(`{"acls": []}`)
+	c.Assert(err, check.IsNil)
+
+	export, err := db.Export()
+	c.Assert(err, check.IsNil)
+	c.Assert(export.Version, check.Equals, types.ExportVersion)
+	c.Assert(export.Users, check.HasLen, 1)
+	c.Assert(export.PreAuthKeys, check.HasLen, 1)
+	c.Assert(export.PreAuthKeys[0].ACLTags, check.DeepEquals, []string{"tag:server"})
+	c.Assert(export.Nodes, check.HasLen, 1)
+	c.Assert(export.Routes, check.HasLen, 1)
+	c.Assert(export.APIKeys, check.HasLen, 1)
+	c.Assert(export.IPReservations, check.HasLen, 1)
+	c.Assert(export.Policy, check.NotNil)
+
+	// The export goes through JSON, as it does between two instances.
+	data, err := json.Marshal(export)
+	c.Assert(err, check.IsNil)
+
+	var decoded types.Export
+	c.Assert(json.Unmarshal(data, &decoded), check.IsNil)
+
+	imported, err := NewHeadscaleDatabase(
+		Sqlite,
+		tmpDir+"/imported.db",
+		false,
+		notifier.NewNotifier(),
+		nil,
+		db.ipPrefixes,
+		nil,
+		types.IPAllocationConfig{},
+		"",
+	)
+	c.Assert(err, check.IsNil)
+	defer imported.Close()
+
+	err = imported.Import(&decoded)
+	c.Assert(err, check.IsNil)
+
+	reexport, err := imported.Export()
+	c.Assert(err, check.IsNil)
+	reexport.ExportedAt = export.ExportedAt
+
+	redata, err := json.Marshal(reexport)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(redata), check.Equals, string(data))
+
+	importedNode, err := imported.GetNodeByMachineKey(node.MachineKey)
+	c.Assert(err, check.IsNil)
+	c.Assert(importedNode.ID, check.Equals, node.ID)
+	c.Assert(importedNode.IPAddresses, check.DeepEquals, node.IPAddresses)
+	c.Assert(importedNode.Hostinfo.OS, check.Equals, "linux")
+
+	// Only an empty database can be imported into.
+	err = imported.Import(&decoded)
+	c.Assert(err, check.Equals, ErrImportNotEmpty)
+
+	// New objects continue after the imported IDs.
+	other, err := imported.CreateUser("other")
+	c.Assert(err, check.IsNil)
+	c.Assert(other.ID > user.ID, check.Equals, true)
+}
+
+func (s *Suite) TestImportNewerVersion(c *check.C) {
+	err := db.Import(&types.Export{Version: types.ExportVersion + 1})
+	c.Assert(errors.Is(err, ErrExportVersion), check.Equals, true)
+
+	err = db.Import(&types.Export{})
+	c.Assert(errors.Is(err, ErrExportVersion), check.Equals, true)
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"go4.org/netipx"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIPReservationNotFound = errors.New("ip reservation not found")
+	ErrIPReservationTarget   = errors.New(
+		"ip reservation needs either a hostname or a pre-auth key",
+	)
+)
+
+// CreateIPReservation reserves the address of reservation for the node
+// registering with its hostname or pre-auth key. The address must be
+// within ip_prefixes and not used by another node.
+func (hsdb *HSDatabase) CreateIPReservation(reservation *types.IPReservation) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	unlock, err := hsdb.lockIPAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if (reservation.Hostname == "") == (reservation.PreAuthKeyID == nil) {
+		return ErrIPReservationTarget
+	}
+
+	if err := hsdb.validateStaticIP(reservation.IP); err != nil {
+		return err
+	}
+
+	reservations, err := hsdb.listIPReservations()
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range reservations {
+		if existing.IP == reservation.IP {
+			return ErrIPReserved
+		}
+	}
+
+	// Reserving the address of a node already registered is allowed so
+	// it gets it back when it registers again.
+	nodes, err := hsdb.listNodes()
+	if err != nil {
+		return err
+	}
+
+	for index := range nodes {
+		if slices.Contains(nodes[index].IPAddresses, reservation.IP) &&
+			!reservation.Matches(&nodes[index]) {
+			return ErrIPInUse
+		}
+	}
+
+	if err := hsdb.db.Save(reservation).Error; err != nil {
+		return err
+	}
+
+	return hsdb.db.
+		Preload("PreAuthKey").
+		Preload("PreAuthKey.User").
+		Preload("PreAuthKey.ACLTags").
+		First(reservation, reservation.ID).Error
+}
+
+// ListIPReservations returns all the reserved addresses.
+func (hsdb *HSDatabase) ListIPReservations() (types.IPReservations, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	return hsdb.listIPReservations()
+}
+
+func (hsdb *HSDatabase) listIPReservations() (types.IPReservations, error) {
+	reservations := types.IPReservations{}
+	err := hsdb.db.
+		Preload("PreAuthKey").
+		Preload("PreAuthKey.User").
+		Preload("PreAuthKey.ACLTags").
+		Order("id").
+		Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// GetIPReservation returns the reservation with the given ID.
+func (hsdb *HSDatabase) GetIPReservation(id uint64) (*types.IPReservation, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	reservation := types.IPReservation{}
+	err := hsdb.db.
+		Preload("PreAuthKey").
+		Preload("PreAuthKey.User").
+		Preload("PreAuthKey.ACLTags").
+		First(&reservation, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrIPReservationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// DeleteIPReservation releases a reserved address, a node already using
+// it keeps it.
+func (hsdb *HSDatabase) DeleteIPReservation(reservation *types.IPReservation) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	return hsdb.db.Delete(reservation).Error
+}
+
+func (hsdb *HSDatabase) getReservedIPs() (*netipx.IPSet, error) {
+	reservations, err := hsdb.listIPReservations()
+	if err != nil {
+		return nil, err
+	}
+
+	var ips netipx.IPSetBuilder
+	for _, reservation := range reservations {
+		ips.Add(reservation.IP)
+	}
+
+	return ips.IPSet()
+}
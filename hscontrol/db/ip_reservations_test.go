@@ -0,0 +1,212 @@
+package db
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"gopkg.in/check.v1"
+	"tailscale.com/types/key"
+)
+
+func (s *Suite) TestIPReservations(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	pak, err := db.CreatePreAuthKey(user.Name, true, false, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	register := func(hostname string, authKeyID uint64) (*types.Node, error) {
+		return db.RegisterNode(types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			Hostname:       hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodAuthKey,
+			AuthKeyID:      uint(authKeyID),
+		})
+	}
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP: netip.MustParseAddr("10.27.0.1"),
+	})
+	c.Assert(err, check.Equals, ErrIPReservationTarget)
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:       netip.MustParseAddr("10.28.0.1"),
+		Hostname: "server",
+	})
+	c.Assert(err, check.Equals, ErrIPOutsidePrefixes)
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:       netip.MustParseAddr("10.27.0.1"),
+		Hostname: "server",
+	})
+	c.Assert(err, check.IsNil)
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:       netip.MustParseAddr("10.27.0.1"),
+		Hostname: "other",
+	})
+	c.Assert(err, check.Equals, ErrIPReserved)
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:           netip.MustParseAddr("10.27.0.10"),
+		PreAuthKeyID: &pak.ID,
+	})
+	c.Assert(err, check.IsNil)
+
+	reservations, err := db.ListIPReservations()
+	c.Assert(err, check.IsNil)
+	c.Assert(reservations, check.HasLen, 2)
+	c.Assert(reservations[1].PreAuthKey.User.Name, check.Equals, "test")
+
+	// Other nodes skip the reserved addresses.
+	laptop, err := register("laptop", 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(laptop.IPAddresses[0].String(), check.Equals, "10.27.0.2")
+
+	server, err := register("server", 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(server.IPAddresses[0].String(), check.Equals, "10.27.0.1")
+
+	keyed, err := register("keyed", pak.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(keyed.IPAddresses[0].String(), check.Equals, "10.27.0.10")
+
+	// A rebuilt server registering while the old node still exists gets
+	// a new address.
+	rebuilt, err := register("server", 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(rebuilt.IPAddresses[0].String(), check.Equals, "10.27.0.3")
+
+	// The address of a node can only be reserved for that node.
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:       netip.MustParseAddr("10.27.0.2"),
+		Hostname: "other",
+	})
+	c.Assert(err, check.Equals, ErrIPInUse)
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:       netip.MustParseAddr("10.27.0.2"),
+		Hostname: "laptop",
+	})
+	c.Assert(err, check.IsNil)
+
+	reservation, err := db.GetIPReservation(reservations[0].ID)
+	c.Assert(err, check.IsNil)
+
+	err = db.DeleteIPReservation(reservation)
+	c.Assert(err, check.IsNil)
+
+	_, err = db.GetIPReservation(reservations[0].ID)
+	c.Assert(err, check.Equals, ErrIPReservationNotFound)
+
+	// Reservations of a pre-auth key go away with the key.
+	err = db.DestroyPreAuthKey(*pak)
+	c.Assert(err, check.IsNil)
+
+	reservations, err = db.ListIPReservations()
+	c.Assert(err, check.IsNil)
+	c.Assert(reservations, check.HasLen, 1)
+	c.Assert(reservations[0].Hostname, check.Equals, "laptop")
+}
+
+func (s *Suite) TestSetNodeIP(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	register := func(hostname string) *types.Node {
+		node, err := db.RegisterNode(types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			Hostname:       hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodCLI,
+		})
+		c.Assert(err, check.IsNil)
+
+		return node
+	}
+
+	node := register("server")
+	other := register("other")
+
+	err = db.CreateIPReservation(&types.IPReservation{
+		IP:       netip.MustParseAddr("10.27.0.20"),
+		Hostname: "reserved",
+	})
+	c.Assert(err, check.IsNil)
+
+	for ip, expected := range map[string]error{
+		"10.28.0.1":   ErrIPOutsidePrefixes,
+		"10.27.0.0":   ErrIPNotAssignable,
+		"10.27.1.255": ErrIPNotAssignable,
+		"10.27.0.2":   ErrIPInUse,
+		"10.27.0.20":  ErrIPReserved,
+	} {
+		err = db.SetNodeIP(node, netip.MustParseAddr(ip))
+		c.Assert(err, check.Equals, expected, check.Commentf("ip %s", ip))
+	}
+
+	err = db.SetNodeIP(node, netip.MustParseAddr("10.27.1.42"))
+	c.Assert(err, check.IsNil)
+	c.Assert(node.IPAddresses, check.DeepEquals, types.NodeAddresses{netip.MustParseAddr("10.27.1.42")})
+
+	stored, err := db.GetNodeByID(node.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.IPAddresses, check.DeepEquals, types.NodeAddresses{netip.MustParseAddr("10.27.1.42")})
+
+	// The previous address can be given to another node.
+	err = db.SetNodeIP(other, netip.MustParseAddr("10.27.0.1"))
+	c.Assert(err, check.IsNil)
+}
+
+func (s *Suite) TestSetNodeIPPoolsAndQuarantine(c *check.C) {
+	db.ipPools = types.IPPools{
+		{User: "ci", Prefixes: []netip.Prefix{netip.MustParsePrefix("10.27.0.0/28")}},
+	}
+	db.ipAllocation.Quarantine = time.Hour
+	defer func() {
+		db.ipPools = nil
+		db.ipAllocation.Quarantine = 0
+	}()
+
+	ci, err := db.CreateUser("ci")
+	c.Assert(err, check.IsNil)
+
+	other, err := db.CreateUser("other")
+	c.Assert(err, check.IsNil)
+
+	register := func(hostname string, user *types.User) *types.Node {
+		node, err := db.RegisterNode(types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			Hostname:       hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodCLI,
+		})
+		c.Assert(err, check.IsNil)
+
+		return node
+	}
+
+	pooled := register("pooled", ci)
+	outside := register("outside", other)
+
+	// Nodes of a pool stay within it, the others stay out of every pool.
+	err = db.SetNodeIP(pooled, netip.MustParseAddr("10.27.1.42"))
+	c.Assert(err, check.Equals, ErrIPOutsidePool)
+
+	err = db.SetNodeIP(outside, netip.MustParseAddr("10.27.0.5"))
+	c.Assert(err, check.Equals, ErrIPOutsidePool)
+
+	released := pooled.IPAddresses[0]
+	err = db.SetNodeIP(pooled, netip.MustParseAddr("10.27.0.5"))
+	c.Assert(err, check.IsNil)
+
+	// The previous address is quarantined.
+	err = db.SetNodeIP(register("pooled-2", ci), released)
+	c.Assert(err, check.Equals, ErrIPQuarantined)
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AcquireLease takes the lease for holder, or renews it if holder already
+// holds it, for the given duration. It returns false if another holder
+// holds the lease and it has not expired. The row of the lease is locked
+// while it is checked, so only one of the processes racing for it takes
+// it.
+func (hsdb *HSDatabase) AcquireLease(
+	name, holder string,
+	duration time.Duration,
+) (bool, error) {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	acquired := false
+
+	err := hsdb.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&types.Lease{Name: name}).Error
+		if err != nil {
+			return err
+		}
+
+		var lease types.Lease
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("name = ?", name).
+			First(&lease).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if lease.Holder != "" && lease.Holder != holder && lease.ExpiresAt.After(now) {
+			return nil
+		}
+
+		acquired = true
+
+		return tx.Model(&lease).Updates(map[string]any{
+			"holder":     holder,
+			"expires_at": now.Add(duration),
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// ReleaseLease gives up the lease if holder holds it, so another process
+// can take it without waiting for it to expire.
+func (hsdb *HSDatabase) ReleaseLease(name, holder string) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	return hsdb.db.Model(&types.Lease{}).
+		Where("name = ? AND holder = ?", name, holder).
+		Updates(map[string]any{
+			"holder":     "",
+			"expires_at": time.Time{},
+		}).Error
+}
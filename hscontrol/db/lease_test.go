@@ -0,0 +1,49 @@
+package db
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *Suite) TestAcquireLease(c *check.C) {
+	acquired, err := db.AcquireLease("jobs", "a", time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, true)
+
+	// The holder renews its lease, others have to wait for it.
+	acquired, err = db.AcquireLease("jobs", "a", time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, true)
+
+	acquired, err = db.AcquireLease("jobs", "b", time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, false)
+
+	// Releasing a lease held by another holder does nothing.
+	c.Assert(db.ReleaseLease("jobs", "b"), check.IsNil)
+
+	acquired, err = db.AcquireLease("jobs", "b", time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, false)
+
+	c.Assert(db.ReleaseLease("jobs", "a"), check.IsNil)
+
+	acquired, err = db.AcquireLease("jobs", "b", time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, true)
+}
+
+func (s *Suite) TestAcquireExpiredLease(c *check.C) {
+	acquired, err := db.AcquireLease("jobs", "a", -time.Second)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, true)
+
+	acquired, err = db.AcquireLease("jobs", "b", time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, true)
+
+	acquired, err = db.AcquireLease("jobs", "a", time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(acquired, check.Equals, false)
+}
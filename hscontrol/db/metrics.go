@@ -0,0 +1,56 @@
+package db
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+const prometheusNamespace = "headscale"
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "The time taken by database queries, by operation and table",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	ephemeralNodesExpired = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Name:      "ephemeral_nodes_expired_total",
+		Help:      "The number of inactive ephemeral nodes removed, by outcome",
+	}, []string{"status"})
+
+	routeFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Name:      "route_failovers_total",
+		Help:      "The number of primary route failovers attempted, by outcome",
+	}, []string{"status"})
+)
+
+const queryStartedKey = "headscale:query_started"
+
+// registerQueryMetrics records the duration of every query made through
+// db in queryDuration.
+func registerQueryMetrics(db *gorm.DB) error {
+	return registerQueryCallbacks(
+		db,
+		"metrics",
+		func(_ string, tx *gorm.DB) {
+			tx.InstanceSet(queryStartedKey, time.Now())
+		},
+		func(operation string, tx *gorm.DB) {
+			started, ok := tx.InstanceGet(queryStartedKey)
+			if !ok {
+				return
+			}
+
+			queryDuration.
+				WithLabelValues(operation, tx.Statement.Table).
+				Observe(time.Since(started.(time.Time)).Seconds())
+		},
+	)
+}
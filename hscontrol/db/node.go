@@ -4,13 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/juanfont/headscale/hscontrol/events"
+	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
-	"github.com/patrickmn/go-cache"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 	"tailscale.com/tailcfg"
@@ -28,10 +30,10 @@ var (
 	ErrNodeNotFoundRegistrationCache = errors.New(
 		"node not found in registration cache",
 	)
-	ErrCouldNotConvertNodeInterface = errors.New("failed to convert node interface")
-	ErrDifferentRegisteredUser      = errors.New(
+	ErrDifferentRegisteredUser = errors.New(
 		"node was previously registered with a different user",
 	)
+	ErrUnknownNodeOrder = errors.New("unknown order")
 )
 
 // ListPeers returns all peers of node, regardless of any Policy or if the node is expired.
@@ -85,6 +87,138 @@ func (hsdb *HSDatabase) listNodes() ([]types.Node, error) {
 	return nodes, nil
 }
 
+// NodeFilter selects and orders the nodes listed by ListNodesPage.
+type NodeFilter struct {
+	// User is the name of the user owning the nodes, the nodes of every
+	// user are listed if it is empty.
+	User    string
+	Expired *bool
+	// Route is a prefix advertised by the nodes.
+	Route *netip.Prefix
+
+	// OrderBy is a field of nodeOrders optionally followed by " desc".
+	// The nodes are ordered by ID if it is empty, and the nodes equal on
+	// the field are kept in the order of their ID.
+	OrderBy string
+}
+
+// nodeOrders are the columns the nodes can be ordered by, the times
+// that are not set come first.
+var nodeOrders = map[string][]string{
+	"id":         {"id"},
+	"name":       {"given_name"},
+	"hostname":   {"hostname"},
+	"user":       {"(SELECT name FROM users WHERE users.id = nodes.user_id)"},
+	"last_seen":  {"CASE WHEN last_seen IS NULL THEN 0 ELSE 1 END", "last_seen"},
+	"created_at": {"created_at"},
+	"expiry":     {"CASE WHEN expiry IS NULL THEN 0 ELSE 1 END", "expiry"},
+}
+
+// order returns the columns the nodes are ordered by.
+func (filter NodeFilter) order() ([]string, error) {
+	field, direction, _ := strings.Cut(strings.TrimSpace(filter.OrderBy), " ")
+	if field == "" {
+		field = "id"
+	}
+
+	columns, ok := nodeOrders[field]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownNodeOrder, field)
+	}
+
+	switch strings.TrimSpace(direction) {
+	case "", "asc":
+		return columns, nil
+	case "desc":
+		descending := make([]string, len(columns))
+		for index, column := range columns {
+			descending[index] = column + " DESC"
+		}
+
+		return descending, nil
+	}
+
+	return nil, fmt.Errorf("%w %q", ErrUnknownNodeOrder, filter.OrderBy)
+}
+
+// where selects the nodes matching the filter, userID is the ID of
+// filter.User.
+func (filter NodeFilter) where(userID uint) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if filter.User != "" {
+			tx = tx.Where("user_id = ?", userID)
+		}
+
+		if filter.Expired != nil {
+			expired := "expiry IS NOT NULL AND expiry > ? AND expiry < ?"
+			if !*filter.Expired {
+				expired = "NOT (" + expired + ")"
+			}
+
+			tx = tx.Where(expired, time.Time{}, time.Now().UTC())
+		}
+
+		if filter.Route != nil {
+			tx = tx.Where(
+				"EXISTS (SELECT 1 FROM routes WHERE routes.node_id = nodes.id"+
+					" AND routes.deleted_at IS NULL AND routes.advertised = ? AND routes.prefix = ?)",
+				true,
+				types.IPPrefix(*filter.Route),
+			)
+		}
+
+		return tx
+	}
+}
+
+// ListNodesPage returns the page of the nodes matching filter, in its
+// order, and the number of nodes matching it.
+func (hsdb *HSDatabase) ListNodesPage(filter NodeFilter, page Page) (types.Nodes, int64, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	order, err := filter.order()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var userID uint
+	if filter.User != "" {
+		user, err := hsdb.getUser(filter.User)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		userID = user.ID
+	}
+
+	var total int64
+	err = hsdb.db.
+		Model(&types.Node{}).
+		Scopes(filter.where(userID)).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tx := hsdb.db.
+		Preload("AuthKey").
+		Preload("AuthKey.User").
+		Preload("User").
+		Preload("Routes").
+		Scopes(filter.where(userID))
+	for _, column := range order {
+		tx = tx.Order(column)
+	}
+
+	nodes := types.Nodes{}
+	if err := tx.Scopes(page.scope).Find(&nodes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return nodes, total, nil
+}
+
 func (hsdb *HSDatabase) ListNodesByGivenName(givenName string) (types.Nodes, error) {
 	hsdb.mu.RLock()
 	defer hsdb.mu.RUnlock()
@@ -276,7 +410,7 @@ func (hsdb *HSDatabase) SetTags(
 		Message:     "called from db.SetTags",
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyWithIgnore(stateUpdate, node.MachineKey.String())
+		hsdb.notifier.NotifyWithIgnore(hsdb.ctx, stateUpdate, node.MachineKey.String())
 	}
 
 	return nil
@@ -316,7 +450,165 @@ func (hsdb *HSDatabase) RenameNode(node *types.Node, newName string) error {
 		Message:     "called from db.RenameNode",
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyWithIgnore(stateUpdate, node.MachineKey.String())
+		hsdb.notifier.NotifyWithIgnore(hsdb.ctx, stateUpdate, node.MachineKey.String())
+	}
+
+	return nil
+}
+
+// SetNodeIP pins ip to node, replacing its address of the same IP
+// family. The address must be within the IP pool of node, not used by
+// another node or reserved for one, and not quarantined.
+func (hsdb *HSDatabase) SetNodeIP(node *types.Node, ip netip.Addr) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	unlock, err := hsdb.lockIPAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := hsdb.validateStaticIP(ip); err != nil {
+		return err
+	}
+
+	if slices.Contains(node.IPAddresses, ip) {
+		return nil
+	}
+
+	if err := hsdb.validateNodeIP(node, ip); err != nil {
+		return err
+	}
+
+	usedIps, err := hsdb.getUsedIPs()
+	if err != nil {
+		return err
+	}
+
+	if usedIps.Contains(ip) {
+		return ErrIPInUse
+	}
+
+	reservations, err := hsdb.listIPReservations()
+	if err != nil {
+		return err
+	}
+
+	for index := range reservations {
+		if reservations[index].IP == ip && !reservations[index].Matches(node) {
+			return ErrIPReserved
+		}
+	}
+
+	ips := types.NodeAddresses{ip}
+	var released []netip.Addr
+	for _, addr := range node.IPAddresses {
+		if addr.Is4() != ip.Is4() {
+			ips = append(ips, addr)
+		} else {
+			released = append(released, addr)
+		}
+	}
+	ips.Sort()
+
+	if err := hsdb.db.Model(node).Update("ip_addresses", ips).Error; err != nil {
+		return fmt.Errorf("failed to set the address of node in the database: %w", err)
+	}
+	node.IPAddresses = ips
+
+	if err := hsdb.releaseIPs(released); err != nil {
+		return err
+	}
+
+	stateUpdate := types.StateUpdate{
+		Type:        types.StatePeerChanged,
+		ChangeNodes: types.Nodes{node},
+		Message:     "called from db.SetNodeIP",
+	}
+	if stateUpdate.Valid() {
+		hsdb.notifier.NotifyWithIgnore(hsdb.ctx, stateUpdate, node.MachineKey.String())
+	}
+
+	// The node needs to learn its own new address.
+	hsdb.notifier.NotifyByMachineKey(hsdb.ctx, types.StateUpdate{
+		Type: types.StateFullUpdate,
+	}, node.MachineKey)
+
+	return nil
+}
+
+// ApproveNode authorizes a node waiting for approval to join the network.
+func (hsdb *HSDatabase) ApproveNode(node *types.Node) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	if err := hsdb.nodeSetPendingApproval(node, false); err != nil {
+		return err
+	}
+
+	stateUpdate := types.StateUpdate{
+		Type:        types.StatePeerChanged,
+		ChangeNodes: types.Nodes{node},
+		Message:     "called from db.ApproveNode",
+	}
+	if stateUpdate.Valid() {
+		hsdb.notifier.NotifyWithIgnore(hsdb.ctx, stateUpdate, node.MachineKey.String())
+	}
+
+	// The node did not get any peers while it was waiting.
+	hsdb.notifier.NotifyByMachineKey(hsdb.ctx, types.StateUpdate{
+		Type: types.StateFullUpdate,
+	}, node.MachineKey)
+
+	return nil
+}
+
+// RejectNode removes a node from the network. A node waiting for
+// approval is deleted, an approved node has its approval revoked: it keeps
+// its registration but is removed from the network until it is approved
+// again.
+func (hsdb *HSDatabase) RejectNode(node *types.Node) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	if node.PendingApproval {
+		return hsdb.deleteNode(node)
+	}
+
+	if err := hsdb.nodeSetPendingApproval(node, true); err != nil {
+		return err
+	}
+
+	stateUpdate := types.StateUpdate{
+		Type:    types.StatePeerRemoved,
+		Removed: []tailcfg.NodeID{tailcfg.NodeID(node.ID)},
+	}
+	if stateUpdate.Valid() {
+		hsdb.notifier.NotifyWithIgnore(hsdb.ctx, stateUpdate, node.MachineKey.String())
+	}
+
+	// The node loses all of its peers.
+	hsdb.notifier.NotifyByMachineKey(hsdb.ctx, types.StateUpdate{
+		Type: types.StateFullUpdate,
+	}, node.MachineKey)
+
+	return nil
+}
+
+func (hsdb *HSDatabase) nodeSetPendingApproval(node *types.Node, pending bool) error {
+	if err := hsdb.db.Model(node).Update("pending_approval", pending).Error; err != nil {
+		return fmt.Errorf("failed to update node approval in the database: %w", err)
+	}
+
+	node.PendingApproval = pending
+
+	stateSelfUpdate := types.StateUpdate{
+		Type:        types.StateSelfUpdate,
+		ChangeNodes: types.Nodes{node},
+	}
+	if stateSelfUpdate.Valid() {
+		hsdb.notifier.NotifyByMachineKey(hsdb.ctx, stateSelfUpdate, node.MachineKey)
 	}
 
 	return nil
@@ -347,7 +639,7 @@ func (hsdb *HSDatabase) nodeSetExpiry(node *types.Node, expiry time.Time) error
 		ChangeNodes: types.Nodes{node},
 	}
 	if stateSelfUpdate.Valid() {
-		hsdb.notifier.NotifyByMachineKey(stateSelfUpdate, node.MachineKey)
+		hsdb.notifier.NotifyByMachineKey(hsdb.ctx, stateSelfUpdate, node.MachineKey)
 	}
 
 	stateUpdate := types.StateUpdate{
@@ -360,12 +652,32 @@ func (hsdb *HSDatabase) nodeSetExpiry(node *types.Node, expiry time.Time) error
 		},
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyWithIgnore(stateUpdate, node.MachineKey.String())
+		hsdb.notifier.NotifyWithIgnore(hsdb.ctx, stateUpdate, node.MachineKey.String())
 	}
 
 	return nil
 }
 
+// NodeSetExpiryDisabled sets whether the key of node never expires,
+// whatever the node expiry policy. Disabling the expiry removes the
+// current expiry of the node.
+func (hsdb *HSDatabase) NodeSetExpiryDisabled(node *types.Node, disabled bool) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	if err := hsdb.db.Model(node).Update("expiry_disabled", disabled).Error; err != nil {
+		return fmt.Errorf("failed to update node expiry policy in the database: %w", err)
+	}
+
+	node.ExpiryDisabled = disabled
+
+	if !disabled {
+		return nil
+	}
+
+	return hsdb.nodeSetExpiry(node, time.Time{})
+}
+
 // DeleteNode deletes a Node from the database.
 func (hsdb *HSDatabase) DeleteNode(node *types.Node) error {
 	hsdb.mu.Lock()
@@ -385,12 +697,16 @@ func (hsdb *HSDatabase) deleteNode(node *types.Node) error {
 		return err
 	}
 
+	if err := hsdb.releaseIPs(node.IPAddresses); err != nil {
+		return err
+	}
+
 	stateUpdate := types.StateUpdate{
 		Type:    types.StatePeerRemoved,
 		Removed: []tailcfg.NodeID{tailcfg.NodeID(node.ID)},
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyAll(stateUpdate)
+		hsdb.notifier.NotifyAll(hsdb.ctx, stateUpdate)
 	}
 
 	return nil
@@ -409,11 +725,11 @@ func (hsdb *HSDatabase) UpdateLastSeen(node *types.Node) error {
 }
 
 func (hsdb *HSDatabase) RegisterNodeFromAuthCallback(
-	cache *cache.Cache,
 	mkey key.MachinePublic,
 	userName string,
 	nodeExpiry *time.Time,
 	registrationMethod string,
+	pendingApproval bool,
 ) (*types.Node, error) {
 	hsdb.mu.Lock()
 	defer hsdb.mu.Unlock()
@@ -425,44 +741,57 @@ func (hsdb *HSDatabase) RegisterNodeFromAuthCallback(
 		Str("expiresAt", fmt.Sprintf("%v", nodeExpiry)).
 		Msg("Registering node from API/CLI or auth callback")
 
-	if nodeInterface, ok := cache.Get(mkey.String()); ok {
-		if registrationNode, ok := nodeInterface.(types.Node); ok {
-			user, err := hsdb.getUser(userName)
-			if err != nil {
-				return nil, fmt.Errorf(
-					"failed to find user in register node from auth callback, %w",
-					err,
-				)
-			}
+	reg, err := hsdb.getPendingRegistration(mkey)
+	if errors.Is(err, ErrPendingRegistrationNotFound) {
+		return nil, ErrNodeNotFoundRegistrationCache
+	} else if err != nil {
+		return nil, err
+	}
 
-			// Registration of expired node with different user
-			if registrationNode.ID != 0 &&
-				registrationNode.UserID != user.ID {
-				return nil, ErrDifferentRegisteredUser
-			}
+	if reg.Rejected {
+		return nil, ErrPendingRegistrationRejected
+	}
 
-			registrationNode.UserID = user.ID
-			registrationNode.RegisterMethod = registrationMethod
+	registrationNode := reg.Node
 
-			if nodeExpiry != nil {
-				registrationNode.Expiry = nodeExpiry
-			}
+	user, err := hsdb.getUser(userName)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to find user in register node from auth callback, %w",
+			err,
+		)
+	}
 
-			node, err := hsdb.registerNode(
-				registrationNode,
-			)
+	// Registration of expired node with different user
+	if registrationNode.ID != 0 &&
+		registrationNode.UserID != user.ID {
+		return nil, ErrDifferentRegisteredUser
+	}
 
-			if err == nil {
-				cache.Delete(mkey.String())
-			}
+	registrationNode.UserID = user.ID
+	registrationNode.RegisterMethod = registrationMethod
 
-			return node, err
-		} else {
-			return nil, ErrCouldNotConvertNodeInterface
-		}
+	if nodeExpiry != nil {
+		registrationNode.Expiry = nodeExpiry
+	}
+
+	// Nodes logging in again keep their approval.
+	if registrationNode.ID == 0 {
+		registrationNode.PendingApproval = pendingApproval
+	}
+
+	node, err := hsdb.registerNode(
+		registrationNode,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hsdb.db.Delete(reg).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete pending registration: %w", err)
 	}
 
-	return nil, ErrNodeNotFoundRegistrationCache
+	return node, nil
 }
 
 // RegisterNode is executed from the CLI to register a new Node using its MachineKey.
@@ -483,7 +812,7 @@ func (hsdb *HSDatabase) registerNode(node types.Node) (*types.Node, error) {
 
 	// If the node exists and we had already IPs for it, we just save it
 	// so we store the node.Expire and node.Nodekey that has been set when
-	// adding it to the registrationCache
+	// adding it to the pending registrations
 	if len(node.IPAddresses) > 0 {
 		if err := hsdb.db.Save(&node).Error; err != nil {
 			return nil, fmt.Errorf("failed register existing node in the database: %w", err)
@@ -497,13 +826,21 @@ func (hsdb *HSDatabase) registerNode(node types.Node) (*types.Node, error) {
 			Str("user", node.User.Name).
 			Msg("Node authorized again")
 
+		hsdb.events.Publish(events.Event{
+			Type: events.NodeRegistered,
+			Node: &node,
+		})
+
 		return &node, nil
 	}
 
-	hsdb.ipAllocationMutex.Lock()
-	defer hsdb.ipAllocationMutex.Unlock()
+	unlock, err := hsdb.lockIPAllocation()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
-	ips, err := hsdb.getAvailableIPs()
+	ips, err := hsdb.getAvailableIPsForNode(&node)
 	if err != nil {
 		log.Error().
 			Caller().
@@ -526,6 +863,11 @@ func (hsdb *HSDatabase) registerNode(node types.Node) (*types.Node, error) {
 		Str("ip", strings.Join(ips.StringSlice(), ",")).
 		Msg("Node registered with the database")
 
+	hsdb.events.Publish(events.Event{
+		Type: events.NodeRegistered,
+		Node: &node,
+	})
+
 	return &node, nil
 }
 
@@ -735,8 +1077,7 @@ func (hsdb *HSDatabase) enableRoutes(node *types.Node, routeStrs ...string) erro
 		Message:     "called from db.enableRoutes",
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyWithIgnore(
-			stateUpdate, node.MachineKey.String())
+		hsdb.notifier.NotifyWithIgnore(hsdb.ctx, stateUpdate, node.MachineKey.String())
 	}
 
 	return nil
@@ -844,12 +1185,16 @@ func (hsdb *HSDatabase) ExpireEphemeralNodes(inactivityThreshhold time.Duration)
 						Err(err).
 						Str("node", node.Hostname).
 						Msg("🤮 Cannot delete ephemeral node from the database")
+
+					ephemeralNodesExpired.WithLabelValues("failed").Inc()
+				} else {
+					ephemeralNodesExpired.WithLabelValues("success").Inc()
 				}
 			}
 		}
 
 		if len(expired) > 0 {
-			hsdb.notifier.NotifyAll(types.StateUpdate{
+			hsdb.notifier.NotifyAll(hsdb.ctx, types.StateUpdate{
 				Type:    types.StatePeerRemoved,
 				Removed: expired,
 			})
@@ -857,7 +1202,15 @@ func (hsdb *HSDatabase) ExpireEphemeralNodes(inactivityThreshhold time.Duration)
 	}
 }
 
-func (hsdb *HSDatabase) ExpireExpiredNodes(lastCheck time.Time) time.Time {
+// ExpireExpiredNodes expires the nodes whose expiry passed since
+// lastCheck, and sets the expiry of the nodes without one from
+// expiryPolicy, counted from now, matching the tags aclPolicy gives the
+// nodes. It returns the time of this check.
+func (hsdb *HSDatabase) ExpireExpiredNodes(
+	lastCheck time.Time,
+	expiryPolicy types.NodeExpiryConfig,
+	aclPolicy *policy.ACLPolicy,
+) time.Time {
 	hsdb.mu.Lock()
 	defer hsdb.mu.Unlock()
 
@@ -867,6 +1220,7 @@ func (hsdb *HSDatabase) ExpireExpiredNodes(lastCheck time.Time) time.Time {
 	started := time.Now()
 
 	expiredNodes := make([]*types.Node, 0)
+	limitedNodes := make([]*types.Node, 0)
 
 	nodes, err := hsdb.listNodes()
 	if err != nil {
@@ -887,7 +1241,7 @@ func (hsdb *HSDatabase) ExpireExpiredNodes(lastCheck time.Time) time.Time {
 			// Do not use setNodeExpiry as that has a notifier hook, which
 			// can cause a deadlock, we are updating all changed nodes later
 			// and there is no point in notifiying twice.
-			if err := hsdb.db.Model(nodes[index]).Updates(types.Node{
+			if err := hsdb.db.Model(&nodes[index]).Updates(types.Node{
 				Expiry: &started,
 			}).Error; err != nil {
 				log.Error().
@@ -900,16 +1254,52 @@ func (hsdb *HSDatabase) ExpireExpiredNodes(lastCheck time.Time) time.Time {
 					Str("node", node.Hostname).
 					Str("name", node.GivenName).
 					Msg("Node successfully expired")
+
+				hsdb.events.Publish(events.Event{
+					Type: events.NodeExpired,
+					Node: &nodes[index],
+				})
+			}
+		} else if node.Expiry == nil || node.Expiry.IsZero() {
+			keyExpiry := expiryPolicy.ForNode(&nodes[index], aclPolicy.EffectiveTags(&nodes[index]))
+			if keyExpiry == 0 {
+				continue
 			}
+
+			expiry := started.Add(keyExpiry)
+			if err := hsdb.db.Model(&nodes[index]).Updates(types.Node{
+				Expiry: &expiry,
+			}).Error; err != nil {
+				log.Error().
+					Err(err).
+					Str("node", node.Hostname).
+					Msg("Cannot set the expiry of node from the node expiry policy")
+
+				continue
+			}
+			nodes[index].Expiry = &expiry
+
+			log.Info().
+				Str("node", node.Hostname).
+				Time("expiry", expiry).
+				Msg("Node expiry set from the node expiry policy")
+
+			limitedNodes = append(limitedNodes, &nodes[index])
 		}
 	}
 
-	expired := make([]*tailcfg.PeerChange, len(expiredNodes))
-	for idx, node := range expiredNodes {
-		expired[idx] = &tailcfg.PeerChange{
+	expired := make([]*tailcfg.PeerChange, 0, len(expiredNodes)+len(limitedNodes))
+	for _, node := range expiredNodes {
+		expired = append(expired, &tailcfg.PeerChange{
 			NodeID:    tailcfg.NodeID(node.ID),
 			KeyExpiry: &started,
-		}
+		})
+	}
+	for _, node := range limitedNodes {
+		expired = append(expired, &tailcfg.PeerChange{
+			NodeID:    tailcfg.NodeID(node.ID),
+			KeyExpiry: node.Expiry,
+		})
 	}
 
 	// Inform the peers of a node with a lightweight update.
@@ -918,19 +1308,86 @@ func (hsdb *HSDatabase) ExpireExpiredNodes(lastCheck time.Time) time.Time {
 		ChangePatches: expired,
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyAll(stateUpdate)
+		hsdb.notifier.NotifyAll(hsdb.ctx, stateUpdate)
 	}
 
-	// Inform the node itself that it has expired.
-	for _, node := range expiredNodes {
+	// Inform the node itself that it has expired, or of its new expiry.
+	for _, node := range append(expiredNodes, limitedNodes...) {
 		stateSelfUpdate := types.StateUpdate{
 			Type:        types.StateSelfUpdate,
 			ChangeNodes: types.Nodes{node},
 		}
 		if stateSelfUpdate.Valid() {
-			hsdb.notifier.NotifyByMachineKey(stateSelfUpdate, node.MachineKey)
+			hsdb.notifier.NotifyByMachineKey(hsdb.ctx, stateSelfUpdate, node.MachineKey)
 		}
 	}
 
 	return started
 }
+
+// ListExpiringNodes returns the nodes whose key expires within before,
+// and whose owner has not been notified of this expiry yet.
+func (hsdb *HSDatabase) ListExpiringNodes(before time.Duration) (types.Nodes, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	nodes, err := hsdb.listNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	expiring := types.Nodes{}
+	for index := range nodes {
+		if isExpiring(&nodes[index], now, before) {
+			expiring = append(expiring, &nodes[index])
+		}
+	}
+
+	return expiring, nil
+}
+
+// SetNodeExpiryNotified records that the owner of node was notified of
+// its expiry, once the notification was delivered. The node.expiring
+// event is published and the node is sent the warning that it expires.
+func (hsdb *HSDatabase) SetNodeExpiryNotified(node *types.Node) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	if err := hsdb.db.Model(node).Updates(types.Node{
+		ExpiryNotified: node.Expiry,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to save the expiry notification of node: %w", err)
+	}
+	node.ExpiryNotified = node.Expiry
+
+	hsdb.events.Publish(events.Event{
+		Type: events.NodeExpiring,
+		Node: node,
+	})
+
+	stateSelfUpdate := types.StateUpdate{
+		Type:        types.StateSelfUpdate,
+		ChangeNodes: types.Nodes{node},
+	}
+	if stateSelfUpdate.Valid() {
+		hsdb.notifier.NotifyByMachineKey(hsdb.ctx, stateSelfUpdate, node.MachineKey)
+	}
+
+	return nil
+}
+
+// isExpiring reports if the key of node expires within before of now,
+// and its owner has not been notified of this expiry yet.
+func isExpiring(node *types.Node, now time.Time, before time.Duration) bool {
+	if before == 0 || node.Expiry == nil || node.Expiry.IsZero() || !node.Expiry.After(now) {
+		return false
+	}
+
+	if node.ExpiryNotified != nil && node.ExpiryNotified.Equal(*node.Expiry) {
+		return false
+	}
+
+	return node.Expiry.Sub(now) <= before
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"errors"
 	"fmt"
 	"net/netip"
 	"regexp"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/juanfont/headscale/hscontrol/events"
 	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
@@ -326,6 +328,200 @@ func (s *Suite) TestExpireNode(c *check.C) {
 	c.Assert(nodeFromDB.IsExpired(), check.Equals, true)
 }
 
+func (s *Suite) TestApproveAndRejectNode(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	node := &types.Node{
+		ID:              0,
+		MachineKey:      key.NewMachine().Public(),
+		NodeKey:         key.NewNode().Public(),
+		Hostname:        "testnode",
+		UserID:          user.ID,
+		RegisterMethod:  util.RegisterMethodAuthKey,
+		PendingApproval: true,
+	}
+	db.db.Save(node)
+
+	nodeFromDB, err := db.GetNode("test", "testnode")
+	c.Assert(err, check.IsNil)
+	c.Assert(nodeFromDB.PendingApproval, check.Equals, true)
+
+	err = db.ApproveNode(nodeFromDB)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodeFromDB.PendingApproval, check.Equals, false)
+
+	nodeFromDB, err = db.GetNode("test", "testnode")
+	c.Assert(err, check.IsNil)
+	c.Assert(nodeFromDB.PendingApproval, check.Equals, false)
+
+	err = db.RejectNode(nodeFromDB)
+	c.Assert(err, check.IsNil)
+
+	nodeFromDB, err = db.GetNode("test", "testnode")
+	c.Assert(err, check.IsNil)
+	c.Assert(nodeFromDB.PendingApproval, check.Equals, true)
+
+	// Rejecting a node waiting for approval deletes it.
+	err = db.RejectNode(nodeFromDB)
+	c.Assert(err, check.IsNil)
+
+	_, err = db.GetNode("test", "testnode")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *Suite) TestExpireExpiredNodesEvents(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	lastCheck := time.Now().Add(-time.Minute)
+	expiry := time.Now().Add(-time.Second)
+
+	node := &types.Node{
+		ID:             0,
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "testnode",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		Expiry:         &expiry,
+	}
+	db.db.Save(node)
+
+	eventChan, cancel := broker.Subscribe(1)
+	defer cancel()
+
+	db.ExpireExpiredNodes(lastCheck, types.NodeExpiryConfig{}, nil)
+
+	c.Assert(len(eventChan), check.Equals, 1)
+
+	event := <-eventChan
+	c.Assert(event.Type, check.Equals, events.NodeExpired)
+	c.Assert(event.Node.Hostname, check.Equals, "testnode")
+}
+
+func (s *Suite) TestListExpiringNodes(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	expiry := time.Now().Add(3 * 24 * time.Hour)
+
+	node := &types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "testnode",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		Expiry:         &expiry,
+	}
+	c.Assert(db.db.Save(node).Error, check.IsNil)
+
+	eventChan, cancel := broker.Subscribe(2)
+	defer cancel()
+
+	before := 7 * 24 * time.Hour
+
+	nodes, err := db.ListExpiringNodes(before)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.HasLen, 1)
+	c.Assert(nodes[0].User.Name, check.Equals, "test")
+
+	// The node is listed until the notification is recorded.
+	nodes, err = db.ListExpiringNodes(before)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.HasLen, 1)
+	c.Assert(len(eventChan), check.Equals, 0)
+
+	c.Assert(db.SetNodeExpiryNotified(nodes[0]), check.IsNil)
+
+	c.Assert(len(eventChan), check.Equals, 1)
+
+	event := <-eventChan
+	c.Assert(event.Type, check.Equals, events.NodeExpiring)
+	c.Assert(event.Node.Hostname, check.Equals, "testnode")
+
+	// The owner is only notified once of an expiry.
+	nodes, err = db.ListExpiringNodes(before)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.HasLen, 0)
+
+	// And again when the expiry changes.
+	expiry = expiry.Add(time.Hour)
+	c.Assert(db.NodeSetExpiry(node, expiry), check.IsNil)
+
+	nodes, err = db.ListExpiringNodes(before)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.HasLen, 1)
+}
+
+func (s *Suite) TestExpireExpiredNodesPolicy(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	save := func(hostname string, tags []string) *types.Node {
+		node := &types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			Hostname:       hostname,
+			GivenName:      hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodAuthKey,
+			ForcedTags:     tags,
+		}
+		c.Assert(db.db.Save(node).Error, check.IsNil)
+
+		return node
+	}
+
+	laptop := save("laptop", nil)
+	server := save("server", []string{"tag:server"})
+	exempt := save("exempt", nil)
+	c.Assert(db.NodeSetExpiryDisabled(exempt, true), check.IsNil)
+
+	// Tags requested by the node count once the ACL policy allows them.
+	requested := &types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "requested",
+		GivenName:      "requested",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		Hostinfo:       &tailcfg.Hostinfo{RequestTags: []string{"tag:server"}},
+	}
+	c.Assert(db.db.Save(requested).Error, check.IsNil)
+
+	expiryPolicy := types.NodeExpiryConfig{
+		Default: 90 * 24 * time.Hour,
+		Overrides: []types.NodeExpiryOverride{
+			{Tag: "tag:server", Expiry: 0},
+		},
+	}
+	aclPolicy := &policy.ACLPolicy{
+		TagOwners: map[string][]string{"tag:server": {"test"}},
+	}
+
+	db.ExpireExpiredNodes(time.Now().Add(-time.Minute), expiryPolicy, aclPolicy)
+
+	laptop, err = db.GetNodeByID(laptop.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(laptop.Expiry, check.NotNil)
+	c.Assert(laptop.Expiry.After(time.Now().Add(89*24*time.Hour)), check.Equals, true)
+	c.Assert(laptop.IsExpired(), check.Equals, false)
+
+	server, err = db.GetNodeByID(server.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(server.Expiry == nil || server.Expiry.IsZero(), check.Equals, true)
+
+	exempt, err = db.GetNodeByID(exempt.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(exempt.ExpiryDisabled, check.Equals, true)
+	c.Assert(exempt.Expiry == nil || exempt.Expiry.IsZero(), check.Equals, true)
+
+	requested, err = db.GetNodeByID(requested.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(requested.Expiry == nil || requested.Expiry.IsZero(), check.Equals, true)
+}
+
 func (s *Suite) TestSerdeAddressStrignSlice(c *check.C) {
 	input := types.NodeAddresses([]netip.Addr{
 		netip.MustParseAddr("192.0.2.1"),
@@ -617,3 +813,63 @@ func (s *Suite) TestAutoApproveRoutes(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(enabledRoutes, check.HasLen, 4)
 }
+
+func (s *Suite) TestListNodesPage(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	other, err := db.CreateUser("other")
+	c.Assert(err, check.IsNil)
+
+	expired := time.Now().Add(-time.Hour)
+	for index, hostname := range []string{"c", "a", "b"} {
+		node := types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			Hostname:       hostname,
+			GivenName:      hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodAuthKey,
+		}
+		if index == 0 {
+			node.Expiry = &expired
+		}
+		db.db.Save(&node)
+	}
+
+	db.db.Save(&types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		Hostname:       "d",
+		GivenName:      "d",
+		UserID:         other.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+	})
+
+	hostnames := func(nodes types.Nodes) []string {
+		names := []string{}
+		for _, node := range nodes {
+			names = append(names, node.Hostname)
+		}
+
+		return names
+	}
+
+	nodes, total, err := db.ListNodesPage(NodeFilter{User: "test", OrderBy: "hostname"}, Page{Offset: 1, Limit: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, int64(3))
+	c.Assert(hostnames(nodes), check.DeepEquals, []string{"b"})
+	c.Assert(nodes[0].User.Name, check.Equals, "test")
+
+	notExpired := false
+	nodes, total, err = db.ListNodesPage(NodeFilter{Expired: &notExpired, OrderBy: "user desc"}, Page{})
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, int64(3))
+	c.Assert(hostnames(nodes), check.DeepEquals, []string{"a", "b", "d"})
+
+	_, _, err = db.ListNodesPage(NodeFilter{OrderBy: "color"}, Page{})
+	c.Assert(errors.Is(err, ErrUnknownNodeOrder), check.Equals, true)
+
+	_, _, err = db.ListNodesPage(NodeFilter{User: "nobody"}, Page{})
+	c.Assert(err, check.NotNil)
+}
@@ -0,0 +1,21 @@
+package db
+
+import "gorm.io/gorm"
+
+// Page is a range of rows of a list ordered by ID.
+type Page struct {
+	Offset int
+	// Limit is the number of rows listed, all the rows from Offset are
+	// listed if it is 0.
+	Limit int
+}
+
+// scope orders the rows by ID and selects the rows of page.
+func (page Page) scope(tx *gorm.DB) *gorm.DB {
+	tx = tx.Order("id").Offset(page.Offset)
+	if page.Limit > 0 {
+		tx = tx.Limit(page.Limit)
+	}
+
+	return tx
+}
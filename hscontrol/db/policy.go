@@ -0,0 +1,58 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"gorm.io/gorm"
+)
+
+// SetPolicy stores a new version of the policy in the database.
+// Previous versions are kept as the history of the policy.
+func (hsdb *HSDatabase) SetPolicy(policy string) (*types.Policy, error) {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	pol := types.Policy{
+		Data: policy,
+	}
+
+	if err := hsdb.db.Create(&pol).Error; err != nil {
+		return nil, err
+	}
+
+	return &pol, nil
+}
+
+// GetPolicy returns the latest version of the policy.
+func (hsdb *HSDatabase) GetPolicy() (*types.Policy, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	var pol types.Policy
+	if err := hsdb.db.
+		Order("id DESC").
+		Limit(1).
+		First(&pol).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, types.ErrPolicyNotFound
+		}
+
+		return nil, err
+	}
+
+	return &pol, nil
+}
+
+// ListPolicies returns every stored version of the policy, newest first.
+func (hsdb *HSDatabase) ListPolicies() ([]types.Policy, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	policies := []types.Policy{}
+	if err := hsdb.db.Order("id DESC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
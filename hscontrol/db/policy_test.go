@@ -0,0 +1,33 @@
+package db
+
+import (
+	"github.com/juanfont/headscale/hscontrol/types"
+	"gopkg.in/check.v1"
+)
+
+func (*Suite) TestGetPolicyNotFound(c *check.C) {
+	pol, err := db.GetPolicy()
+	c.Assert(err, check.Equals, types.ErrPolicyNotFound)
+	c.Assert(pol, check.IsNil)
+}
+
+func (*Suite) TestSetPolicyKeepsHistory(c *check.C) {
+	first, err := db.SetPolicy(`{"acls": []}`)
+	c.Assert(err, check.IsNil)
+	c.Assert(first.ID, check.Not(check.Equals), uint(0))
+
+	second, err := db.SetPolicy(`{"groups": {}}`)
+	c.Assert(err, check.IsNil)
+	c.Assert(second.ID > first.ID, check.Equals, true)
+
+	pol, err := db.GetPolicy()
+	c.Assert(err, check.IsNil)
+	c.Assert(pol.ID, check.Equals, second.ID)
+	c.Assert(pol.Data, check.Equals, `{"groups": {}}`)
+
+	policies, err := db.ListPolicies()
+	c.Assert(err, check.IsNil)
+	c.Assert(policies, check.HasLen, 2)
+	c.Assert(policies[0].ID, check.Equals, second.ID)
+	c.Assert(policies[1].Data, check.Equals, `{"acls": []}`)
+}
@@ -102,6 +102,43 @@ func (hsdb *HSDatabase) ListPreAuthKeys(userName string) ([]types.PreAuthKey, er
 	return hsdb.listPreAuthKeys(userName)
 }
 
+// ListPreAuthKeysPage returns the pre-auth keys of page of a user, and the
+// number of keys of the user.
+func (hsdb *HSDatabase) ListPreAuthKeysPage(
+	userName string,
+	page Page,
+) ([]types.PreAuthKey, int64, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	user, err := hsdb.getUser(userName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	err = hsdb.db.
+		Model(&types.PreAuthKey{}).
+		Where(&types.PreAuthKey{UserID: user.ID}).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	keys := []types.PreAuthKey{}
+	err = hsdb.db.
+		Preload("User").
+		Preload("ACLTags").
+		Where(&types.PreAuthKey{UserID: user.ID}).
+		Scopes(page.scope).
+		Find(&keys).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return keys, total, nil
+}
+
 func (hsdb *HSDatabase) listPreAuthKeys(userName string) ([]types.PreAuthKey, error) {
 	user, err := hsdb.getUser(userName)
 	if err != nil {
@@ -148,6 +185,10 @@ func (hsdb *HSDatabase) destroyPreAuthKey(pak types.PreAuthKey) error {
 			return result.Error
 		}
 
+		if result := db.Where("pre_auth_key_id = ?", pak.ID).Delete(&types.IPReservation{}); result.Error != nil {
+			return result.Error
+		}
+
 		if result := db.Unscoped().Delete(pak); result.Error != nil {
 			return result.Error
 		}
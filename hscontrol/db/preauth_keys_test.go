@@ -200,3 +200,32 @@ func (*Suite) TestPreAuthKeyACLTags(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(listedPaks[0].Proto().GetAclTags(), check.DeepEquals, tags)
 }
+
+func (*Suite) TestListPreAuthKeysPage(c *check.C) {
+	user, err := db.CreateUser("test9")
+	c.Assert(err, check.IsNil)
+
+	other, err := db.CreateUser("test10")
+	c.Assert(err, check.IsNil)
+
+	var created []uint64
+	for i := 0; i < 3; i++ {
+		pak, err := db.CreatePreAuthKey(user.Name, false, false, nil, nil)
+		c.Assert(err, check.IsNil)
+
+		created = append(created, pak.ID)
+	}
+
+	_, err = db.CreatePreAuthKey(other.Name, false, false, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	keys, total, err := db.ListPreAuthKeysPage(user.Name, Page{Offset: 1, Limit: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, int64(3))
+	c.Assert(keys, check.HasLen, 1)
+	c.Assert(keys[0].ID, check.Equals, created[1])
+
+	keys, _, err = db.ListPreAuthKeysPage(user.Name, Page{Offset: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(keys, check.HasLen, 2)
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"gorm.io/gorm"
+	"tailscale.com/types/key"
+)
+
+var (
+	ErrPendingRegistrationNotFound = errors.New("pending registration not found")
+	ErrPendingRegistrationRejected = errors.New("registration has been rejected")
+)
+
+// SetPendingRegistration stores a node waiting to be registered until
+// expiry. A registration already rejected for the machine key stays
+// rejected, and the OIDC login started for it is kept.
+func (hsdb *HSDatabase) SetPendingRegistration(node types.Node, expiry time.Time) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	reg, err := hsdb.getPendingRegistration(node.MachineKey)
+	if errors.Is(err, ErrPendingRegistrationNotFound) {
+		// Replace any expired registration of the machine key.
+		err = hsdb.db.
+			Where("machine_key = ?", node.MachineKey.String()).
+			Delete(&types.PendingRegistration{}).Error
+		if err != nil {
+			return err
+		}
+
+		reg = &types.PendingRegistration{
+			MachineKey: node.MachineKey,
+		}
+	} else if err != nil {
+		return err
+	}
+
+	reg.Node = node
+	reg.Expiry = expiry.UTC()
+
+	return hsdb.db.Save(reg).Error
+}
+
+// GetPendingRegistration returns the registration waiting for the
+// machine key, if it has not expired.
+func (hsdb *HSDatabase) GetPendingRegistration(
+	machineKey key.MachinePublic,
+) (*types.PendingRegistration, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	return hsdb.getPendingRegistration(machineKey)
+}
+
+func (hsdb *HSDatabase) getPendingRegistration(
+	machineKey key.MachinePublic,
+) (*types.PendingRegistration, error) {
+	reg := types.PendingRegistration{}
+	err := hsdb.db.
+		Where("machine_key = ? AND expiry > ?", machineKey.String(), time.Now().UTC()).
+		First(&reg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPendingRegistrationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &reg, nil
+}
+
+// SetPendingRegistrationOIDCState records the state of the OIDC login
+// started for the registration of the machine key.
+func (hsdb *HSDatabase) SetPendingRegistrationOIDCState(
+	machineKey key.MachinePublic,
+	state string,
+) error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	reg, err := hsdb.getPendingRegistration(machineKey)
+	if err != nil {
+		return err
+	}
+
+	return hsdb.db.Model(reg).Update("oidc_state", state).Error
+}
+
+// GetPendingRegistrationByOIDCState returns the registration the OIDC
+// login with the given state was started for.
+func (hsdb *HSDatabase) GetPendingRegistrationByOIDCState(
+	state string,
+) (*types.PendingRegistration, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	reg := types.PendingRegistration{}
+	err := hsdb.db.
+		Where("oidc_state = ? AND expiry > ?", state, time.Now().UTC()).
+		First(&reg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPendingRegistrationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &reg, nil
+}
+
+// ListPendingRegistrations returns the registrations that have not
+// expired, including the rejected ones.
+func (hsdb *HSDatabase) ListPendingRegistrations() ([]types.PendingRegistration, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	regs := []types.PendingRegistration{}
+	err := hsdb.db.
+		Where("expiry > ?", time.Now().UTC()).
+		Order("created_at").
+		Find(&regs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return regs, nil
+}
+
+// RejectPendingRegistration marks the registration of the machine key as
+// rejected, it can no longer be completed and is removed when it expires.
+func (hsdb *HSDatabase) RejectPendingRegistration(
+	machineKey key.MachinePublic,
+) (*types.PendingRegistration, error) {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	reg, err := hsdb.getPendingRegistration(machineKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hsdb.db.Model(reg).Update("rejected", true).Error; err != nil {
+		return nil, err
+	}
+	reg.Rejected = true
+
+	return reg, nil
+}
+
+// DeleteExpiredPendingRegistrations removes the registrations that have
+// expired.
+func (hsdb *HSDatabase) DeleteExpiredPendingRegistrations() error {
+	hsdb.mu.Lock()
+	defer hsdb.mu.Unlock()
+
+	return hsdb.db.
+		Where("expiry <= ?", time.Now().UTC()).
+		Delete(&types.PendingRegistration{}).Error
+}
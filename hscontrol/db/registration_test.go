@@ -0,0 +1,246 @@
+package db
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"gopkg.in/check.v1"
+	"tailscale.com/types/key"
+)
+
+func pendingNode(hostname string) types.Node {
+	return types.Node{
+		MachineKey: key.NewMachine().Public(),
+		NodeKey:    key.NewNode().Public(),
+		Hostname:   hostname,
+		GivenName:  hostname,
+		Expiry:     &time.Time{},
+	}
+}
+
+func (*Suite) TestPendingRegistration(c *check.C) {
+	node := pendingNode("pending")
+
+	_, err := db.GetPendingRegistration(node.MachineKey)
+	c.Assert(err, check.Equals, ErrPendingRegistrationNotFound)
+
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	reg, err := db.GetPendingRegistration(node.MachineKey)
+	c.Assert(err, check.IsNil)
+	c.Assert(reg.MachineKey, check.Equals, node.MachineKey)
+	c.Assert(reg.Node.NodeKey, check.Equals, node.NodeKey)
+	c.Assert(reg.Node.Hostname, check.Equals, "pending")
+	c.Assert(reg.Rejected, check.Equals, false)
+
+	// Setting it again updates the existing registration.
+	node.Hostname = "renamed"
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	regs, err := db.ListPendingRegistrations()
+	c.Assert(err, check.IsNil)
+	c.Assert(regs, check.HasLen, 1)
+	c.Assert(regs[0].ID, check.Equals, reg.ID)
+	c.Assert(regs[0].Node.Hostname, check.Equals, "renamed")
+}
+
+func (*Suite) TestPendingRegistrationExpiry(c *check.C) {
+	expired := pendingNode("expired")
+	err := db.SetPendingRegistration(expired, time.Now().Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+
+	valid := pendingNode("valid")
+	err = db.SetPendingRegistration(valid, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	_, err = db.GetPendingRegistration(expired.MachineKey)
+	c.Assert(err, check.Equals, ErrPendingRegistrationNotFound)
+
+	regs, err := db.ListPendingRegistrations()
+	c.Assert(err, check.IsNil)
+	c.Assert(regs, check.HasLen, 1)
+	c.Assert(regs[0].MachineKey, check.Equals, valid.MachineKey)
+
+	err = db.DeleteExpiredPendingRegistrations()
+	c.Assert(err, check.IsNil)
+
+	var count int64
+	err = db.db.Model(&types.PendingRegistration{}).Count(&count).Error
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int64(1))
+
+	// A node coming back after its registration expired starts over.
+	err = db.SetPendingRegistration(valid, time.Now().Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+	err = db.SetPendingRegistration(valid, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	_, err = db.GetPendingRegistration(valid.MachineKey)
+	c.Assert(err, check.IsNil)
+}
+
+func (*Suite) TestPendingRegistrationOIDCState(c *check.C) {
+	node := pendingNode("oidc")
+
+	err := db.SetPendingRegistrationOIDCState(node.MachineKey, "state")
+	c.Assert(err, check.Equals, ErrPendingRegistrationNotFound)
+
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	err = db.SetPendingRegistrationOIDCState(node.MachineKey, "state")
+	c.Assert(err, check.IsNil)
+
+	// The state is kept when the node asks to register again.
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	reg, err := db.GetPendingRegistrationByOIDCState("state")
+	c.Assert(err, check.IsNil)
+	c.Assert(reg.MachineKey, check.Equals, node.MachineKey)
+
+	_, err = db.GetPendingRegistrationByOIDCState("other")
+	c.Assert(err, check.Equals, ErrPendingRegistrationNotFound)
+}
+
+func (*Suite) TestRejectPendingRegistration(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	node := pendingNode("rejected")
+
+	_, err = db.RejectPendingRegistration(node.MachineKey)
+	c.Assert(err, check.Equals, ErrPendingRegistrationNotFound)
+
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	reg, err := db.RejectPendingRegistration(node.MachineKey)
+	c.Assert(err, check.IsNil)
+	c.Assert(reg.Rejected, check.Equals, true)
+
+	// The rejection is kept when the node asks to register again.
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	reg, err = db.GetPendingRegistration(node.MachineKey)
+	c.Assert(err, check.IsNil)
+	c.Assert(reg.Rejected, check.Equals, true)
+
+	_, err = db.RegisterNodeFromAuthCallback(
+		node.MachineKey,
+		user.Name,
+		nil,
+		util.RegisterMethodCLI,
+		false,
+	)
+	c.Assert(err, check.Equals, ErrPendingRegistrationRejected)
+
+	_, err = db.GetNodeByMachineKey(node.MachineKey)
+	c.Assert(err, check.NotNil)
+}
+
+func (*Suite) TestRegisterNodeFromAuthCallback(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	node := pendingNode("callback")
+
+	_, err = db.RegisterNodeFromAuthCallback(
+		node.MachineKey,
+		user.Name,
+		nil,
+		util.RegisterMethodCLI,
+		false,
+	)
+	c.Assert(err, check.Equals, ErrNodeNotFoundRegistrationCache)
+
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	registered, err := db.RegisterNodeFromAuthCallback(
+		node.MachineKey,
+		user.Name,
+		nil,
+		util.RegisterMethodCLI,
+		false,
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(registered.UserID, check.Equals, user.ID)
+	c.Assert(registered.Hostname, check.Equals, "callback")
+
+	c.Assert(registered.PendingApproval, check.Equals, false)
+
+	_, err = db.GetPendingRegistration(node.MachineKey)
+	c.Assert(err, check.Equals, ErrPendingRegistrationNotFound)
+}
+
+func (*Suite) TestRegisterNodeFromAuthCallbackPendingApproval(c *check.C) {
+	user, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	node := pendingNode("approval")
+
+	err = db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	registered, err := db.RegisterNodeFromAuthCallback(
+		node.MachineKey,
+		user.Name,
+		nil,
+		util.RegisterMethodOIDC,
+		true,
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(registered.PendingApproval, check.Equals, true)
+
+	err = db.ApproveNode(registered)
+	c.Assert(err, check.IsNil)
+
+	// Logging in again keeps the approval.
+	registered.NodeKey = key.NewNode().Public()
+	err = db.SetPendingRegistration(*registered, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	registered, err = db.RegisterNodeFromAuthCallback(
+		node.MachineKey,
+		user.Name,
+		nil,
+		util.RegisterMethodOIDC,
+		true,
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(registered.PendingApproval, check.Equals, false)
+}
+
+func (*Suite) TestPendingRegistrationSurvivesRestart(c *check.C) {
+	node := pendingNode("restart")
+
+	err := db.SetPendingRegistration(node, time.Now().Add(time.Minute))
+	c.Assert(err, check.IsNil)
+
+	reopened, err := NewHeadscaleDatabase(
+		"sqlite3",
+		tmpDir+"/headscale_test.db",
+		false,
+		notifier.NewNotifier(),
+		nil,
+		[]netip.Prefix{
+			netip.MustParsePrefix("10.27.0.0/23"),
+		},
+		nil,
+		types.IPAllocationConfig{},
+		"",
+	)
+	c.Assert(err, check.IsNil)
+	defer reopened.Close()
+
+	reg, err := reopened.GetPendingRegistration(node.MachineKey)
+	c.Assert(err, check.IsNil)
+	c.Assert(reg.Node.NodeKey, check.Equals, node.NodeKey)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/netip"
 
+	"github.com/juanfont/headscale/hscontrol/events"
 	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/rs/zerolog/log"
@@ -21,6 +22,30 @@ func (hsdb *HSDatabase) GetRoutes() (types.Routes, error) {
 	return hsdb.getRoutes()
 }
 
+// GetRoutesPage returns the routes of page, and the number of routes. The
+// nodes of the routes are only loaded if withNodes is set.
+func (hsdb *HSDatabase) GetRoutesPage(page Page, withNodes bool) (types.Routes, int64, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	var total int64
+	if err := hsdb.db.Model(&types.Route{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	tx := hsdb.db.Scopes(page.scope)
+	if withNodes {
+		tx = tx.Preload("Node").Preload("Node.User")
+	}
+
+	var routes types.Routes
+	if err := tx.Find(&routes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return routes, total, nil
+}
+
 func (hsdb *HSDatabase) getRoutes() (types.Routes, error) {
 	var routes types.Routes
 	err := hsdb.db.
@@ -211,7 +236,7 @@ func (hsdb *HSDatabase) DisableRoute(id uint64) error {
 		Message:     "called from db.DisableRoute",
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyAll(stateUpdate)
+		hsdb.notifier.NotifyAll(hsdb.ctx, stateUpdate)
 	}
 
 	return nil
@@ -274,7 +299,7 @@ func (hsdb *HSDatabase) DeleteRoute(id uint64) error {
 		Message:     "called from db.DeleteRoute",
 	}
 	if stateUpdate.Valid() {
-		hsdb.notifier.NotifyAll(stateUpdate)
+		hsdb.notifier.NotifyAll(hsdb.ctx, stateUpdate)
 	}
 
 	return nil
@@ -494,7 +519,7 @@ func (hsdb *HSDatabase) FailoverNodeRoutesWithNotify(node *types.Node) error {
 			Message:     "called from db.FailoverNodeRoutesWithNotify",
 		}
 		if stateUpdate.Valid() {
-			hsdb.notifier.NotifyAll(stateUpdate)
+			hsdb.notifier.NotifyAll(hsdb.ctx, stateUpdate)
 		}
 	}
 
@@ -537,7 +562,7 @@ func (hsdb *HSDatabase) failoverRouteWithNotify(r *types.Route) error {
 			Message:     "called from db.failoverRouteWithNotify",
 		}
 		if stateUpdate.Valid() {
-			hsdb.notifier.NotifyAll(stateUpdate)
+			hsdb.notifier.NotifyAll(hsdb.ctx, stateUpdate)
 		}
 	}
 
@@ -574,6 +599,8 @@ func (hsdb *HSDatabase) failoverRoute(r *types.Route) ([]key.MachinePublic, erro
 
 	routes, err := hsdb.getRoutesByPrefix(netip.Prefix(r.Prefix))
 	if err != nil {
+		routeFailovers.WithLabelValues("failed").Inc()
+
 		return nil, err
 	}
 
@@ -585,6 +612,11 @@ func (hsdb *HSDatabase) failoverRoute(r *types.Route) ([]key.MachinePublic, erro
 			continue
 		}
 
+		// Nodes waiting for approval are not seen by their peers.
+		if route.Node.PendingApproval {
+			continue
+		}
+
 		if hsdb.notifier.IsConnected(route.Node.MachineKey) {
 			newPrimary = &routes[idx]
 			break
@@ -597,6 +629,8 @@ func (hsdb *HSDatabase) failoverRoute(r *types.Route) ([]key.MachinePublic, erro
 	// the one currently marked as primary is the
 	// best we got.
 	if newPrimary == nil {
+		routeFailovers.WithLabelValues("no_candidate").Inc()
+
 		return nil, nil
 	}
 
@@ -609,6 +643,7 @@ func (hsdb *HSDatabase) failoverRoute(r *types.Route) ([]key.MachinePublic, erro
 	err = hsdb.db.Save(&r).Error
 	if err != nil {
 		log.Error().Err(err).Msg("error disabling new primary route")
+		routeFailovers.WithLabelValues("failed").Inc()
 
 		return nil, err
 	}
@@ -622,6 +657,7 @@ func (hsdb *HSDatabase) failoverRoute(r *types.Route) ([]key.MachinePublic, erro
 	err = hsdb.db.Save(&newPrimary).Error
 	if err != nil {
 		log.Error().Err(err).Msg("error enabling new primary route")
+		routeFailovers.WithLabelValues("failed").Inc()
 
 		return nil, err
 	}
@@ -630,6 +666,15 @@ func (hsdb *HSDatabase) failoverRoute(r *types.Route) ([]key.MachinePublic, erro
 		Str("hostname", newPrimary.Node.Hostname).
 		Msg("set primary to new route")
 
+	routeFailovers.WithLabelValues("success").Inc()
+
+	hsdb.events.Publish(events.Event{
+		Type:         events.RouteFailover,
+		Node:         &newPrimary.Node,
+		PreviousNode: &r.Node,
+		Route:        newPrimary,
+	})
+
 	// Return a list of the machinekeys of the changed nodes.
 	return []key.MachinePublic{r.Node.MachineKey, newPrimary.Node.MachineKey}, nil
 }
@@ -590,9 +590,12 @@ func TestFailoverRoute(t *testing.T) {
 				tmpDir+"/headscale_test.db",
 				false,
 				notif,
+				nil,
 				[]netip.Prefix{
 					netip.MustParsePrefix("10.27.0.0/23"),
 				},
+				nil,
+				types.IPAllocationConfig{},
 				"",
 			)
 			assert.NoError(t, err)
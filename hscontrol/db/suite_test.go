@@ -6,7 +6,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/juanfont/headscale/hscontrol/events"
 	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/types"
 	"gopkg.in/check.v1"
 )
 
@@ -21,6 +23,7 @@ type Suite struct{}
 var (
 	tmpDir string
 	db     *HSDatabase
+	broker *events.Broker
 )
 
 func (s *Suite) SetUpTest(c *check.C) {
@@ -44,14 +47,19 @@ func (s *Suite) ResetDB(c *check.C) {
 
 	log.Printf("database path: %s", tmpDir+"/headscale_test.db")
 
+	broker = events.NewBroker()
+
 	db, err = NewHeadscaleDatabase(
 		"sqlite3",
 		tmpDir+"/headscale_test.db",
 		false,
 		notifier.NewNotifier(),
+		broker,
 		[]netip.Prefix{
 			netip.MustParsePrefix("10.27.0.0/23"),
 		},
+		nil,
+		types.IPAllocationConfig{},
 		"",
 	)
 	if err != nil {
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+var tracer = otel.Tracer("github.com/juanfont/headscale/hscontrol/db")
+
+const querySpanKey = "headscale:query_span"
+
+// registerQueryTracing records a span for every query made through db,
+// child of the span in the context of the query.
+func registerQueryTracing(db *gorm.DB) error {
+	return registerQueryCallbacks(
+		db,
+		"tracing",
+		func(operation string, tx *gorm.DB) {
+			ctx, span := tracer.Start(
+				tx.Statement.Context,
+				"db."+operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemKey.String(tx.Dialector.Name()),
+					semconv.DBOperation(operation),
+				),
+			)
+			tx.Statement.Context = ctx
+			tx.InstanceSet(querySpanKey, span)
+		},
+		func(_ string, tx *gorm.DB) {
+			value, ok := tx.InstanceGet(querySpanKey)
+			if !ok {
+				return
+			}
+			span := value.(trace.Span)
+			defer span.End()
+
+			span.SetAttributes(
+				semconv.DBSQLTable(tx.Statement.Table),
+				semconv.DBStatement(tx.Statement.SQL.String()),
+				attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+			)
+
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				span.RecordError(tx.Error)
+				span.SetStatus(codes.Error, tx.Error.Error())
+			}
+		},
+	)
+}
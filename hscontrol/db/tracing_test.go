@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gopkg.in/check.v1"
+)
+
+func (s *Suite) TestQueryTracing(c *check.C) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	otel.SetTracerProvider(provider)
+
+	_, err := db.CreateUser("traced")
+	c.Assert(err, check.IsNil)
+
+	// Queries made without a span in their context start their own trace.
+	c.Assert(exporter.GetSpans(), check.Not(check.HasLen), 0)
+	exporter.Reset()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	_, err = db.WithContext(ctx).GetUser("traced")
+	c.Assert(err, check.IsNil)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	c.Assert(spans, check.HasLen, 2)
+
+	query := spans[0]
+	c.Assert(query.Name, check.Equals, "db.query")
+	c.Assert(query.Parent.SpanID(), check.Equals, parent.SpanContext().SpanID())
+
+	attributes := map[string]string{}
+	for _, attribute := range query.Attributes {
+		attributes[string(attribute.Key)] = attribute.Value.Emit()
+	}
+
+	c.Assert(attributes["db.sql.table"], check.Equals, "users")
+	c.Assert(attributes["db.operation"], check.Equals, "query")
+	c.Assert(attributes["db.rows_affected"], check.Equals, "1")
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"errors"
 
+	"github.com/juanfont/headscale/hscontrol/events"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/rs/zerolog/log"
@@ -39,6 +40,11 @@ func (hsdb *HSDatabase) CreateUser(name string) (*types.User, error) {
 		return nil, err
 	}
 
+	hsdb.events.Publish(events.Event{
+		Type: events.UserCreated,
+		User: &user,
+	})
+
 	return &user, nil
 }
 
@@ -76,6 +82,11 @@ func (hsdb *HSDatabase) DestroyUser(name string) error {
 		return result.Error
 	}
 
+	hsdb.events.Publish(events.Event{
+		Type: events.UserDeleted,
+		User: user,
+	})
+
 	return nil
 }
 
@@ -139,6 +150,24 @@ func (hsdb *HSDatabase) ListUsers() ([]types.User, error) {
 	return hsdb.listUsers()
 }
 
+// ListUsersPage gets the users of page, and the number of users.
+func (hsdb *HSDatabase) ListUsersPage(page Page) ([]types.User, int64, error) {
+	hsdb.mu.RLock()
+	defer hsdb.mu.RUnlock()
+
+	var total int64
+	if err := hsdb.db.Model(&types.User{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	users := []types.User{}
+	if err := hsdb.db.Scopes(page.scope).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
 func (hsdb *HSDatabase) listUsers() ([]types.User, error) {
 	users := []types.User{}
 	if err := hsdb.db.Find(&users).Error; err != nil {
@@ -167,7 +196,13 @@ func (hsdb *HSDatabase) listNodesByUser(name string) (types.Nodes, error) {
 	}
 
 	nodes := types.Nodes{}
-	if err := hsdb.db.Preload("AuthKey").Preload("AuthKey.User").Preload("User").Where(&types.Node{UserID: user.ID}).Find(&nodes).Error; err != nil {
+	if err := hsdb.db.
+		Preload("AuthKey").
+		Preload("AuthKey.User").
+		Preload("User").
+		Preload("Routes").
+		Where(&types.Node{UserID: user.ID}).
+		Find(&nodes).Error; err != nil {
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"github.com/juanfont/headscale/hscontrol/events"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"gopkg.in/check.v1"
@@ -23,6 +24,27 @@ func (s *Suite) TestCreateAndDestroyUser(c *check.C) {
 	c.Assert(err, check.NotNil)
 }
 
+func (s *Suite) TestUserEvents(c *check.C) {
+	eventChan, cancel := broker.Subscribe(2)
+	defer cancel()
+
+	_, err := db.CreateUser("test")
+	c.Assert(err, check.IsNil)
+
+	err = db.DestroyUser("test")
+	c.Assert(err, check.IsNil)
+
+	c.Assert(len(eventChan), check.Equals, 2)
+
+	created := <-eventChan
+	c.Assert(created.Type, check.Equals, events.UserCreated)
+	c.Assert(created.User.Name, check.Equals, "test")
+
+	deleted := <-eventChan
+	c.Assert(deleted.Type, check.Equals, events.UserDeleted)
+	c.Assert(deleted.User.Name, check.Equals, "test")
+}
+
 func (s *Suite) TestDestroyUserErrors(c *check.C) {
 	err := db.DestroyUser("test")
 	c.Assert(err, check.Equals, ErrUserNotFound)
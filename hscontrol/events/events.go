@@ -0,0 +1,221 @@
+// Package events publishes lifecycle events of nodes, routes and users
+// to in-process subscribers such as the WatchEvents API and webhooks.
+package events
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Type string
+
+const (
+	NodeRegistered Type = "node.registered"
+	NodeOnline     Type = "node.online"
+	NodeOffline    Type = "node.offline"
+	NodeExpired    Type = "node.expired"
+	NodeExpiring   Type = "node.expiring"
+	RouteFailover  Type = "route.failover"
+	UserCreated    Type = "user.created"
+	UserDeleted    Type = "user.deleted"
+)
+
+// Types lists all event types that are published.
+var Types = []Type{
+	NodeRegistered,
+	NodeOnline,
+	NodeOffline,
+	NodeExpired,
+	NodeExpiring,
+	RouteFailover,
+	UserCreated,
+	UserDeleted,
+}
+
+// Event describes something that happened in headscale. Only the fields
+// relevant to the Type are set; for RouteFailover, Node is the new
+// primary and PreviousNode the node the route failed over from.
+type Event struct {
+	Type Type
+	Time time.Time
+
+	Node         *types.Node
+	PreviousNode *types.Node
+	User         *types.User
+	Route        *types.Route
+}
+
+func (e Event) Proto() *v1.Event {
+	event := &v1.Event{
+		Type:      string(e.Type),
+		CreatedAt: timestamppb.New(e.Time),
+	}
+
+	if e.Node != nil {
+		event.Node = nodeProto(e.Node)
+	}
+
+	if e.PreviousNode != nil {
+		event.PreviousNode = nodeProto(e.PreviousNode)
+	}
+
+	if e.User != nil {
+		event.User = e.User.Proto()
+	}
+
+	if e.Route != nil {
+		event.Route = types.Routes{*e.Route}.Proto()[0]
+		event.Route.Node = nodeProto(&e.Route.Node)
+	}
+
+	return event
+}
+
+// nodeProto returns node for an event, without the key of its pre auth
+// key.
+func nodeProto(node *types.Node) *v1.Node {
+	protoNode := node.Proto()
+	if protoNode.GetPreAuthKey() != nil {
+		protoNode.PreAuthKey.Key = ""
+	}
+
+	return protoNode
+}
+
+// Matches reports if the event is one of the given types, an empty
+// list matches all events.
+func (e Event) Matches(eventTypes []Type) bool {
+	return len(eventTypes) == 0 || slices.Contains(eventTypes, e.Type)
+}
+
+// Broker fans out published events to all subscribers. Publishing never
+// blocks; events are dropped for subscribers that do not keep up.
+type Broker struct {
+	l           sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish sends the event to all current subscribers. It is safe to
+// call on a nil Broker, which discards the event.
+func (b *Broker) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	// The publisher might keep modifying the objects after publishing,
+	// hand the subscribers a copy of them.
+	event.Node = cloneNode(event.Node)
+	event.PreviousNode = cloneNode(event.PreviousNode)
+	event.User = clone(event.User)
+	event.Route = cloneRoute(event.Route)
+
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	for c := range b.subscribers {
+		select {
+		case c <- event:
+		default:
+			log.Warn().
+				Str("event", string(event.Type)).
+				Msg("event subscriber is not keeping up, dropping event")
+		}
+	}
+}
+
+// Subscribe returns a channel receiving all events published from now on
+// and a function that cancels the subscription and closes the channel.
+func (b *Broker) Subscribe(buffer int) (<-chan Event, func()) {
+	c := make(chan Event, buffer)
+
+	b.l.Lock()
+	b.subscribers[c] = struct{}{}
+	b.l.Unlock()
+
+	var once sync.Once
+
+	return c, func() {
+		once.Do(func() {
+			b.l.Lock()
+			delete(b.subscribers, c)
+			b.l.Unlock()
+
+			close(c)
+		})
+	}
+}
+
+func clone[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+
+	return &c
+}
+
+// cloneNode copies node along with the slices, maps and pointers it
+// holds, so the copy shares nothing with node.
+func cloneNode(node *types.Node) *types.Node {
+	if node == nil {
+		return nil
+	}
+
+	c := *node
+	c.EndpointsDatabaseField = slices.Clone(node.EndpointsDatabaseField)
+	c.Endpoints = slices.Clone(node.Endpoints)
+	c.Hostinfo = node.Hostinfo.Clone()
+	c.IPAddresses = slices.Clone(node.IPAddresses)
+	c.ForcedTags = slices.Clone(node.ForcedTags)
+	c.LastSeen = clone(node.LastSeen)
+	c.Expiry = clone(node.Expiry)
+	c.ExpiryNotified = clone(node.ExpiryNotified)
+	c.LastMapRequest = clone(node.LastMapRequest)
+	c.DeletedAt = clone(node.DeletedAt)
+	c.IsOnline = clone(node.IsOnline)
+
+	if node.AuthKey != nil {
+		c.AuthKey = clone(node.AuthKey)
+		c.AuthKey.ACLTags = slices.Clone(node.AuthKey.ACLTags)
+		c.AuthKey.CreatedAt = clone(node.AuthKey.CreatedAt)
+		c.AuthKey.Expiration = clone(node.AuthKey.Expiration)
+	}
+
+	if node.Routes != nil {
+		c.Routes = make([]types.Route, len(node.Routes))
+		for index := range node.Routes {
+			c.Routes[index] = *cloneRoute(&node.Routes[index])
+		}
+	}
+
+	return &c
+}
+
+// cloneRoute copies route and the node it belongs to.
+func cloneRoute(route *types.Route) *types.Route {
+	if route == nil {
+		return nil
+	}
+
+	c := *route
+	c.Node = *cloneNode(&route.Node)
+
+	return &c
+}
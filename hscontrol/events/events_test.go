@@ -0,0 +1,163 @@
+package events
+
+import (
+	"net/netip"
+	"testing"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"tailscale.com/tailcfg"
+)
+
+func TestBrokerPublish(t *testing.T) {
+	broker := NewBroker()
+
+	first, cancelFirst := broker.Subscribe(1)
+	defer cancelFirst()
+
+	second, cancelSecond := broker.Subscribe(1)
+
+	node := &types.Node{Hostname: "node1"}
+	broker.Publish(Event{Type: NodeOnline, Node: node})
+
+	// The subscribers must not see changes made after publishing.
+	node.Hostname = "changed"
+
+	for _, eventChan := range []<-chan Event{first, second} {
+		if len(eventChan) != 1 {
+			t.Fatalf("expected one event, got %d", len(eventChan))
+		}
+
+		event := <-eventChan
+		if event.Type != NodeOnline || event.Node.Hostname != "node1" {
+			t.Errorf("unexpected event %+v", event)
+		}
+
+		if event.Time.IsZero() {
+			t.Errorf("event time was not set")
+		}
+	}
+
+	cancelSecond()
+	cancelSecond()
+
+	if _, ok := <-second; ok {
+		t.Errorf("expected channel to be closed after cancel")
+	}
+
+	// A full subscriber must not block publishing.
+	broker.Publish(Event{Type: NodeOffline})
+	broker.Publish(Event{Type: NodeOffline})
+
+	if len(first) != 1 {
+		t.Errorf("expected one buffered event, got %d", len(first))
+	}
+}
+
+func TestBrokerPublishDeepCopy(t *testing.T) {
+	broker := NewBroker()
+
+	events, cancel := broker.Subscribe(1)
+	defer cancel()
+
+	node := &types.Node{
+		Hostname:    "node1",
+		Hostinfo:    &tailcfg.Hostinfo{OS: "linux", RequestTags: []string{"tag:server"}},
+		IPAddresses: types.NodeAddresses{netip.MustParseAddr("100.64.0.1")},
+		ForcedTags:  types.StringList{"tag:server"},
+		Routes: []types.Route{
+			{Prefix: types.IPPrefix(netip.MustParsePrefix("10.0.0.0/24")), Enabled: true},
+		},
+	}
+	broker.Publish(Event{Type: NodeOnline, Node: node})
+
+	// The slices and maps of the node must not be shared either.
+	node.Hostinfo.OS = "windows"
+	node.Hostinfo.RequestTags[0] = "tag:changed"
+	node.IPAddresses[0] = netip.MustParseAddr("100.64.0.2")
+	node.ForcedTags[0] = "tag:changed"
+	node.Routes[0].Enabled = false
+
+	event := <-events
+	if event.Node.Hostinfo.OS != "linux" || event.Node.Hostinfo.RequestTags[0] != "tag:server" {
+		t.Errorf("hostinfo shared with the publisher: %+v", event.Node.Hostinfo)
+	}
+
+	if event.Node.IPAddresses[0] != netip.MustParseAddr("100.64.0.1") {
+		t.Errorf("addresses shared with the publisher: %v", event.Node.IPAddresses)
+	}
+
+	if event.Node.ForcedTags[0] != "tag:server" {
+		t.Errorf("tags shared with the publisher: %v", event.Node.ForcedTags)
+	}
+
+	if !event.Node.Routes[0].Enabled {
+		t.Errorf("routes shared with the publisher: %+v", event.Node.Routes)
+	}
+}
+
+func TestEventProtoHidesPreAuthKey(t *testing.T) {
+	node := types.Node{
+		Hostname: "node1",
+		AuthKey:  &types.PreAuthKey{Key: "secret"},
+	}
+
+	event := Event{
+		Type:         NodeOnline,
+		Node:         &node,
+		PreviousNode: &node,
+		Route:        &types.Route{Node: node},
+	}
+
+	proto := event.Proto()
+	for _, protoNode := range []*v1.Node{proto.GetNode(), proto.GetPreviousNode(), proto.GetRoute().GetNode()} {
+		if protoNode.GetPreAuthKey() == nil || protoNode.GetPreAuthKey().GetKey() != "" {
+			t.Errorf("expected the pre auth key without its key, got %+v", protoNode.GetPreAuthKey())
+		}
+	}
+
+	if node.AuthKey.Key != "secret" {
+		t.Errorf("the key of the published node was changed")
+	}
+}
+
+func TestBrokerNil(t *testing.T) {
+	var broker *Broker
+
+	broker.Publish(Event{Type: UserCreated})
+}
+
+func TestEventMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		types []Type
+		want  bool
+	}{
+		{
+			name:  "no-filter",
+			event: Event{Type: NodeExpired},
+			want:  true,
+		},
+		{
+			name:  "matching",
+			event: Event{Type: NodeExpired},
+			types: []Type{NodeOnline, NodeExpired},
+			want:  true,
+		},
+		{
+			name:  "not-matching",
+			event: Event{Type: UserDeleted},
+			types: []Type{NodeOnline, NodeExpired},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.Matches(tt.types); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package events
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+)
+
+var errNoEmailAddress = errors.New("user has no email address")
+
+// ExpiryNotifier tells the owner of a node that its key expires soon,
+// from a NodeExpiring event.
+type ExpiryNotifier interface {
+	NotifyExpiring(ctx context.Context, event Event) error
+}
+
+// ExpiryNotifiers returns the notifiers enabled by cfg.
+func ExpiryNotifiers(cfg types.NodeExpiryNotifyConfig) []ExpiryNotifier {
+	notifiers := []ExpiryNotifier{}
+
+	if cfg.Log {
+		notifiers = append(notifiers, LogNotifier{})
+	}
+
+	if cfg.Webhook.URL != "" {
+		notifiers = append(notifiers, NewWebhook(cfg.Webhook))
+	}
+
+	if cfg.SMTP.Address != "" {
+		notifiers = append(notifiers, NewSMTPNotifier(cfg.SMTP))
+	}
+
+	return notifiers
+}
+
+// NotifyExpiring sends the NodeExpiring event through every notifier, it
+// returns the errors of the notifiers that failed to deliver it.
+func NotifyExpiring(ctx context.Context, notifiers []ExpiryNotifier, event Event) error {
+	var errs []error
+
+	for _, notifier := range notifiers {
+		if err := notifier.NotifyExpiring(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%T: %w", notifier, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// LogNotifier writes the notifications to the log.
+type LogNotifier struct{}
+
+func (LogNotifier) NotifyExpiring(_ context.Context, event Event) error {
+	log.Warn().
+		Str("node", event.Node.Hostname).
+		Str("name", event.Node.GivenName).
+		Str("user", event.Node.User.Name).
+		Time("expiry", *event.Node.Expiry).
+		Msg("Node key expires soon")
+
+	return nil
+}
+
+// NotifyExpiring delivers the event to the webhook.
+func (w *Webhook) NotifyExpiring(ctx context.Context, event Event) error {
+	return w.deliver(ctx, event)
+}
+
+// SMTPNotifier emails the owners of the nodes.
+type SMTPNotifier struct {
+	cfg types.SMTPConfig
+
+	// sendMail sends the email, replaced in tests.
+	sendMail func(ctx context.Context, addr string, from string, to []string, msg []byte) error
+}
+
+func NewSMTPNotifier(cfg types.SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{
+		cfg:      cfg,
+		sendMail: sendMail,
+	}
+}
+
+func (n *SMTPNotifier) NotifyExpiring(ctx context.Context, event Event) error {
+	to, err := n.recipient(event.Node.User)
+	if err != nil {
+		return err
+	}
+
+	expiry := event.Node.Expiry.Format(time.RFC1123)
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Date: %s\r\n", event.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Subject: The key of %s expires on %s\r\n", event.Node.GivenName, expiry)
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(&msg, "\r\n")
+	fmt.Fprintf(
+		&msg,
+		"The key of your machine %s (%s) expires on %s.\r\n"+
+			"Log in again on the machine before then, or it will be disconnected from the network.\r\n",
+		event.Node.GivenName,
+		event.Node.Hostname,
+		expiry,
+	)
+
+	return n.sendMail(ctx, n.cfg.Address, n.cfg.From, []string{to}, []byte(msg.String()))
+}
+
+// recipient returns the email address of user, its name if it is an
+// email address or its name at the configured domain.
+func (n *SMTPNotifier) recipient(user types.User) (string, error) {
+	if strings.Contains(user.Name, "@") {
+		return user.Name, nil
+	}
+
+	if n.cfg.Domain == "" {
+		return "", fmt.Errorf("%w: %s", errNoEmailAddress, user.Name)
+	}
+
+	return user.Name + "@" + n.cfg.Domain, nil
+}
+
+// sendMail is smtp.SendMail, without authentication, giving up when ctx
+// is done.
+func sendMail(ctx context.Context, addr string, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// A server that stops answering is cut off.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(msg); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
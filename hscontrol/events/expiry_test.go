@@ -0,0 +1,154 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+)
+
+func TestExpiryNotifiers(t *testing.T) {
+	notifiers := ExpiryNotifiers(types.NodeExpiryNotifyConfig{
+		Log:     true,
+		Webhook: types.WebhookConfig{URL: "http://localhost/expiring"},
+		SMTP:    types.SMTPConfig{Address: "localhost:25", From: "headscale@example.com"},
+	})
+	if len(notifiers) != 3 {
+		t.Fatalf("got %d notifiers, want 3", len(notifiers))
+	}
+
+	if notifiers := ExpiryNotifiers(types.NodeExpiryNotifyConfig{}); len(notifiers) != 0 {
+		t.Errorf("got %d notifiers, want none", len(notifiers))
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	expiry := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		user    string
+		domain  string
+		wantTo  string
+		wantErr error
+	}{
+		{"email user", "alice@example.org", "", "alice@example.org", nil},
+		{"user at domain", "bob", "example.com", "bob@example.com", nil},
+		{"no address", "bob", "", "", errNoEmailAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTo []string
+			var gotMsg string
+
+			notifier := NewSMTPNotifier(types.SMTPConfig{
+				Address: "localhost:25",
+				From:    "headscale@example.com",
+				Domain:  tt.domain,
+			})
+			notifier.sendMail = func(_ context.Context, addr string, from string, to []string, msg []byte) error {
+				if addr != "localhost:25" || from != "headscale@example.com" {
+					t.Errorf("sendMail(%q, %q), want localhost:25 from headscale@example.com", addr, from)
+				}
+				gotTo = to
+				gotMsg = string(msg)
+
+				return nil
+			}
+
+			err := notifier.NotifyExpiring(context.Background(), Event{
+				Type: NodeExpiring,
+				Time: expiry.Add(-72 * time.Hour),
+				Node: &types.Node{
+					Hostname:  "laptop",
+					GivenName: "laptop-1",
+					User:      types.User{Name: tt.user},
+					Expiry:    &expiry,
+				},
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NotifyExpiring() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(gotTo) != 1 || gotTo[0] != tt.wantTo {
+				t.Errorf("to = %v, want [%s]", gotTo, tt.wantTo)
+			}
+
+			for _, want := range []string{
+				"To: " + tt.wantTo + "\r\n",
+				"Subject: The key of laptop-1 expires on Tue, 02 Jan 2024 12:00:00 UTC\r\n",
+				"laptop-1 (laptop)",
+			} {
+				if !strings.Contains(gotMsg, want) {
+					t.Errorf("message does not contain %q:\n%s", want, gotMsg)
+				}
+			}
+		})
+	}
+}
+
+type failingNotifier struct{}
+
+func (failingNotifier) NotifyExpiring(context.Context, Event) error {
+	return errNoEmailAddress
+}
+
+func TestNotifyExpiring(t *testing.T) {
+	event := Event{
+		Type: NodeExpiring,
+		Node: &types.Node{Expiry: &time.Time{}},
+	}
+
+	if err := NotifyExpiring(context.Background(), []ExpiryNotifier{LogNotifier{}}, event); err != nil {
+		t.Errorf("NotifyExpiring() error = %v, want nil", err)
+	}
+
+	err := NotifyExpiring(context.Background(), []ExpiryNotifier{LogNotifier{}, failingNotifier{}}, event)
+	if !errors.Is(err, errNoEmailAddress) {
+		t.Errorf("NotifyExpiring() error = %v, want %v", err, errNoEmailAddress)
+	}
+}
+
+func TestSendMailTimeout(t *testing.T) {
+	// The server accepts the connection but never answers.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- sendMail(ctx, listener.Addr().String(), "headscale@example.com", []string{"alice@example.org"}, nil)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("sendMail() succeeded with a server that does not answer")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("sendMail() did not give up when its context expired")
+	}
+}
@@ -0,0 +1,150 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	WebhookEventHeader     = "X-Headscale-Event"
+	WebhookSignatureHeader = "X-Headscale-Signature"
+
+	webhookSubscriberBuffer = 256
+	webhookAttempts         = 3
+	webhookRetryBackoff     = time.Second
+)
+
+// Webhook delivers events to a single configured URL.
+type Webhook struct {
+	cfg        types.WebhookConfig
+	eventTypes []Type
+	client     *http.Client
+
+	// backoff is the delay before the first retry, doubling
+	// for every following attempt.
+	backoff time.Duration
+}
+
+func NewWebhook(cfg types.WebhookConfig) *Webhook {
+	eventTypes := make([]Type, 0, len(cfg.Events))
+	for _, eventType := range cfg.Events {
+		if !slices.Contains(Types, Type(eventType)) {
+			log.Warn().
+				Str("url", cfg.URL).
+				Str("event", eventType).
+				Msg("webhook is configured with an unknown event type")
+		}
+
+		eventTypes = append(eventTypes, Type(eventType))
+	}
+
+	return &Webhook{
+		cfg:        cfg,
+		eventTypes: eventTypes,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		backoff: webhookRetryBackoff,
+	}
+}
+
+// Run delivers the events published on the broker that match the
+// webhook until the context is cancelled.
+func (w *Webhook) Run(ctx context.Context, broker *Broker) {
+	eventChan, cancel := broker.Subscribe(webhookSubscriberBuffer)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-eventChan:
+			if !event.Matches(w.eventTypes) {
+				continue
+			}
+
+			if err := w.deliver(ctx, event); err != nil {
+				log.Error().
+					Err(err).
+					Str("url", w.cfg.URL).
+					Str("event", string(event.Type)).
+					Msg("failed to deliver event to webhook")
+			}
+		}
+	}
+}
+
+func (w *Webhook) deliver(ctx context.Context, event Event) error {
+	body, err := protojson.Marshal(event.Proto())
+	if err != nil {
+		return fmt.Errorf("marshalling event: %w", err)
+	}
+
+	backoff := w.backoff
+	for attempt := 1; ; attempt++ {
+		err = w.send(ctx, event.Type, body)
+		if err == nil || attempt == webhookAttempts {
+			return err
+		}
+
+		log.Debug().
+			Err(err).
+			Str("url", w.cfg.URL).
+			Int("attempt", attempt).
+			Msg("webhook delivery failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+func (w *Webhook) send(ctx context.Context, eventType Type, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(eventType))
+
+	if w.cfg.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+Sign(w.cfg.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body using secret, as sent
+// in the signature header of webhook requests.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestWebhookDeliver(t *testing.T) {
+	const secret = "supersecret"
+
+	var attempts atomic.Int32
+	received := make(chan *v1.Event, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to exercise the retry.
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading body: %s", err)
+		}
+
+		if got, want := r.Header.Get(WebhookSignatureHeader), "sha256="+Sign(secret, body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+
+		if got := r.Header.Get(WebhookEventHeader); got != string(UserCreated) {
+			t.Errorf("event header = %q, want %q", got, UserCreated)
+		}
+
+		var event v1.Event
+		if err := protojson.Unmarshal(body, &event); err != nil {
+			t.Errorf("unmarshalling event: %s", err)
+		}
+
+		received <- &event
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(types.WebhookConfig{
+		URL:     server.URL,
+		Events:  []string{string(UserCreated)},
+		Secret:  secret,
+		Timeout: time.Second,
+	})
+	webhook.backoff = time.Millisecond
+
+	broker := NewBroker()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go webhook.Run(ctx, broker)
+
+	// Wait for the webhook to subscribe before publishing.
+	for {
+		broker.l.RLock()
+		subscribed := len(broker.subscribers) == 1
+		broker.l.RUnlock()
+
+		if subscribed {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	broker.Publish(Event{Type: NodeOnline, Node: &types.Node{Hostname: "ignored"}})
+	broker.Publish(Event{Type: UserCreated, User: &types.User{Name: "test"}})
+
+	select {
+	case event := <-received:
+		if event.GetType() != string(UserCreated) || event.GetUser().GetName() != "test" {
+			t.Errorf("unexpected event %v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for webhook delivery")
+	}
+
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
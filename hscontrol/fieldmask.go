@@ -0,0 +1,91 @@
+package hscontrol
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var errInvalidReadMask = errors.New("invalid read mask")
+
+// readMask is the tree of the fields kept in the items returned by the
+// List calls, the fields of a message are all kept if it has no child.
+type readMask map[protoreflect.Name]readMask
+
+// newReadMask returns the read mask of the paths of mask for items of the
+// type of item. The returned mask is nil, keeping all the fields, if mask
+// has no path.
+func newReadMask(mask *fieldmaskpb.FieldMask, item proto.Message) (readMask, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+
+	if !mask.IsValid(item) {
+		return nil, fmt.Errorf(
+			"%w %q for %s",
+			errInvalidReadMask,
+			strings.Join(mask.GetPaths(), ","),
+			item.ProtoReflect().Descriptor().Name(),
+		)
+	}
+
+	tree := readMask{}
+	for _, path := range mask.GetPaths() {
+		node := tree
+		for _, name := range strings.Split(path, ".") {
+			child, ok := node[protoreflect.Name(name)]
+			if !ok {
+				child = readMask{}
+				node[protoreflect.Name(name)] = child
+			}
+
+			node = child
+		}
+	}
+
+	return tree, nil
+}
+
+// has reports if the field is kept, in full or in part.
+func (mask readMask) has(field string) bool {
+	if mask == nil {
+		return true
+	}
+
+	_, ok := mask[protoreflect.Name(field)]
+
+	return ok
+}
+
+// apply clears the fields of message not kept by mask.
+func (mask readMask) apply(message proto.Message) {
+	if mask == nil {
+		return
+	}
+
+	mask.prune(message.ProtoReflect())
+}
+
+func (mask readMask) prune(message protoreflect.Message) {
+	var cleared []protoreflect.FieldDescriptor
+
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		child, ok := mask[field.Name()]
+		switch {
+		case !ok:
+			cleared = append(cleared, field)
+		case len(child) > 0 && field.Message() != nil && !field.IsList() && !field.IsMap():
+			child.prune(value.Message())
+		}
+
+		return true
+	})
+
+	for _, field := range cleared {
+		message.Clear(field)
+	}
+}
@@ -2,21 +2,36 @@
 package hscontrol
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/db"
+	"github.com/juanfont/headscale/hscontrol/events"
+	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/key"
 )
 
+// watchEventsBuffer is the number of events buffered for a WatchEvents
+// stream before events are dropped for it.
+const watchEventsBuffer = 64
+
 type headscaleV1APIServer struct { // v1.HeadscaleServiceServer
 	v1.UnimplementedHeadscaleServiceServer
 	h *Headscale
@@ -32,7 +47,7 @@ func (api headscaleV1APIServer) GetUser(
 	ctx context.Context,
 	request *v1.GetUserRequest,
 ) (*v1.GetUserResponse, error) {
-	user, err := api.h.db.GetUser(request.GetName())
+	user, err := api.h.db.WithContext(ctx).GetUser(request.GetName())
 	if err != nil {
 		return nil, err
 	}
@@ -44,11 +59,13 @@ func (api headscaleV1APIServer) CreateUser(
 	ctx context.Context,
 	request *v1.CreateUserRequest,
 ) (*v1.CreateUserResponse, error) {
-	user, err := api.h.db.CreateUser(request.GetName())
+	user, err := api.h.db.WithContext(ctx).CreateUser(request.GetName())
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(ctx, types.AuditUserCreate, auditTarget("user", user.ID), nil, user.Proto())
+
 	return &v1.CreateUserResponse{User: user.Proto()}, nil
 }
 
@@ -56,16 +73,23 @@ func (api headscaleV1APIServer) RenameUser(
 	ctx context.Context,
 	request *v1.RenameUserRequest,
 ) (*v1.RenameUserResponse, error) {
-	err := api.h.db.RenameUser(request.GetOldName(), request.GetNewName())
+	oldUser, err := api.h.db.WithContext(ctx).GetUser(request.GetOldName())
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := api.h.db.GetUser(request.GetNewName())
+	err = api.h.db.WithContext(ctx).RenameUser(request.GetOldName(), request.GetNewName())
 	if err != nil {
 		return nil, err
 	}
 
+	user, err := api.h.db.WithContext(ctx).GetUser(request.GetNewName())
+	if err != nil {
+		return nil, err
+	}
+
+	api.h.audit(ctx, types.AuditUserRename, auditTarget("user", user.ID), oldUser.Proto(), user.Proto())
+
 	return &v1.RenameUserResponse{User: user.Proto()}, nil
 }
 
@@ -73,11 +97,18 @@ func (api headscaleV1APIServer) DeleteUser(
 	ctx context.Context,
 	request *v1.DeleteUserRequest,
 ) (*v1.DeleteUserResponse, error) {
-	err := api.h.db.DestroyUser(request.GetName())
+	user, err := api.h.db.WithContext(ctx).GetUser(request.GetName())
 	if err != nil {
 		return nil, err
 	}
 
+	err = api.h.db.WithContext(ctx).DestroyUser(request.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	api.h.audit(ctx, types.AuditUserDelete, auditTarget("user", user.ID), user.Proto(), nil)
+
 	return &v1.DeleteUserResponse{}, nil
 }
 
@@ -85,7 +116,17 @@ func (api headscaleV1APIServer) ListUsers(
 	ctx context.Context,
 	request *v1.ListUsersRequest,
 ) (*v1.ListUsersResponse, error) {
-	users, err := api.h.db.ListUsers()
+	mask, err := newReadMask(request.GetReadMask(), &v1.User{})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := dbPage(request.GetPageSize(), request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	users, total, err := api.h.db.WithContext(ctx).ListUsersPage(page)
 	if err != nil {
 		return nil, err
 	}
@@ -93,11 +134,16 @@ func (api headscaleV1APIServer) ListUsers(
 	response := make([]*v1.User, len(users))
 	for index, user := range users {
 		response[index] = user.Proto()
+		mask.apply(response[index])
 	}
 
 	log.Trace().Caller().Interface("users", response).Msg("")
 
-	return &v1.ListUsersResponse{Users: response}, nil
+	return &v1.ListUsersResponse{
+		Users:         response,
+		NextPageToken: nextPageToken(page, total),
+		TotalSize:     uint32(total),
+	}, nil
 }
 
 func (api headscaleV1APIServer) CreatePreAuthKey(
@@ -118,7 +164,7 @@ func (api headscaleV1APIServer) CreatePreAuthKey(
 		}
 	}
 
-	preAuthKey, err := api.h.db.CreatePreAuthKey(
+	preAuthKey, err := api.h.db.WithContext(ctx).CreatePreAuthKey(
 		request.GetUser(),
 		request.GetReusable(),
 		request.GetEphemeral(),
@@ -129,6 +175,14 @@ func (api headscaleV1APIServer) CreatePreAuthKey(
 		return nil, err
 	}
 
+	api.h.audit(
+		ctx,
+		types.AuditPreAuthKeyCreate,
+		auditTarget("pre_auth_key", preAuthKey.ID),
+		nil,
+		auditPreAuthKey(preAuthKey),
+	)
+
 	return &v1.CreatePreAuthKeyResponse{PreAuthKey: preAuthKey.Proto()}, nil
 }
 
@@ -136,16 +190,26 @@ func (api headscaleV1APIServer) ExpirePreAuthKey(
 	ctx context.Context,
 	request *v1.ExpirePreAuthKeyRequest,
 ) (*v1.ExpirePreAuthKeyResponse, error) {
-	preAuthKey, err := api.h.db.GetPreAuthKey(request.GetUser(), request.Key)
+	preAuthKey, err := api.h.db.WithContext(ctx).GetPreAuthKey(request.GetUser(), request.Key)
 	if err != nil {
 		return nil, err
 	}
 
-	err = api.h.db.ExpirePreAuthKey(preAuthKey)
+	before := auditPreAuthKey(preAuthKey)
+
+	err = api.h.db.WithContext(ctx).ExpirePreAuthKey(preAuthKey)
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(
+		ctx,
+		types.AuditPreAuthKeyExpire,
+		auditTarget("pre_auth_key", preAuthKey.ID),
+		before,
+		auditPreAuthKey(preAuthKey),
+	)
+
 	return &v1.ExpirePreAuthKeyResponse{}, nil
 }
 
@@ -153,7 +217,17 @@ func (api headscaleV1APIServer) ListPreAuthKeys(
 	ctx context.Context,
 	request *v1.ListPreAuthKeysRequest,
 ) (*v1.ListPreAuthKeysResponse, error) {
-	preAuthKeys, err := api.h.db.ListPreAuthKeys(request.GetUser())
+	mask, err := newReadMask(request.GetReadMask(), &v1.PreAuthKey{})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := dbPage(request.GetPageSize(), request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	preAuthKeys, total, err := api.h.db.WithContext(ctx).ListPreAuthKeysPage(request.GetUser(), page)
 	if err != nil {
 		return nil, err
 	}
@@ -161,9 +235,14 @@ func (api headscaleV1APIServer) ListPreAuthKeys(
 	response := make([]*v1.PreAuthKey, len(preAuthKeys))
 	for index, key := range preAuthKeys {
 		response[index] = key.Proto()
+		mask.apply(response[index])
 	}
 
-	return &v1.ListPreAuthKeysResponse{PreAuthKeys: response}, nil
+	return &v1.ListPreAuthKeysResponse{
+		PreAuthKeys:   response,
+		NextPageToken: nextPageToken(page, total),
+		TotalSize:     uint32(total),
+	}, nil
 }
 
 func (api headscaleV1APIServer) RegisterNode(
@@ -181,25 +260,89 @@ func (api headscaleV1APIServer) RegisterNode(
 		return nil, err
 	}
 
-	node, err := api.h.db.RegisterNodeFromAuthCallback(
-		api.h.registrationCache,
+	reg, err := api.h.db.WithContext(ctx).GetPendingRegistration(mkey)
+	if errors.Is(err, db.ErrPendingRegistrationNotFound) {
+		return nil, db.ErrNodeNotFoundRegistrationCache
+	} else if err != nil {
+		return nil, err
+	}
+
+	user, err := api.h.db.WithContext(ctx).GetUser(request.GetUser())
+	if err != nil {
+		return nil, err
+	}
+
+	var requested time.Time
+	if reg.Node.Expiry != nil {
+		requested = *reg.Node.Expiry
+	}
+	expiry := api.h.keyExpiry(&reg.Node, user, requested)
+
+	node, err := api.h.db.WithContext(ctx).RegisterNodeFromAuthCallback(
 		mkey,
 		request.GetUser(),
-		nil,
+		&expiry,
 		util.RegisterMethodCLI,
+		false,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(ctx, types.AuditNodeRegister, auditTarget("node", node.ID), nil, auditNode(node))
+
 	return &v1.RegisterNodeResponse{Node: node.Proto()}, nil
 }
 
+func (api headscaleV1APIServer) ListPendingRegistrations(
+	ctx context.Context,
+	request *v1.ListPendingRegistrationsRequest,
+) (*v1.ListPendingRegistrationsResponse, error) {
+	regs, err := api.h.db.WithContext(ctx).ListPendingRegistrations()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.PendingRegistration, len(regs))
+	for index, reg := range regs {
+		response[index] = reg.Proto()
+	}
+
+	return &v1.ListPendingRegistrationsResponse{Registrations: response}, nil
+}
+
+func (api headscaleV1APIServer) RejectPendingRegistration(
+	ctx context.Context,
+	request *v1.RejectPendingRegistrationRequest,
+) (*v1.RejectPendingRegistrationResponse, error) {
+	var mkey key.MachinePublic
+	err := mkey.UnmarshalText([]byte(request.GetMachineKey()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reg, err := api.h.db.WithContext(ctx).RejectPendingRegistration(mkey)
+	if errors.Is(err, db.ErrPendingRegistrationNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("machine_key", mkey.ShortString()).
+		Str("hostname", reg.Node.Hostname).
+		Msg("Rejected pending registration")
+
+	api.h.audit(ctx, types.AuditNodeReject, auditTarget("machine_key", mkey.String()), nil, reg.Proto())
+
+	return &v1.RejectPendingRegistrationResponse{Registration: reg.Proto()}, nil
+}
+
 func (api headscaleV1APIServer) GetNode(
 	ctx context.Context,
 	request *v1.GetNodeRequest,
 ) (*v1.GetNodeResponse, error) {
-	node, err := api.h.db.GetNodeByID(request.GetNodeId())
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +353,12 @@ func (api headscaleV1APIServer) GetNode(
 	// currently connected nodes.
 	resp.Online = api.h.nodeNotifier.IsConnected(node.MachineKey)
 
+	resp.ValidTags, resp.InvalidTags = api.h.ACLPolicy().TagsOfNode(node)
+
+	if request.GetDiagnostics() {
+		resp.Diagnostics = node.Diagnostics(api.h.DERPMap)
+	}
+
 	return &v1.GetNodeResponse{Node: resp}, nil
 }
 
@@ -217,7 +366,7 @@ func (api headscaleV1APIServer) SetTags(
 	ctx context.Context,
 	request *v1.SetTagsRequest,
 ) (*v1.SetTagsResponse, error) {
-	node, err := api.h.db.GetNodeByID(request.GetNodeId())
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
 	if err != nil {
 		return nil, err
 	}
@@ -231,13 +380,17 @@ func (api headscaleV1APIServer) SetTags(
 		}
 	}
 
-	err = api.h.db.SetTags(node, request.GetTags())
+	before := auditNode(node)
+
+	err = api.h.db.WithContext(ctx).SetTags(node, request.GetTags())
 	if err != nil {
 		return &v1.SetTagsResponse{
 			Node: nil,
 		}, status.Error(codes.Internal, err.Error())
 	}
 
+	api.h.audit(ctx, types.AuditNodeSetTags, auditTarget("node", node.ID), before, auditNode(node))
+
 	log.Trace().
 		Str("node", node.Hostname).
 		Strs("tags", request.GetTags()).
@@ -263,18 +416,20 @@ func (api headscaleV1APIServer) DeleteNode(
 	ctx context.Context,
 	request *v1.DeleteNodeRequest,
 ) (*v1.DeleteNodeResponse, error) {
-	node, err := api.h.db.GetNodeByID(request.GetNodeId())
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
 	if err != nil {
 		return nil, err
 	}
 
-	err = api.h.db.DeleteNode(
+	err = api.h.db.WithContext(ctx).DeleteNode(
 		node,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(ctx, types.AuditNodeDelete, auditTarget("node", node.ID), auditNode(node), nil)
+
 	return &v1.DeleteNodeResponse{}, nil
 }
 
@@ -282,18 +437,21 @@ func (api headscaleV1APIServer) ExpireNode(
 	ctx context.Context,
 	request *v1.ExpireNodeRequest,
 ) (*v1.ExpireNodeResponse, error) {
-	node, err := api.h.db.GetNodeByID(request.GetNodeId())
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
 	if err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
+	before := auditNode(node)
 
-	api.h.db.NodeSetExpiry(
+	api.h.db.WithContext(ctx).NodeSetExpiry(
 		node,
 		now,
 	)
 
+	api.h.audit(ctx, types.AuditNodeExpire, auditTarget("node", node.ID), before, auditNode(node))
+
 	log.Trace().
 		Str("node", node.Hostname).
 		Time("expiry", *node.Expiry).
@@ -302,16 +460,217 @@ func (api headscaleV1APIServer) ExpireNode(
 	return &v1.ExpireNodeResponse{Node: node.Proto()}, nil
 }
 
+func (api headscaleV1APIServer) SetNodeExpiry(
+	ctx context.Context,
+	request *v1.SetNodeExpiryRequest,
+) (*v1.SetNodeExpiryResponse, error) {
+	if request.GetExpiry() == nil {
+		return nil, status.Error(codes.InvalidArgument, "expiry is required")
+	}
+
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditNode(node)
+
+	if node.ExpiryDisabled {
+		err = api.h.db.WithContext(ctx).NodeSetExpiryDisabled(node, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = api.h.db.WithContext(ctx).NodeSetExpiry(node, request.GetExpiry().AsTime())
+	if err != nil {
+		return nil, err
+	}
+
+	api.h.audit(ctx, types.AuditNodeSetExpiry, auditTarget("node", node.ID), before, auditNode(node))
+
+	log.Trace().
+		Str("node", node.Hostname).
+		Time("expiry", *node.Expiry).
+		Msg("node expiry set")
+
+	return &v1.SetNodeExpiryResponse{Node: node.Proto()}, nil
+}
+
+func (api headscaleV1APIServer) DisableNodeExpiry(
+	ctx context.Context,
+	request *v1.DisableNodeExpiryRequest,
+) (*v1.DisableNodeExpiryResponse, error) {
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditNode(node)
+
+	err = api.h.db.WithContext(ctx).NodeSetExpiryDisabled(node, true)
+	if err != nil {
+		return nil, err
+	}
+
+	api.h.audit(ctx, types.AuditNodeNoExpiry, auditTarget("node", node.ID), before, auditNode(node))
+
+	return &v1.DisableNodeExpiryResponse{Node: node.Proto()}, nil
+}
+
+func (api headscaleV1APIServer) ApproveNode(
+	ctx context.Context,
+	request *v1.ApproveNodeRequest,
+) (*v1.ApproveNodeResponse, error) {
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
+	if err != nil {
+		return nil, err
+	}
+
+	if !node.PendingApproval {
+		return &v1.ApproveNodeResponse{Node: node.Proto()}, nil
+	}
+
+	before := auditNode(node)
+
+	err = api.h.db.WithContext(ctx).ApproveNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	api.h.audit(ctx, types.AuditNodeApprove, auditTarget("node", node.ID), before, auditNode(node))
+
+	log.Info().
+		Str("node", node.Hostname).
+		Msg("node approved")
+
+	return &v1.ApproveNodeResponse{Node: node.Proto()}, nil
+}
+
+func (api headscaleV1APIServer) RejectNode(
+	ctx context.Context,
+	request *v1.RejectNodeRequest,
+) (*v1.RejectNodeResponse, error) {
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
+	if err != nil {
+		return nil, err
+	}
+
+	pending := node.PendingApproval
+	before := auditNode(node)
+
+	err = api.h.db.WithContext(ctx).RejectNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nodes waiting for approval are deleted.
+	if pending {
+		api.h.audit(ctx, types.AuditNodeReject, auditTarget("node", node.ID), before, nil)
+
+		log.Info().
+			Str("node", node.Hostname).
+			Msg("node rejected")
+
+		return &v1.RejectNodeResponse{Node: before}, nil
+	}
+
+	api.h.audit(ctx, types.AuditNodeRevoke, auditTarget("node", node.ID), before, auditNode(node))
+
+	log.Info().
+		Str("node", node.Hostname).
+		Msg("node approval revoked")
+
+	return &v1.RejectNodeResponse{Node: node.Proto()}, nil
+}
+
+func (api headscaleV1APIServer) ListNodesOutsideIPPools(
+	ctx context.Context,
+	request *v1.ListNodesOutsideIPPoolsRequest,
+) (*v1.ListNodesOutsideIPPoolsResponse, error) {
+	outside, err := api.h.db.WithContext(ctx).ListNodesOutsideIPPools()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.NodeOutsideIPPool, len(outside))
+	for index, node := range outside {
+		resp := &v1.NodeOutsideIPPool{
+			Node:      node.Node.Proto(),
+			Addresses: types.NodeAddresses(node.Addresses).StringSlice(),
+		}
+
+		if node.Pool != nil {
+			resp.Pool = node.Pool.String()
+		}
+
+		response[index] = resp
+	}
+
+	return &v1.ListNodesOutsideIPPoolsResponse{Nodes: response}, nil
+}
+
+func (api headscaleV1APIServer) SetNodeIP(
+	ctx context.Context,
+	request *v1.SetNodeIPRequest,
+) (*v1.SetNodeIPResponse, error) {
+	ip, err := netip.ParseAddr(request.GetIp())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditNode(node)
+
+	err = api.h.db.WithContext(ctx).SetNodeIP(node, ip)
+	if err != nil {
+		return nil, staticIPError(err)
+	}
+
+	api.h.audit(ctx, types.AuditNodeSetIP, auditTarget("node", node.ID), before, auditNode(node))
+
+	log.Trace().
+		Str("node", node.Hostname).
+		Str("ip", ip.String()).
+		Msg("Setting address of node")
+
+	return &v1.SetNodeIPResponse{Node: node.Proto()}, nil
+}
+
+// staticIPError returns the gRPC status of an error setting or reserving
+// an address.
+func staticIPError(err error) error {
+	switch {
+	case errors.Is(err, db.ErrIPOutsidePrefixes),
+		errors.Is(err, db.ErrIPNotAssignable),
+		errors.Is(err, db.ErrIPOutsidePool),
+		errors.Is(err, db.ErrIPReservationTarget):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, db.ErrIPInUse), errors.Is(err, db.ErrIPReserved):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, db.ErrIPQuarantined):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return err
+}
+
 func (api headscaleV1APIServer) RenameNode(
 	ctx context.Context,
 	request *v1.RenameNodeRequest,
 ) (*v1.RenameNodeResponse, error) {
-	node, err := api.h.db.GetNodeByID(request.GetNodeId())
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
 	if err != nil {
 		return nil, err
 	}
 
-	err = api.h.db.RenameNode(
+	before := auditNode(node)
+
+	err = api.h.db.WithContext(ctx).RenameNode(
 		node,
 		request.GetNewName(),
 	)
@@ -319,6 +678,8 @@ func (api headscaleV1APIServer) RenameNode(
 		return nil, err
 	}
 
+	api.h.audit(ctx, types.AuditNodeRename, auditTarget("node", node.ID), before, auditNode(node))
+
 	log.Trace().
 		Str("node", node.Hostname).
 		Str("new_name", request.GetNewName()).
@@ -331,78 +692,221 @@ func (api headscaleV1APIServer) ListNodes(
 	ctx context.Context,
 	request *v1.ListNodesRequest,
 ) (*v1.ListNodesResponse, error) {
-	if request.GetUser() != "" {
-		nodes, err := api.h.db.ListNodesByUser(request.GetUser())
+	filter, err := newNodeFilter(request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	mask, err := newReadMask(request.GetReadMask(), &v1.Node{})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The nodes are paginated by the database unless some of them are
+	// filtered out once loaded.
+	var page db.Page
+	if !filter.local() {
+		page, err = dbPage(request.GetPageSize(), request.GetPageToken())
 		if err != nil {
-			return nil, err
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
+	}
 
-		response := make([]*v1.Node, len(nodes))
-		for index, node := range nodes {
-			resp := node.Proto()
+	nodes, total, err := api.h.db.WithContext(ctx).ListNodesPage(filter.db, page)
+	if err != nil {
+		if errors.Is(err, db.ErrUnknownNodeOrder) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 
+		return nil, err
+	}
+
+	listed := make([]listedNode, 0, len(nodes))
+	for _, node := range nodes {
+		candidate := listedNode{
+			node: node,
 			// Populate the online field based on
 			// currently connected nodes.
-			resp.Online = api.h.nodeNotifier.IsConnected(node.MachineKey)
-
-			response[index] = resp
+			online: api.h.nodeNotifier.IsConnected(node.MachineKey),
+		}
+		if len(filter.tags) > 0 {
+			candidate.validTags, candidate.invalidTags = api.h.ACLPolicy().TagsOfNode(node)
 		}
 
-		return &v1.ListNodesResponse{Nodes: response}, nil
+		if filter.match(candidate) {
+			listed = append(listed, candidate)
+		}
 	}
 
-	nodes, err := api.h.db.ListNodes()
-	if err != nil {
-		return nil, err
+	nextPage := nextPageToken(page, total)
+	if filter.local() {
+		total = int64(len(listed))
+
+		listed, nextPage, err = paginate(listed, request.GetPageSize(), request.GetPageToken())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 	}
 
-	response := make([]*v1.Node, len(nodes))
-	for index, node := range nodes {
-		resp := node.Proto()
+	response := make([]*v1.Node, len(listed))
+	for index, listed := range listed {
+		if len(filter.tags) == 0 {
+			listed.validTags, listed.invalidTags = api.h.ACLPolicy().TagsOfNode(listed.node)
+		}
 
-		// Populate the online field based on
-		// currently connected nodes.
-		resp.Online = api.h.nodeNotifier.IsConnected(node.MachineKey)
+		resp := listed.node.Proto()
+		resp.Online = listed.online
+		resp.ValidTags = listed.validTags
+		resp.InvalidTags = listed.invalidTags
 
-		validTags, invalidTags := api.h.ACLPolicy.TagsOfNode(
-			&node,
-		)
-		resp.InvalidTags = invalidTags
-		resp.ValidTags = validTags
+		if request.GetDiagnostics() {
+			resp.Diagnostics = listed.node.Diagnostics(api.h.DERPMap)
+		}
+
+		mask.apply(resp)
 		response[index] = resp
 	}
 
-	return &v1.ListNodesResponse{Nodes: response}, nil
+	return &v1.ListNodesResponse{
+		Nodes:         response,
+		NextPageToken: nextPage,
+		TotalSize:     uint32(total),
+	}, nil
 }
 
 func (api headscaleV1APIServer) MoveNode(
 	ctx context.Context,
 	request *v1.MoveNodeRequest,
 ) (*v1.MoveNodeResponse, error) {
-	node, err := api.h.db.GetNodeByID(request.GetNodeId())
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
 	if err != nil {
 		return nil, err
 	}
 
-	err = api.h.db.AssignNodeToUser(node, request.GetUser())
+	before := auditNode(node)
+
+	err = api.h.db.WithContext(ctx).AssignNodeToUser(node, request.GetUser())
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(ctx, types.AuditNodeMove, auditTarget("node", node.ID), before, auditNode(node))
+
 	return &v1.MoveNodeResponse{Node: node.Proto()}, nil
 }
 
+func (api headscaleV1APIServer) CreateIPReservation(
+	ctx context.Context,
+	request *v1.CreateIPReservationRequest,
+) (*v1.CreateIPReservationResponse, error) {
+	ip, err := netip.ParseAddr(request.GetIp())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reservation := types.IPReservation{
+		IP:       ip,
+		Hostname: request.GetHostname(),
+	}
+
+	if request.GetPreAuthKey() != "" {
+		preAuthKey, err := api.h.db.WithContext(ctx).GetPreAuthKey(request.GetUser(), request.GetPreAuthKey())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		reservation.PreAuthKeyID = &preAuthKey.ID
+	}
+
+	err = api.h.db.WithContext(ctx).CreateIPReservation(&reservation)
+	if err != nil {
+		return nil, staticIPError(err)
+	}
+
+	api.h.audit(
+		ctx,
+		types.AuditIPReservationCreate,
+		auditTarget("ip_reservation", reservation.ID),
+		nil,
+		auditIPReservation(&reservation),
+	)
+
+	return &v1.CreateIPReservationResponse{Reservation: reservation.Proto()}, nil
+}
+
+func (api headscaleV1APIServer) ListIPReservations(
+	ctx context.Context,
+	request *v1.ListIPReservationsRequest,
+) (*v1.ListIPReservationsResponse, error) {
+	reservations, err := api.h.db.WithContext(ctx).ListIPReservations()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.IPReservation, len(reservations))
+	for index := range reservations {
+		response[index] = reservations[index].Proto()
+	}
+
+	return &v1.ListIPReservationsResponse{Reservations: response}, nil
+}
+
+func (api headscaleV1APIServer) DeleteIPReservation(
+	ctx context.Context,
+	request *v1.DeleteIPReservationRequest,
+) (*v1.DeleteIPReservationResponse, error) {
+	reservation, err := api.h.db.WithContext(ctx).GetIPReservation(request.GetId())
+	if errors.Is(err, db.ErrIPReservationNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = api.h.db.WithContext(ctx).DeleteIPReservation(reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	api.h.audit(
+		ctx,
+		types.AuditIPReservationDelete,
+		auditTarget("ip_reservation", reservation.ID),
+		auditIPReservation(reservation),
+		nil,
+	)
+
+	return &v1.DeleteIPReservationResponse{}, nil
+}
+
 func (api headscaleV1APIServer) GetRoutes(
 	ctx context.Context,
 	request *v1.GetRoutesRequest,
 ) (*v1.GetRoutesResponse, error) {
-	routes, err := api.h.db.GetRoutes()
+	mask, err := newReadMask(request.GetReadMask(), &v1.Route{})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := dbPage(request.GetPageSize(), request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The nodes are only loaded when they are returned.
+	routes, total, err := api.h.db.WithContext(ctx).GetRoutesPage(page, mask.has("node"))
 	if err != nil {
 		return nil, err
 	}
 
+	response := routes.Proto()
+	for _, route := range response {
+		mask.apply(route)
+	}
+
 	return &v1.GetRoutesResponse{
-		Routes: types.Routes(routes).Proto(),
+		Routes:        response,
+		NextPageToken: nextPageToken(page, total),
+		TotalSize:     uint32(total),
 	}, nil
 }
 
@@ -410,11 +914,21 @@ func (api headscaleV1APIServer) EnableRoute(
 	ctx context.Context,
 	request *v1.EnableRouteRequest,
 ) (*v1.EnableRouteResponse, error) {
-	err := api.h.db.EnableRoute(request.GetRouteId())
+	before := api.h.auditRoute(request.GetRouteId())
+
+	err := api.h.db.WithContext(ctx).EnableRoute(request.GetRouteId())
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(
+		ctx,
+		types.AuditRouteEnable,
+		auditTarget("route", request.GetRouteId()),
+		before,
+		api.h.auditRoute(request.GetRouteId()),
+	)
+
 	return &v1.EnableRouteResponse{}, nil
 }
 
@@ -422,11 +936,21 @@ func (api headscaleV1APIServer) DisableRoute(
 	ctx context.Context,
 	request *v1.DisableRouteRequest,
 ) (*v1.DisableRouteResponse, error) {
-	err := api.h.db.DisableRoute(request.GetRouteId())
+	before := api.h.auditRoute(request.GetRouteId())
+
+	err := api.h.db.WithContext(ctx).DisableRoute(request.GetRouteId())
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(
+		ctx,
+		types.AuditRouteDisable,
+		auditTarget("route", request.GetRouteId()),
+		before,
+		api.h.auditRoute(request.GetRouteId()),
+	)
+
 	return &v1.DisableRouteResponse{}, nil
 }
 
@@ -434,12 +958,12 @@ func (api headscaleV1APIServer) GetNodeRoutes(
 	ctx context.Context,
 	request *v1.GetNodeRoutesRequest,
 ) (*v1.GetNodeRoutesResponse, error) {
-	node, err := api.h.db.GetNodeByID(request.GetNodeId())
+	node, err := api.h.db.WithContext(ctx).GetNodeByID(request.GetNodeId())
 	if err != nil {
 		return nil, err
 	}
 
-	routes, err := api.h.db.GetNodeRoutes(node)
+	routes, err := api.h.db.WithContext(ctx).GetNodeRoutes(node)
 	if err != nil {
 		return nil, err
 	}
@@ -453,11 +977,21 @@ func (api headscaleV1APIServer) DeleteRoute(
 	ctx context.Context,
 	request *v1.DeleteRouteRequest,
 ) (*v1.DeleteRouteResponse, error) {
-	err := api.h.db.DeleteRoute(request.GetRouteId())
+	before := api.h.auditRoute(request.GetRouteId())
+
+	err := api.h.db.WithContext(ctx).DeleteRoute(request.GetRouteId())
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(
+		ctx,
+		types.AuditRouteDelete,
+		auditTarget("route", request.GetRouteId()),
+		before,
+		nil,
+	)
+
 	return &v1.DeleteRouteResponse{}, nil
 }
 
@@ -470,13 +1004,21 @@ func (api headscaleV1APIServer) CreateApiKey(
 		expiration = request.GetExpiration().AsTime()
 	}
 
-	apiKey, _, err := api.h.db.CreateAPIKey(
+	apiKey, key, err := api.h.db.WithContext(ctx).CreateAPIKey(
 		&expiration,
+		request.GetScopes(),
+		request.GetUsers(),
 	)
 	if err != nil {
+		if errors.Is(err, types.ErrAPIKeyInvalidScope) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
 		return nil, err
 	}
 
+	api.h.audit(ctx, types.AuditAPIKeyCreate, auditTarget("api_key", key.Prefix), nil, key.Proto())
+
 	return &v1.CreateApiKeyResponse{ApiKey: apiKey}, nil
 }
 
@@ -487,16 +1029,20 @@ func (api headscaleV1APIServer) ExpireApiKey(
 	var apiKey *types.APIKey
 	var err error
 
-	apiKey, err = api.h.db.GetAPIKey(request.Prefix)
+	apiKey, err = api.h.db.WithContext(ctx).GetAPIKey(request.Prefix)
 	if err != nil {
 		return nil, err
 	}
 
-	err = api.h.db.ExpireAPIKey(apiKey)
+	before := apiKey.Proto()
+
+	err = api.h.db.WithContext(ctx).ExpireAPIKey(apiKey)
 	if err != nil {
 		return nil, err
 	}
 
+	api.h.audit(ctx, types.AuditAPIKeyExpire, auditTarget("api_key", apiKey.Prefix), before, apiKey.Proto())
+
 	return &v1.ExpireApiKeyResponse{}, nil
 }
 
@@ -504,7 +1050,17 @@ func (api headscaleV1APIServer) ListApiKeys(
 	ctx context.Context,
 	request *v1.ListApiKeysRequest,
 ) (*v1.ListApiKeysResponse, error) {
-	apiKeys, err := api.h.db.ListAPIKeys()
+	mask, err := newReadMask(request.GetReadMask(), &v1.ApiKey{})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := dbPage(request.GetPageSize(), request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	apiKeys, total, err := api.h.db.WithContext(ctx).ListAPIKeysPage(page)
 	if err != nil {
 		return nil, err
 	}
@@ -512,9 +1068,435 @@ func (api headscaleV1APIServer) ListApiKeys(
 	response := make([]*v1.ApiKey, len(apiKeys))
 	for index, key := range apiKeys {
 		response[index] = key.Proto()
+		mask.apply(response[index])
 	}
 
-	return &v1.ListApiKeysResponse{ApiKeys: response}, nil
+	return &v1.ListApiKeysResponse{
+		ApiKeys:       response,
+		NextPageToken: nextPageToken(page, total),
+		TotalSize:     uint32(total),
+	}, nil
+}
+
+func (api headscaleV1APIServer) GetPolicy(
+	ctx context.Context,
+	_ *v1.GetPolicyRequest,
+) (*v1.GetPolicyResponse, error) {
+	acl := api.h.config().ACL
+	switch acl.PolicyMode {
+	case types.PolicyModeDB:
+		pol, err := api.h.db.WithContext(ctx).GetPolicy()
+		if errors.Is(err, types.ErrPolicyNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if err != nil {
+			return nil, fmt.Errorf("loading ACL policy from database: %w", err)
+		}
+
+		return &v1.GetPolicyResponse{
+			Policy:    pol.Data,
+			Version:   uint64(pol.ID),
+			UpdatedAt: timestamppb.New(pol.UpdatedAt),
+		}, nil
+	default:
+		if acl.PolicyPath == "" {
+			return nil, status.Error(codes.NotFound, types.ErrPolicyNotFound.Error())
+		}
+
+		absPath := util.AbsolutePathFromConfigPath(acl.PolicyPath)
+		info, err := os.Stat(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading policy from path %q: %w", absPath, err)
+		}
+
+		b, err := os.ReadFile(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading policy from path %q: %w", absPath, err)
+		}
+
+		return &v1.GetPolicyResponse{
+			Policy:    string(b),
+			UpdatedAt: timestamppb.New(info.ModTime()),
+		}, nil
+	}
+}
+
+func (api headscaleV1APIServer) SetPolicy(
+	ctx context.Context,
+	request *v1.SetPolicyRequest,
+) (*v1.SetPolicyResponse, error) {
+	if api.h.config().ACL.PolicyMode != types.PolicyModeDB {
+		return nil, status.Error(codes.FailedPrecondition, types.ErrPolicyUpdateIsDisabled.Error())
+	}
+
+	p := request.GetPolicy()
+
+	pol, err := policy.LoadACLPolicyFromBytes([]byte(p), "hujson")
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "loading ACL policy: %s", err)
+	}
+
+	// Make sure the policy can be applied to the nodes currently
+	// registered before storing it, it would otherwise break the
+	// map responses of every node.
+	nodes, err := api.h.db.WithContext(ctx).ListNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) > 0 {
+		peers := make(types.Nodes, 0, len(nodes)-1)
+		for index := range nodes[1:] {
+			peers = append(peers, &nodes[index+1])
+		}
+
+		_, _, err = policy.GenerateFilterAndSSHRules(pol, &nodes[0], peers)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "applying ACL policy to nodes: %s", err)
+		}
+	}
+
+	// Hold the policy lock from storing the policy until it is active, a
+	// concurrent reload would otherwise activate an older policy.
+	api.h.aclPolicyLock.Lock()
+
+	var before *v1.GetPolicyResponse
+	if previous, err := api.h.db.WithContext(ctx).GetPolicy(); err == nil {
+		before = &v1.GetPolicyResponse{
+			Policy:    previous.Data,
+			Version:   uint64(previous.ID),
+			UpdatedAt: timestamppb.New(previous.UpdatedAt),
+		}
+	}
+
+	updated, err := api.h.db.WithContext(ctx).SetPolicy(p)
+	if err != nil {
+		api.h.aclPolicyLock.Unlock()
+
+		return nil, err
+	}
+
+	api.h.setACLPolicy(pol)
+	api.h.aclPolicyLock.Unlock()
+
+	response := &v1.SetPolicyResponse{
+		Policy:    updated.Data,
+		Version:   uint64(updated.ID),
+		UpdatedAt: timestamppb.New(updated.UpdatedAt),
+	}
+
+	api.h.audit(ctx, types.AuditPolicySet, "policy", before, response)
+
+	api.h.notifyPolicyChanged()
+
+	log.Info().
+		Uint("version", updated.ID).
+		Msg("ACL policy updated through the API, notifying nodes of change")
+
+	api.h.nodeNotifier.NotifyAll(ctx, types.StateUpdate{
+		Type: types.StateFullUpdate,
+	})
+
+	return response, nil
+}
+
+func (api headscaleV1APIServer) CheckAccess(
+	ctx context.Context,
+	request *v1.CheckAccessRequest,
+) (*v1.CheckAccessResponse, error) {
+	listed, err := api.h.db.WithContext(ctx).ListNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(types.Nodes, 0, len(listed))
+	for index := range listed {
+		nodes = append(nodes, &listed[index])
+	}
+
+	pol := api.h.ACLPolicy()
+
+	// Without a destination, list everything the source can reach.
+	if request.GetDestination() == "" {
+		reachable, err := policy.ReachableFrom(
+			pol,
+			nodes,
+			request.GetSource(),
+			request.GetProtocol(),
+		)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		response := &v1.CheckAccessResponse{
+			Allowed:   len(reachable) > 0,
+			Reachable: make([]*v1.ReachableDestination, len(reachable)),
+		}
+
+		for index, reach := range reachable {
+			names := make([]string, len(reach.Nodes))
+			for i, node := range reach.Nodes {
+				names[i] = node.GivenName
+			}
+
+			response.Reachable[index] = &v1.ReachableDestination{
+				Prefix: reach.Prefix.String(),
+				Ports:  portRangeToString(reach.Ports),
+				Rule:   aclRuleToProto(pol, reach.ACLIndex),
+				Nodes:  names,
+			}
+		}
+
+		return response, nil
+	}
+
+	result, err := policy.CheckAccess(
+		pol,
+		nodes,
+		request.GetSource(),
+		request.GetDestination(),
+		request.GetProtocol(),
+	)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response := &v1.CheckAccessResponse{
+		Allowed: result.Allowed,
+		Rules:   make([]*v1.AclRule, len(result.ACLs)),
+	}
+
+	for index, acl := range result.ACLs {
+		response.Rules[index] = aclRuleToProto(pol, acl)
+	}
+
+	return response, nil
+}
+
+// aclRuleToProto returns the ACL entry at index, or nil if the policy
+// does not have one, as is the case when no policy is loaded.
+func aclRuleToProto(pol *policy.ACLPolicy, index int) *v1.AclRule {
+	if pol == nil || index < 0 || index >= len(pol.ACLs) {
+		return nil
+	}
+
+	acl := pol.ACLs[index]
+
+	return &v1.AclRule{
+		Index:        uint64(index),
+		Action:       acl.Action,
+		Protocol:     acl.Protocol,
+		Sources:      acl.Sources,
+		Destinations: acl.Destinations,
+	}
+}
+
+func portRangeToString(ports tailcfg.PortRange) string {
+	switch {
+	case ports == tailcfg.PortRangeAny:
+		return "*"
+	case ports.First == ports.Last:
+		return strconv.FormatUint(uint64(ports.First), util.Base10)
+	default:
+		return fmt.Sprintf("%d-%d", ports.First, ports.Last)
+	}
+}
+
+func (api headscaleV1APIServer) ListAuditEvents(
+	ctx context.Context,
+	request *v1.ListAuditEventsRequest,
+) (*v1.ListAuditEventsResponse, error) {
+	filter := db.AuditEventFilter{
+		Actor:  request.GetActor(),
+		Action: request.GetAction(),
+		Target: request.GetTarget(),
+		Limit:  int(request.GetLimit()),
+	}
+
+	if request.GetSince() != nil {
+		filter.Since = request.GetSince().AsTime()
+	}
+
+	events, err := api.h.db.WithContext(ctx).ListAuditEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.AuditEvent, len(events))
+	for index, event := range events {
+		response[index] = event.Proto()
+	}
+
+	return &v1.ListAuditEventsResponse{Events: response}, nil
+}
+
+func (api headscaleV1APIServer) WatchEvents(
+	request *v1.WatchEventsRequest,
+	stream v1.HeadscaleService_WatchEventsServer,
+) error {
+	eventTypes := make([]events.Type, 0, len(request.GetTypes()))
+	for _, eventType := range request.GetTypes() {
+		if !slices.Contains(events.Types, events.Type(eventType)) {
+			return status.Errorf(codes.InvalidArgument, "unknown event type %q", eventType)
+		}
+
+		eventTypes = append(eventTypes, events.Type(eventType))
+	}
+
+	eventChan, cancel := api.h.events.Subscribe(watchEventsBuffer)
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-api.h.shutdownChan:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event := <-eventChan:
+			if !event.Matches(eventTypes) {
+				continue
+			}
+
+			if err := stream.Send(event.Proto()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamChunkSize is the size of the chunks backups and exports are
+// streamed in, gRPC limits messages to 4MB.
+const streamChunkSize = 1 << 20
+
+func (api headscaleV1APIServer) BackupDatabase(
+	request *v1.BackupDatabaseRequest,
+	stream v1.HeadscaleService_BackupDatabaseServer,
+) error {
+	writer := bufio.NewWriterSize(chunkWriter(func(data []byte) error {
+		return stream.Send(&v1.BackupDatabaseResponse{Data: data})
+	}), streamChunkSize)
+
+	if err := api.h.db.Backup(writer); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return writer.Flush()
+}
+
+func (api headscaleV1APIServer) RestoreDatabase(
+	stream v1.HeadscaleService_RestoreDatabaseServer,
+) error {
+	err := api.h.db.Restore(&chunkReader{recv: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+
+		return chunk.GetData(), err
+	}})
+	switch {
+	case errors.Is(err, db.ErrBackupInvalid),
+		errors.Is(err, db.ErrBackupDBType),
+		errors.Is(err, db.ErrBackupSchemaUnknown),
+		errors.Is(err, db.ErrBackupSchemaMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	api.h.audit(stream.Context(), types.AuditDatabaseRestore, "database", nil, nil)
+
+	if api.h.config().ACL.PolicyMode == types.PolicyModeDB {
+		// The reload is logged, the restore itself succeeded.
+		_ = api.h.reloadACLPolicy("restore")
+		api.h.notifyPolicyChanged()
+	}
+
+	return stream.SendAndClose(&v1.RestoreDatabaseResponse{})
+}
+
+func (api headscaleV1APIServer) ExportState(
+	request *v1.ExportStateRequest,
+	stream v1.HeadscaleService_ExportStateServer,
+) error {
+	export, err := api.h.db.Export()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	writer := bufio.NewWriterSize(chunkWriter(func(data []byte) error {
+		return stream.Send(&v1.ExportStateResponse{Data: data})
+	}), streamChunkSize)
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+func (api headscaleV1APIServer) ImportState(
+	stream v1.HeadscaleService_ImportStateServer,
+) error {
+	var export types.Export
+	err := json.NewDecoder(&chunkReader{recv: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+
+		return chunk.GetData(), err
+	}}).Decode(&export)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid export: %s", err)
+	}
+
+	err = api.h.db.Import(&export)
+	switch {
+	case errors.Is(err, db.ErrExportVersion):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, db.ErrImportNotEmpty):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	api.h.audit(stream.Context(), types.AuditStateImport, "database", nil, nil)
+
+	if api.h.config().ACL.PolicyMode == types.PolicyModeDB && export.Policy != nil {
+		// The reload is logged, the import itself succeeded.
+		_ = api.h.reloadACLPolicy("import")
+		api.h.notifyPolicyChanged()
+	}
+
+	return stream.SendAndClose(&v1.ImportStateResponse{})
+}
+
+// chunkWriter sends everything written to it as a chunk of a stream.
+type chunkWriter func(data []byte) error
+
+func (w chunkWriter) Write(data []byte) (int, error) {
+	if err := w(data); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// chunkReader reads the chunks of a stream sent by the client.
+type chunkReader struct {
+	recv func() ([]byte, error)
+	buf  []byte
+}
+
+func (r *chunkReader) Read(data []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk
+	}
+
+	n := copy(data, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
 }
 
 // The following service calls are for testing and debugging
@@ -522,7 +1504,7 @@ func (api headscaleV1APIServer) DebugCreateNode(
 	ctx context.Context,
 	request *v1.DebugCreateNodeRequest,
 ) (*v1.DebugCreateNodeResponse, error) {
-	user, err := api.h.db.GetUser(request.GetUser())
+	user, err := api.h.db.WithContext(ctx).GetUser(request.GetUser())
 	if err != nil {
 		return nil, err
 	}
@@ -550,7 +1532,7 @@ func (api headscaleV1APIServer) DebugCreateNode(
 		return nil, err
 	}
 
-	givenName, err := api.h.db.GenerateGivenName(mkey, request.GetName())
+	givenName, err := api.h.db.WithContext(ctx).GenerateGivenName(mkey, request.GetName())
 	if err != nil {
 		return nil, err
 	}
@@ -572,13 +1554,15 @@ func (api headscaleV1APIServer) DebugCreateNode(
 
 	log.Debug().
 		Str("machine_key", mkey.ShortString()).
-		Msg("adding debug machine via CLI, appending to registration cache")
+		Msg("adding debug machine via CLI, adding to pending registrations")
 
-	api.h.registrationCache.Set(
-		mkey.String(),
+	err = api.h.db.WithContext(ctx).SetPendingRegistration(
 		newNode,
-		registerCacheExpiration,
+		time.Now().Add(registerCacheExpiration),
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	return &v1.DebugCreateNodeResponse{Node: newNode.Proto()}, nil
 }
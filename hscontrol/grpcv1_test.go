@@ -1,6 +1,26 @@
 package hscontrol
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/policy"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/check.v1"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+)
 
 func Test_validateTag(t *testing.T) {
 	type args struct {
@@ -40,3 +60,354 @@ func Test_validateTag(t *testing.T) {
 		})
 	}
 }
+
+func (s *Suite) TestSetPolicyRequiresDatabaseMode(c *check.C) {
+	api := newHeadscaleV1APIServer(app)
+
+	_, err := api.SetPolicy(context.Background(), &v1.SetPolicyRequest{
+		Policy: `{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}]}`,
+	})
+	c.Assert(status.Code(err), check.Equals, codes.FailedPrecondition)
+}
+
+func (s *Suite) TestGetPolicyNotFound(c *check.C) {
+	api := newHeadscaleV1APIServer(app)
+
+	_, err := api.GetPolicy(context.Background(), &v1.GetPolicyRequest{})
+	c.Assert(status.Code(err), check.Equals, codes.NotFound)
+
+	app.config().ACL.PolicyMode = types.PolicyModeDB
+	defer func() { app.config().ACL.PolicyMode = "" }()
+
+	_, err = api.GetPolicy(context.Background(), &v1.GetPolicyRequest{})
+	c.Assert(status.Code(err), check.Equals, codes.NotFound)
+}
+
+func (s *Suite) TestSetAndGetPolicy(c *check.C) {
+	app.config().ACL.PolicyMode = types.PolicyModeDB
+	defer func() { app.config().ACL.PolicyMode = "" }()
+
+	api := newHeadscaleV1APIServer(app)
+
+	_, err := api.SetPolicy(context.Background(), &v1.SetPolicyRequest{
+		Policy: `{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}], "tests": [{"src": "user1", "deny": ["10.0.0.1:22"]}]}`,
+	})
+	c.Assert(status.Code(err), check.Equals, codes.InvalidArgument)
+	c.Assert(app.ACLPolicy(), check.IsNil)
+
+	pol := `{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}]}`
+	set, err := api.SetPolicy(context.Background(), &v1.SetPolicyRequest{Policy: pol})
+	c.Assert(err, check.IsNil)
+	c.Assert(app.ACLPolicy(), check.NotNil)
+
+	got, err := api.GetPolicy(context.Background(), &v1.GetPolicyRequest{})
+	c.Assert(err, check.IsNil)
+	c.Assert(got.GetPolicy(), check.Equals, pol)
+	c.Assert(got.GetVersion(), check.Equals, set.GetVersion())
+}
+
+func (s *Suite) TestSetPolicyConcurrentWithReload(c *check.C) {
+	app.config().ACL.PolicyMode = types.PolicyModeDB
+	defer func() { app.config().ACL.PolicyMode = "" }()
+
+	api := newHeadscaleV1APIServer(app)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		ports := make([]string, i)
+		for port := range ports {
+			ports[port] = fmt.Sprintf(`{"action": "accept", "src": ["*"], "dst": ["*:%d"]}`, port+1)
+		}
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			_, err := api.SetPolicy(context.Background(), &v1.SetPolicyRequest{
+				Policy: fmt.Sprintf(`{"acls": [%s]}`, strings.Join(ports, ",")),
+			})
+			c.Check(err, check.IsNil)
+		}()
+		go func() {
+			defer wg.Done()
+
+			c.Check(app.reloadACLPolicy("test"), check.IsNil)
+		}()
+	}
+	wg.Wait()
+
+	// The active policy must be the one stored last, whatever order the
+	// updates and reloads ran in.
+	stored, err := api.GetPolicy(context.Background(), &v1.GetPolicyRequest{})
+	c.Assert(err, check.IsNil)
+
+	want, err := policy.LoadACLPolicyFromBytes([]byte(stored.GetPolicy()), "hujson")
+	c.Assert(err, check.IsNil)
+	c.Assert(app.ACLPolicy().ACLs, check.HasLen, len(want.ACLs))
+}
+
+func (s *Suite) TestCheckAccess(c *check.C) {
+	app.setACLPolicy(&policy.ACLPolicy{
+		ACLs: []policy.ACL{
+			{
+				Action:       "accept",
+				Sources:      []string{"10.27.0.1"},
+				Destinations: []string{"10.27.0.2:22"},
+			},
+		},
+	})
+
+	api := newHeadscaleV1APIServer(app)
+
+	allowed, err := api.CheckAccess(context.Background(), &v1.CheckAccessRequest{
+		Source:      "10.27.0.1",
+		Destination: "10.27.0.2:22",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(allowed.GetAllowed(), check.Equals, true)
+	c.Assert(allowed.GetRules(), check.HasLen, 1)
+	c.Assert(allowed.GetRules()[0].GetIndex(), check.Equals, uint64(0))
+
+	denied, err := api.CheckAccess(context.Background(), &v1.CheckAccessRequest{
+		Source:      "10.27.0.1",
+		Destination: "10.27.0.2:80",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(denied.GetAllowed(), check.Equals, false)
+	c.Assert(denied.GetRules(), check.HasLen, 0)
+
+	reachable, err := api.CheckAccess(context.Background(), &v1.CheckAccessRequest{
+		Source: "10.27.0.1",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(reachable.GetReachable(), check.HasLen, 1)
+	c.Assert(reachable.GetReachable()[0].GetPrefix(), check.Equals, "10.27.0.2/32")
+	c.Assert(reachable.GetReachable()[0].GetPorts(), check.Equals, "22")
+
+	_, err = api.CheckAccess(context.Background(), &v1.CheckAccessRequest{
+		Source:      "10.27.0.1",
+		Destination: "10.27.0.2",
+	})
+	c.Assert(status.Code(err), check.Equals, codes.InvalidArgument)
+}
+
+func (s *Suite) TestListNodesFilters(c *check.C) {
+	user, err := app.db.CreateUser("list")
+	c.Assert(err, check.IsNil)
+
+	expired := time.Now().Add(-time.Hour)
+
+	register := func(hostname, os string, tags []string, expiry *time.Time, routes ...netip.Prefix) {
+		node, err := app.db.RegisterNode(types.Node{
+			MachineKey:     key.NewMachine().Public(),
+			NodeKey:        key.NewNode().Public(),
+			DiscoKey:       key.NewDisco().Public(),
+			Hostname:       hostname,
+			UserID:         user.ID,
+			RegisterMethod: util.RegisterMethodCLI,
+			ForcedTags:     tags,
+			Expiry:         expiry,
+			Hostinfo: &tailcfg.Hostinfo{
+				OS:          os,
+				RoutableIPs: routes,
+			},
+		})
+		c.Assert(err, check.IsNil)
+
+		_, err = app.db.SaveNodeRoutes(node)
+		c.Assert(err, check.IsNil)
+	}
+
+	register("web-1", "linux", []string{"tag:web"}, &expired, netip.MustParsePrefix("10.1.0.0/24"))
+	register("web-2", "windows", []string{"tag:web"}, nil)
+	register("db-1", "linux", nil, nil)
+
+	api := newHeadscaleV1APIServer(app)
+
+	hostnames := func(request *v1.ListNodesRequest) []string {
+		response, err := api.ListNodes(context.Background(), request)
+		c.Assert(err, check.IsNil)
+
+		names := []string{}
+		for _, node := range response.GetNodes() {
+			names = append(names, node.GetName())
+		}
+
+		return names
+	}
+
+	notExpired := false
+	offline := false
+
+	c.Assert(hostnames(&v1.ListNodesRequest{}), check.DeepEquals, []string{"web-1", "web-2", "db-1"})
+	c.Assert(hostnames(&v1.ListNodesRequest{Tags: []string{"tag:web"}}), check.DeepEquals, []string{"web-1", "web-2"})
+	c.Assert(hostnames(&v1.ListNodesRequest{Expired: &notExpired}), check.DeepEquals, []string{"web-2", "db-1"})
+	c.Assert(hostnames(&v1.ListNodesRequest{Online: &offline, Os: "Linux"}), check.DeepEquals, []string{"web-1", "db-1"})
+	c.Assert(hostnames(&v1.ListNodesRequest{Route: "10.1.0.0/24"}), check.DeepEquals, []string{"web-1"})
+	c.Assert(hostnames(&v1.ListNodesRequest{User: "list", Hostname: "web-*"}), check.DeepEquals, []string{"web-1", "web-2"})
+	c.Assert(hostnames(&v1.ListNodesRequest{OrderBy: "hostname desc"}), check.DeepEquals, []string{"web-2", "web-1", "db-1"})
+	c.Assert(hostnames(&v1.ListNodesRequest{OrderBy: "expiry"}), check.DeepEquals, []string{"web-2", "db-1", "web-1"})
+
+	first, err := api.ListNodes(context.Background(), &v1.ListNodesRequest{OrderBy: "hostname", PageSize: 2})
+	c.Assert(err, check.IsNil)
+	c.Assert(first.GetNodes(), check.HasLen, 2)
+	c.Assert(first.GetTotalSize(), check.Equals, uint32(3))
+	c.Assert(first.GetNextPageToken(), check.Not(check.Equals), "")
+
+	c.Assert(
+		hostnames(&v1.ListNodesRequest{OrderBy: "hostname", PageSize: 2, PageToken: first.GetNextPageToken()}),
+		check.DeepEquals,
+		[]string{"web-2"},
+	)
+
+	// The nodes filtered once loaded are paginated as well.
+	tagged, err := api.ListNodes(context.Background(), &v1.ListNodesRequest{Tags: []string{"tag:web"}, PageSize: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(tagged.GetNodes(), check.HasLen, 1)
+	c.Assert(tagged.GetTotalSize(), check.Equals, uint32(2))
+	c.Assert(tagged.GetNextPageToken(), check.Not(check.Equals), "")
+
+	for _, request := range []*v1.ListNodesRequest{
+		{OrderBy: "color"},
+		{OrderBy: "hostname sideways"},
+		{Route: "10.1.0.0"},
+		{Hostname: "web-["},
+		{PageSize: 2, PageToken: "not a token"},
+	} {
+		_, err := api.ListNodes(context.Background(), request)
+		c.Assert(status.Code(err), check.Equals, codes.InvalidArgument)
+	}
+}
+
+func (s *Suite) TestListUsersPages(c *check.C) {
+	for _, name := range []string{"alice", "bob", "carol"} {
+		_, err := app.db.CreateUser(name)
+		c.Assert(err, check.IsNil)
+	}
+
+	api := newHeadscaleV1APIServer(app)
+
+	request := &v1.ListUsersRequest{
+		PageSize: 2,
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+	}
+
+	first, err := api.ListUsers(context.Background(), request)
+	c.Assert(err, check.IsNil)
+	c.Assert(first.GetUsers(), check.HasLen, 2)
+	c.Assert(first.GetTotalSize(), check.Equals, uint32(3))
+	c.Assert(first.GetUsers()[0].GetName(), check.Equals, "alice")
+	c.Assert(first.GetUsers()[0].GetId(), check.Equals, "")
+	c.Assert(first.GetUsers()[0].GetCreatedAt(), check.IsNil)
+
+	request.PageToken = first.GetNextPageToken()
+	last, err := api.ListUsers(context.Background(), request)
+	c.Assert(err, check.IsNil)
+	c.Assert(last.GetUsers(), check.HasLen, 1)
+	c.Assert(last.GetUsers()[0].GetName(), check.Equals, "carol")
+	c.Assert(last.GetNextPageToken(), check.Equals, "")
+
+	all, err := api.ListUsers(context.Background(), &v1.ListUsersRequest{})
+	c.Assert(err, check.IsNil)
+	c.Assert(all.GetUsers(), check.HasLen, 3)
+	c.Assert(all.GetUsers()[0].GetId(), check.Not(check.Equals), "")
+
+	for _, request := range []*v1.ListUsersRequest{
+		{PageToken: "not a token"},
+		{ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"color"}}},
+	} {
+		_, err := api.ListUsers(context.Background(), request)
+		c.Assert(status.Code(err), check.Equals, codes.InvalidArgument)
+	}
+}
+
+func (s *Suite) TestGetRoutesReadMask(c *check.C) {
+	user, err := app.db.CreateUser("routes")
+	c.Assert(err, check.IsNil)
+
+	node, err := app.db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		DiscoKey:       key.NewDisco().Public(),
+		Hostname:       "router",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodCLI,
+		Hostinfo: &tailcfg.Hostinfo{
+			RoutableIPs: []netip.Prefix{netip.MustParsePrefix("10.2.0.0/24")},
+		},
+	})
+	c.Assert(err, check.IsNil)
+
+	_, err = app.db.SaveNodeRoutes(node)
+	c.Assert(err, check.IsNil)
+
+	api := newHeadscaleV1APIServer(app)
+
+	response, err := api.GetRoutes(context.Background(), &v1.GetRoutesRequest{
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"prefix", "node.name"}},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(response.GetRoutes(), check.HasLen, 1)
+
+	route := response.GetRoutes()[0]
+	c.Assert(route.GetPrefix(), check.Equals, "10.2.0.0/24")
+	c.Assert(route.GetId(), check.Equals, uint64(0))
+	c.Assert(route.GetNode().GetName(), check.Equals, "router")
+	c.Assert(route.GetNode().GetUser(), check.IsNil)
+
+	response, err = api.GetRoutes(context.Background(), &v1.GetRoutesRequest{
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id"}},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(response.GetRoutes()[0].GetId(), check.Not(check.Equals), uint64(0))
+	c.Assert(response.GetRoutes()[0].GetNode(), check.IsNil)
+}
+
+func (s *Suite) TestSetAndDisableNodeExpiry(c *check.C) {
+	user, err := app.db.CreateUser("expiry")
+	c.Assert(err, check.IsNil)
+
+	node, err := app.db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		DiscoKey:       key.NewDisco().Public(),
+		Hostname:       "laptop",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodCLI,
+		Hostinfo:       &tailcfg.Hostinfo{},
+	})
+	c.Assert(err, check.IsNil)
+
+	api := newHeadscaleV1APIServer(app)
+
+	disabled, err := api.DisableNodeExpiry(context.Background(), &v1.DisableNodeExpiryRequest{NodeId: node.ID})
+	c.Assert(err, check.IsNil)
+	c.Assert(disabled.GetNode().GetExpiryDisabled(), check.Equals, true)
+	c.Assert(disabled.GetNode().GetExpiry().AsTime().IsZero(), check.Equals, true)
+
+	// The policy does not apply to nodes with a disabled expiry.
+	app.config().NodeExpiry = types.NodeExpiryConfig{Default: time.Hour}
+	stored, err := app.db.GetNodeByID(node.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(app.keyExpiry(stored, user, time.Time{}).IsZero(), check.Equals, true)
+
+	expiry := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	set, err := api.SetNodeExpiry(context.Background(), &v1.SetNodeExpiryRequest{
+		NodeId: node.ID,
+		Expiry: timestamppb.New(expiry),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(set.GetNode().GetExpiryDisabled(), check.Equals, false)
+	c.Assert(set.GetNode().GetExpiry().AsTime().Equal(expiry), check.Equals, true)
+
+	stored, err = app.db.GetNodeByID(node.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.ExpiryDisabled, check.Equals, false)
+
+	// The earliest of the policy and the requested expiry is used.
+	c.Assert(app.keyExpiry(stored, user, expiry).Before(time.Now().Add(2*time.Hour)), check.Equals, true)
+	c.Assert(app.keyExpiry(stored, user, time.Now().Add(time.Minute)).Before(time.Now().Add(2*time.Minute)), check.Equals, true)
+
+	_, err = api.SetNodeExpiry(context.Background(), &v1.SetNodeExpiryRequest{NodeId: node.ID})
+	c.Assert(status.Code(err), check.Equals, codes.InvalidArgument)
+}
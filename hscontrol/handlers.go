@@ -124,8 +124,11 @@ func (h *Headscale) HealthHandler(
 
 		res := struct {
 			Status string `json:"status"`
+			// Leader is whether this process runs the background jobs.
+			Leader bool `json:"leader"`
 		}{
 			Status: "pass",
+			Leader: h.leader.IsLeader(),
 		}
 
 		if err != nil {
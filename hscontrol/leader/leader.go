@@ -0,0 +1,167 @@
+// Package leader elects the headscale process running the background
+// jobs, like expiring nodes, when several processes share a database.
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/db"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// leaseName is the lease of the background jobs.
+	leaseName = "background-jobs"
+
+	// LeaseDuration is how long a process stays the leader without
+	// renewing its lease.
+	LeaseDuration = 30 * time.Second
+
+	// renewInterval leaves the leader two attempts to renew its lease
+	// before it expires.
+	renewInterval = LeaseDuration / 3
+)
+
+// Elector tells whether this process is the leader.
+type Elector interface {
+	// IsLeader returns whether this process is the leader, and should
+	// run the background jobs.
+	IsLeader() bool
+
+	// Run takes part in the election until ctx is cancelled, calling
+	// onChange when this process gains or loses leadership.
+	Run(ctx context.Context, onChange func(leader bool))
+
+	// Close steps down, another process can become the leader right
+	// away.
+	Close() error
+}
+
+// New returns the elector for the type of database. PostgreSQL databases
+// can be shared by several processes, which hold a lease in the database
+// in turn. SQLite databases are used by a single process, which is
+// always the leader.
+func New(dbType string, database *db.HSDatabase, holder string) Elector {
+	if dbType == db.Postgres {
+		return NewLeaseElector(database, holder)
+	}
+
+	return Single{}
+}
+
+// Single is the Elector of a process not sharing its database, it is
+// always the leader.
+type Single struct{}
+
+func (Single) IsLeader() bool {
+	return true
+}
+
+func (Single) Run(_ context.Context, onChange func(leader bool)) {
+	onChange(true)
+}
+
+func (Single) Close() error {
+	return nil
+}
+
+// LeaseElector elects the process holding a lease in the database.
+type LeaseElector struct {
+	db     *db.HSDatabase
+	holder string
+
+	mu     sync.Mutex
+	leader bool
+	closed bool
+
+	// until is the time the lease expires at, as far as this process
+	// knows. The leader steps down by then if it cannot renew it.
+	until time.Time
+}
+
+func NewLeaseElector(database *db.HSDatabase, holder string) *LeaseElector {
+	return &LeaseElector{
+		db:     database,
+		holder: holder,
+	}
+}
+
+func (e *LeaseElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.leader && time.Now().Before(e.until)
+}
+
+func (e *LeaseElector) Run(ctx context.Context, onChange func(leader bool)) {
+	ticker := time.NewTicker(renewInterval)
+	defer ticker.Stop()
+
+	wasLeader := false
+
+	for {
+		leader := e.acquire()
+		if leader != wasLeader {
+			if leader {
+				log.Info().Str("holder", e.holder).Msg("Became the leader, running the background jobs")
+			} else {
+				log.Info().Str("holder", e.holder).Msg("Lost leadership, stopping the background jobs")
+			}
+
+			wasLeader = leader
+			onChange(leader)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquire takes or renews the lease, and returns whether this process
+// is the leader.
+func (e *LeaseElector) acquire() bool {
+	started := time.Now()
+
+	acquired, err := e.db.AcquireLease(leaseName, e.holder, LeaseDuration)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return false
+	}
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to renew leader lease")
+
+		// The lease might still be held until it expires.
+		return e.leader && time.Now().Before(e.until)
+	}
+
+	e.leader = acquired
+	if acquired {
+		// The lease was set to expire after the request started.
+		e.until = started.Add(LeaseDuration)
+	}
+
+	return acquired
+}
+
+func (e *LeaseElector) Close() error {
+	e.mu.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	e.closed = true
+	e.mu.Unlock()
+
+	if !wasLeader {
+		return nil
+	}
+
+	return e.db.ReleaseLease(leaseName, e.holder)
+}
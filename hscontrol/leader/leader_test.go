@@ -0,0 +1,72 @@
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/juanfont/headscale/hscontrol/db"
+	"github.com/juanfont/headscale/hscontrol/notifier"
+	"github.com/juanfont/headscale/hscontrol/types"
+)
+
+func TestLeaseElector(t *testing.T) {
+	database, err := db.NewHeadscaleDatabase(
+		db.Sqlite,
+		filepath.Join(t.TempDir(), "headscale.db"),
+		false,
+		notifier.NewNotifier(),
+		nil,
+		nil,
+		nil,
+		types.IPAllocationConfig{},
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	run := func(elector *LeaseElector) bool {
+		changed := false
+		elector.Run(ctx, func(leader bool) {
+			changed = leader
+		})
+
+		return changed
+	}
+
+	blue := NewLeaseElector(database, "blue")
+	green := NewLeaseElector(database, "green")
+
+	if !run(blue) || !blue.IsLeader() {
+		t.Fatal("blue did not become the leader")
+	}
+
+	if run(green) || green.IsLeader() {
+		t.Fatal("green became the leader while blue holds the lease")
+	}
+
+	if err := blue.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if blue.IsLeader() {
+		t.Fatal("blue is still the leader after closing")
+	}
+
+	if !run(green) || !green.IsLeader() {
+		t.Fatal("green did not become the leader after blue stepped down")
+	}
+}
+
+func TestSingle(t *testing.T) {
+	var elector Elector = Single{}
+
+	if !elector.IsLeader() {
+		t.Fatal("a single process must be the leader")
+	}
+}
@@ -0,0 +1,111 @@
+package hscontrol
+
+import (
+	"fmt"
+	"net/netip"
+	"path"
+	"slices"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/juanfont/headscale/hscontrol/db"
+	"github.com/juanfont/headscale/hscontrol/types"
+)
+
+// listedNode is a node with the state computed to filter and list it.
+type listedNode struct {
+	node        *types.Node
+	online      bool
+	validTags   []string
+	invalidTags []string
+}
+
+// hasTag reports if the node has tag, forced or granted by the policy.
+func (listed listedNode) hasTag(tag string) bool {
+	return slices.Contains(listed.node.ForcedTags, tag) ||
+		slices.Contains(listed.validTags, tag)
+}
+
+// nodeFilter selects the nodes listed by ListNodes. The predicates of db
+// are evaluated by the database, the others depend on the policy, the
+// connected nodes or the Hostinfo of the nodes and are evaluated on the
+// nodes loaded from the database.
+type nodeFilter struct {
+	db db.NodeFilter
+
+	tags     []string
+	online   *bool
+	os       string
+	hostname string
+}
+
+func newNodeFilter(request *v1.ListNodesRequest) (*nodeFilter, error) {
+	filter := &nodeFilter{
+		db: db.NodeFilter{
+			User:    request.GetUser(),
+			Expired: request.Expired,
+			OrderBy: request.GetOrderBy(),
+		},
+		tags:     request.GetTags(),
+		online:   request.Online,
+		os:       request.GetOs(),
+		hostname: request.GetHostname(),
+	}
+
+	if request.GetRoute() != "" {
+		prefix, err := netip.ParsePrefix(request.GetRoute())
+		if err != nil {
+			return nil, fmt.Errorf("invalid route: %w", err)
+		}
+
+		filter.db.Route = &prefix
+	}
+
+	if filter.hostname != "" {
+		if _, err := path.Match(filter.hostname, ""); err != nil {
+			return nil, fmt.Errorf("invalid hostname pattern: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// local reports if some predicates are not evaluated by the database,
+// the nodes must then be paginated once filtered.
+func (filter *nodeFilter) local() bool {
+	return len(filter.tags) > 0 ||
+		filter.online != nil ||
+		filter.os != "" ||
+		filter.hostname != ""
+}
+
+func (filter *nodeFilter) match(listed listedNode) bool {
+	node := listed.node
+
+	for _, tag := range filter.tags {
+		if !listed.hasTag(tag) {
+			return false
+		}
+	}
+
+	if filter.online != nil && *filter.online != listed.online {
+		return false
+	}
+
+	if filter.os != "" {
+		if node.Hostinfo == nil || !strings.EqualFold(node.Hostinfo.OS, filter.os) {
+			return false
+		}
+	}
+
+	if filter.hostname != "" {
+		hostname, _ := path.Match(filter.hostname, node.Hostname)
+		givenName, _ := path.Match(filter.hostname, node.GivenName)
+
+		if !hostname && !givenName {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
@@ -21,6 +22,9 @@ import (
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/klauspost/compress/zstd"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/maps"
 	"tailscale.com/envknob"
 	"tailscale.com/smallzstd"
@@ -37,6 +41,8 @@ const (
 
 var debugDumpMapResponsePath = envknob.String("HEADSCALE_DEBUG_DUMP_MAPRESPONSE_PATH")
 
+var tracer = otel.Tracer("github.com/juanfont/headscale/hscontrol/mapper")
+
 // TODO: Optimise
 // As this work continues, the idea is that there will be one Mapper instance
 // per node, attached to the open stream between the control and client.
@@ -56,6 +62,7 @@ type Mapper struct {
 	dnsCfg           *tailcfg.DNSConfig
 	logtail          bool
 	randomClientPort bool
+	expiryWarning    time.Duration
 
 	uid     string
 	created time.Time
@@ -81,6 +88,7 @@ func NewMapper(
 	dnsCfg *tailcfg.DNSConfig,
 	logtail bool,
 	randomClientPort bool,
+	expiryWarning time.Duration,
 ) *Mapper {
 	log.Debug().
 		Caller().
@@ -95,6 +103,7 @@ func NewMapper(
 		dnsCfg:           dnsCfg,
 		logtail:          logtail,
 		randomClientPort: randomClientPort,
+		expiryWarning:    expiryWarning,
 
 		uid:     uid,
 		created: time.Now(),
@@ -106,6 +115,24 @@ func NewMapper(
 	}
 }
 
+// SetConfig replaces the configuration used to build the MapResponses,
+// allowing a reloaded configuration to reach nodes that are already
+// connected.
+func (m *Mapper) SetConfig(
+	derpMap *tailcfg.DERPMap,
+	dnsCfg *tailcfg.DNSConfig,
+	randomClientPort bool,
+	expiryWarning time.Duration,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.derpMap = derpMap
+	m.dnsCfg = dnsCfg
+	m.randomClientPort = randomClientPort
+	m.expiryWarning = expiryWarning
+}
+
 func (m *Mapper) String() string {
 	return fmt.Sprintf("Mapper: { seq: %d, uid: %s, created: %s }", m.seq, m.uid, m.created)
 }
@@ -237,10 +264,14 @@ func (m *Mapper) fullMapResponse(
 
 // FullMapResponse returns a MapResponse for the given node.
 func (m *Mapper) FullMapResponse(
+	ctx context.Context,
 	mapRequest tailcfg.MapRequest,
 	node *types.Node,
 	pol *policy.ACLPolicy,
 ) ([]byte, error) {
+	span := startSpan(ctx, "FullMapResponse", node)
+	defer span.End()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -257,22 +288,37 @@ func (m *Mapper) FullMapResponse(
 			Msgf("node requested full map response, but has pending patches")
 	}
 
+	started := time.Now()
+
 	resp, err := m.fullMapResponse(node, pol, mapRequest.Version)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.marshalMapResponse(mapRequest, resp, node, mapRequest.Compress)
+	data, err := m.marshalMapResponse(mapRequest, resp, node, mapRequest.Compress)
+	if err != nil {
+		return nil, err
+	}
+
+	fullMapResponseDuration.Observe(time.Since(started).Seconds())
+	fullMapResponseSize.Observe(float64(len(data)))
+	span.SetAttributes(attribute.Int("size", len(data)))
+
+	return data, nil
 }
 
 // LiteMapResponse returns a MapResponse for the given node.
 // Lite means that the peers has been omitted, this is intended
 // to be used to answer MapRequests with OmitPeers set to true.
 func (m *Mapper) LiteMapResponse(
+	ctx context.Context,
 	mapRequest tailcfg.MapRequest,
 	node *types.Node,
 	pol *policy.ACLPolicy,
 ) ([]byte, error) {
+	span := startSpan(ctx, "LiteMapResponse", node)
+	defer span.End()
+
 	resp, err := m.baseWithConfigMapResponse(node, pol, mapRequest.Version)
 	if err != nil {
 		return nil, err
@@ -292,10 +338,14 @@ func (m *Mapper) KeepAliveResponse(
 }
 
 func (m *Mapper) DERPMapResponse(
+	ctx context.Context,
 	mapRequest tailcfg.MapRequest,
 	node *types.Node,
 	derpMap *tailcfg.DERPMap,
 ) ([]byte, error) {
+	span := startSpan(ctx, "DERPMapResponse", node)
+	defer span.End()
+
 	m.derpMap = derpMap
 
 	resp := m.baseMapResponse()
@@ -305,12 +355,16 @@ func (m *Mapper) DERPMapResponse(
 }
 
 func (m *Mapper) PeerChangedResponse(
+	ctx context.Context,
 	mapRequest tailcfg.MapRequest,
 	node *types.Node,
 	changed types.Nodes,
 	pol *policy.ACLPolicy,
 	messages ...string,
 ) ([]byte, error) {
+	span := startSpan(ctx, "PeerChangedResponse", node)
+	defer span.End()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -357,20 +411,28 @@ func (m *Mapper) PeerChangedResponse(
 // PeerChangedPatchResponse creates a patch MapResponse with
 // incoming update from a state change.
 func (m *Mapper) PeerChangedPatchResponse(
+	ctx context.Context,
 	mapRequest tailcfg.MapRequest,
 	node *types.Node,
 	changed []*tailcfg.PeerChange,
 	pol *policy.ACLPolicy,
 ) ([]byte, error) {
+	span := startSpan(ctx, "PeerChangedPatchResponse", node)
+	defer span.End()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	sendUpdate := false
+	// Changes of peers waiting to be approved are applied but not sent.
+	send := []*tailcfg.PeerChange{}
 	// patch the internal map
 	for _, change := range changed {
 		if peer, ok := m.peers[uint64(change.NodeID)]; ok {
 			peer.ApplyPeerChange(change)
-			sendUpdate = true
+
+			if !node.PendingApproval && !peer.PendingApproval {
+				send = append(send, change)
+			}
 		} else {
 			log.Trace().Str("node", node.Hostname).Msgf("Node with ID %s is missing from mapper for Node %s, saving patch for when node is available", change.NodeID, node.Hostname)
 
@@ -389,22 +451,26 @@ func (m *Mapper) PeerChangedPatchResponse(
 		}
 	}
 
-	if !sendUpdate {
+	if len(send) == 0 {
 		return nil, nil
 	}
 
 	resp := m.baseMapResponse()
-	resp.PeersChangedPatch = changed
+	resp.PeersChangedPatch = send
 
 	return m.marshalMapResponse(mapRequest, &resp, node, mapRequest.Compress)
 }
 
 // TODO(kradalby): We need some integration tests for this.
 func (m *Mapper) PeerRemovedResponse(
+	ctx context.Context,
 	mapRequest tailcfg.MapRequest,
 	node *types.Node,
 	removed []tailcfg.NodeID,
 ) ([]byte, error) {
+	span := startSpan(ctx, "PeerRemovedResponse", node)
+	defer span.End()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -429,6 +495,15 @@ func (m *Mapper) PeerRemovedResponse(
 	return m.marshalMapResponse(mapRequest, &resp, node, mapRequest.Compress)
 }
 
+// startSpan starts the span of building a map response for node.
+func startSpan(ctx context.Context, name string, node *types.Node) trace.Span {
+	_, span := tracer.Start(ctx, "mapper."+name, trace.WithAttributes(
+		attribute.String("node", node.Hostname),
+	))
+
+	return span
+}
+
 func (m *Mapper) marshalMapResponse(
 	mapRequest tailcfg.MapRequest,
 	resp *tailcfg.MapResponse,
@@ -438,7 +513,17 @@ func (m *Mapper) marshalMapResponse(
 ) ([]byte, error) {
 	atomic.AddUint64(&m.seq, 1)
 
-	jsonBody, err := json.Marshal(resp)
+	var body any = resp
+	if resp.Health != nil {
+		// Health is omitted when empty, but the empty list is what
+		// clears the warnings sent before.
+		body = struct {
+			*tailcfg.MapResponse
+			Health []string `json:"Health"`
+		}{resp, resp.Health}
+	}
+
+	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		log.Error().
 			Caller().
@@ -581,9 +666,33 @@ func (m *Mapper) baseWithConfigMapResponse(
 		DisableLogTail: !m.logtail,
 	}
 
+	resp.Health = expiryHealth(node, m.expiryWarning, time.Now())
+
 	return &resp, nil
 }
 
+// expiryHealth returns the health warnings of node about its key
+// expiring within warning of now. The warnings are cleared, with an empty
+// list, once the key does not expire soon, and left untouched if warning
+// is 0.
+func expiryHealth(node *types.Node, warning time.Duration, now time.Time) []string {
+	if warning == 0 {
+		return nil
+	}
+
+	if node.Expiry == nil || node.Expiry.IsZero() || !node.Expiry.After(now) ||
+		node.Expiry.Sub(now) > warning {
+		return []string{}
+	}
+
+	return []string{
+		fmt.Sprintf(
+			"The key of this node expires on %s, log in again before then to keep it connected.",
+			node.Expiry.UTC().Format(time.RFC1123),
+		),
+	}
+}
+
 func nodeMapToList(nodes map[uint64]*types.Node) types.Nodes {
 	ret := make(types.Nodes, 0)
 
@@ -610,6 +719,16 @@ func appendPeerChanges(
 ) error {
 	fullChange := len(peers) == len(changed)
 
+	// Nodes waiting to be approved are not part of the network, they
+	// do not see any peers and are not seen by them.
+	if node.PendingApproval {
+		peers = types.Nodes{}
+		changed = types.Nodes{}
+	} else {
+		peers = peers.Approved()
+		changed = changed.Approved()
+	}
+
 	rules, sshPolicy, err := policy.GenerateFilterAndSSHRules(
 		pol,
 		node,
@@ -325,6 +325,15 @@ func Test_fullMapResponse(t *testing.T) {
 		CreatedAt:   created,
 	}
 
+	pendingMini := *mini
+	pendingMini.PendingApproval = true
+
+	tailPendingMini := *tailMini
+	tailPendingMini.MachineAuthorized = false
+
+	pendingPeer2 := *peer2
+	pendingPeer2.PendingApproval = true
+
 	tests := []struct {
 		name  string
 		pol   *policy.ACLPolicy
@@ -458,6 +467,69 @@ func Test_fullMapResponse(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "pending-approval-peer-map-response",
+			pol:  &policy.ACLPolicy{},
+			node: mini,
+			peers: types.Nodes{
+				peer1,
+				&pendingPeer2,
+			},
+			baseDomain:       "",
+			dnsConfig:        &tailcfg.DNSConfig{},
+			derpMap:          &tailcfg.DERPMap{},
+			logtail:          false,
+			randomClientPort: false,
+			want: &tailcfg.MapResponse{
+				KeepAlive: false,
+				Node:      tailMini,
+				DERPMap:   &tailcfg.DERPMap{},
+				Peers: []*tailcfg.Node{
+					tailPeer1,
+				},
+				DNSConfig:       &tailcfg.DNSConfig{},
+				Domain:          "",
+				CollectServices: "false",
+				PacketFilter:    []tailcfg.FilterRule{},
+				UserProfiles:    []tailcfg.UserProfile{{LoginName: "mini", DisplayName: "mini"}},
+				SSHPolicy:       &tailcfg.SSHPolicy{Rules: []*tailcfg.SSHRule{}},
+				ControlTime:     &time.Time{},
+				Debug: &tailcfg.Debug{
+					DisableLogTail: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pending-approval-node-map-response",
+			pol:  &policy.ACLPolicy{},
+			node: &pendingMini,
+			peers: types.Nodes{
+				peer1,
+			},
+			baseDomain:       "",
+			dnsConfig:        &tailcfg.DNSConfig{},
+			derpMap:          &tailcfg.DERPMap{},
+			logtail:          false,
+			randomClientPort: false,
+			want: &tailcfg.MapResponse{
+				KeepAlive:       false,
+				Node:            &tailPendingMini,
+				DERPMap:         &tailcfg.DERPMap{},
+				Peers:           []*tailcfg.Node{},
+				DNSConfig:       &tailcfg.DNSConfig{},
+				Domain:          "",
+				CollectServices: "false",
+				PacketFilter:    []tailcfg.FilterRule{},
+				UserProfiles:    []tailcfg.UserProfile{{LoginName: "mini", DisplayName: "mini"}},
+				SSHPolicy:       &tailcfg.SSHPolicy{Rules: []*tailcfg.SSHRule{}},
+				ControlTime:     &time.Time{},
+				Debug: &tailcfg.Debug{
+					DisableLogTail: true,
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -470,6 +542,7 @@ func Test_fullMapResponse(t *testing.T) {
 				tt.dnsConfig,
 				tt.logtail,
 				tt.randomClientPort,
+				0,
 			)
 
 			got, err := mappy.fullMapResponse(
@@ -498,3 +571,58 @@ func Test_fullMapResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestExpiryHealth(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	soon := now.Add(3 * 24 * time.Hour)
+	later := now.Add(30 * 24 * time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		expiry  *time.Time
+		warning time.Duration
+		want    []string
+	}{
+		{
+			name:    "warnings-disabled",
+			expiry:  &soon,
+			warning: 0,
+			want:    nil,
+		},
+		{
+			name:    "no-expiry",
+			warning: 7 * 24 * time.Hour,
+			want:    []string{},
+		},
+		{
+			name:    "expires-later",
+			expiry:  &later,
+			warning: 7 * 24 * time.Hour,
+			want:    []string{},
+		},
+		{
+			name:    "expired",
+			expiry:  &past,
+			warning: 7 * 24 * time.Hour,
+			want:    []string{},
+		},
+		{
+			name:    "expires-soon",
+			expiry:  &soon,
+			warning: 7 * 24 * time.Hour,
+			want: []string{
+				"The key of this node expires on Thu, 04 Jan 2024 12:00:00 UTC, log in again before then to keep it connected.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expiryHealth(&types.Node{Expiry: tt.expiry}, tt.warning, now)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("expiryHealth() unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const prometheusNamespace = "headscale"
+
+var (
+	fullMapResponseDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Name:      "full_map_response_duration_seconds",
+		Help:      "The time taken to build and encode full map responses",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	})
+
+	fullMapResponseSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Name:      "full_map_response_size_bytes",
+		Help:      "The size of the encoded full map responses sent to nodes",
+		Buckets:   prometheus.ExponentialBuckets(1024, 2, 14),
+	})
+)
@@ -9,7 +9,6 @@ import (
 	"github.com/juanfont/headscale/hscontrol/policy"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
-	"github.com/samber/lo"
 	"tailscale.com/tailcfg"
 )
 
@@ -90,8 +89,7 @@ func tailNode(
 		return nil, fmt.Errorf("tailNode, failed to create FQDN: %s", err)
 	}
 
-	tags, _ := pol.TagsOfNode(node)
-	tags = lo.Uniq(append(tags, node.ForcedTags...))
+	tags := pol.EffectiveTags(node)
 
 	tNode := tailcfg.Node{
 		ID: tailcfg.NodeID(node.ID), // this is the actual ID
@@ -121,7 +119,7 @@ func tailNode(
 
 		PrimaryRoutes: primaryPrefixes,
 
-		MachineAuthorized: !node.IsExpired(),
+		MachineAuthorized: !node.IsExpired() && !node.PendingApproval,
 		Expired:           node.IsExpired(),
 	}
 
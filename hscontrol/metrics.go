@@ -1,8 +1,11 @@
 package hscontrol
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
 )
 
 const prometheusNamespace = "headscale"
@@ -22,4 +25,138 @@ var (
 		Help:      "The number of calls/messages issued on a specific nodes update channel",
 	}, []string{"user", "node", "status"})
 	// TODO(kradalby): This is very debugging, we might want to remove it.
+
+	aclPolicyReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Name:      "acl_policy_reloads_total",
+		Help:      "The number of ACL policy reloads, by what triggered them and their outcome",
+	}, []string{"trigger", "status"})
+
+	aclPolicyLastReloadSuccessful = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Name:      "acl_policy_last_reload_successful",
+		Help:      "Whether the last ACL policy reload succeeded (1) or failed and the previous policy was kept (0)",
+	})
+
+	leaderStatus = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Name:      "leader",
+		Help:      "Whether this process is the leader running the background jobs (1) or not (0)",
+	})
 )
+
+var (
+	nodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "", "nodes"),
+		"The number of registered nodes, by user",
+		[]string{"user"}, nil,
+	)
+	onlineNodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "", "online_nodes"),
+		"The number of nodes connected to any headscale process, by user",
+		[]string{"user"}, nil,
+	)
+	enabledRoutesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "", "enabled_routes"),
+		"The number of enabled routes",
+		nil, nil,
+	)
+	primaryRoutesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "", "primary_routes"),
+		"The number of primary routes, served to the peers of their node",
+		nil, nil,
+	)
+	preAuthKeysDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "", "pre_auth_keys"),
+		"The number of pre-auth keys, by state: valid, used or expired",
+		[]string{"state"}, nil,
+	)
+)
+
+// stateCollector reports the nodes, routes and pre-auth keys in the
+// database when the metrics are scraped.
+type stateCollector struct {
+	h *Headscale
+}
+
+func (c stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nodesDesc
+	ch <- onlineNodesDesc
+	ch <- enabledRoutesDesc
+	ch <- primaryRoutesDesc
+	ch <- preAuthKeysDesc
+}
+
+func (c stateCollector) Collect(ch chan<- prometheus.Metric) {
+	users, err := c.h.db.ListUsers()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list users for metrics")
+
+		return
+	}
+
+	nodes, err := c.h.db.ListNodes()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list nodes for metrics")
+
+		return
+	}
+
+	total := make(map[string]int, len(users))
+	online := make(map[string]int, len(users))
+	for _, user := range users {
+		total[user.Name] = 0
+		online[user.Name] = 0
+	}
+
+	var enabledRoutes, primaryRoutes int
+	for _, node := range nodes {
+		total[node.User.Name]++
+		if c.h.nodeNotifier.IsConnected(node.MachineKey) {
+			online[node.User.Name]++
+		}
+
+		for _, route := range node.Routes {
+			if route.Enabled {
+				enabledRoutes++
+			}
+			if route.IsPrimary {
+				primaryRoutes++
+			}
+		}
+	}
+
+	for user, count := range total {
+		ch <- prometheus.MustNewConstMetric(nodesDesc, prometheus.GaugeValue, float64(count), user)
+		ch <- prometheus.MustNewConstMetric(onlineNodesDesc, prometheus.GaugeValue, float64(online[user]), user)
+	}
+
+	ch <- prometheus.MustNewConstMetric(enabledRoutesDesc, prometheus.GaugeValue, float64(enabledRoutes))
+	ch <- prometheus.MustNewConstMetric(primaryRoutesDesc, prometheus.GaugeValue, float64(primaryRoutes))
+
+	keys := map[string]int{"valid": 0, "used": 0, "expired": 0}
+	now := time.Now()
+	for _, user := range users {
+		preAuthKeys, err := c.h.db.ListPreAuthKeys(user.Name)
+		if err != nil {
+			log.Error().Err(err).Str("user", user.Name).Msg("Failed to list pre-auth keys for metrics")
+
+			return
+		}
+
+		for _, key := range preAuthKeys {
+			switch {
+			case key.Expiration != nil && key.Expiration.Before(now):
+				keys["expired"]++
+			case key.Used && !key.Reusable && !key.Ephemeral:
+				keys["used"]++
+			default:
+				keys["valid"]++
+			}
+		}
+	}
+
+	for state, count := range keys {
+		ch <- prometheus.MustNewConstMetric(preAuthKeysDesc, prometheus.GaugeValue, float64(count), state)
+	}
+}
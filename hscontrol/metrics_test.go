@@ -0,0 +1,67 @@
+package hscontrol
+
+import (
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/check.v1"
+	"tailscale.com/types/key"
+)
+
+func (s *Suite) TestStateCollector(c *check.C) {
+	user, err := app.db.CreateUser("metrics")
+	c.Assert(err, check.IsNil)
+
+	_, err = app.db.CreateUser("empty")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.db.CreatePreAuthKey(user.Name, false, false, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	expired := time.Now().Add(-time.Hour)
+	_, err = app.db.CreatePreAuthKey(user.Name, true, false, &expired, nil)
+	c.Assert(err, check.IsNil)
+
+	_, err = app.db.CreatePreAuthKey(user.Name, true, false, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	_, err = app.db.RegisterNode(types.Node{
+		MachineKey:     key.NewMachine().Public(),
+		NodeKey:        key.NewNode().Public(),
+		DiscoKey:       key.NewDisco().Public(),
+		Hostname:       "metrics",
+		UserID:         user.ID,
+		RegisterMethod: util.RegisterMethodAuthKey,
+		AuthKeyID:      uint(pak.ID),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(app.db.UsePreAuthKey(pak), check.IsNil)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(stateCollector{h: app})
+
+	families, err := registry.Gather()
+	c.Assert(err, check.IsNil)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range metric.GetLabel() {
+				name += "/" + label.GetValue()
+			}
+
+			values[name] = metric.GetGauge().GetValue()
+		}
+	}
+
+	c.Assert(values["headscale_nodes/metrics"], check.Equals, float64(1))
+	c.Assert(values["headscale_nodes/empty"], check.Equals, float64(0))
+	c.Assert(values["headscale_online_nodes/metrics"], check.Equals, float64(0))
+	c.Assert(values["headscale_pre_auth_keys/valid"], check.Equals, float64(1))
+	c.Assert(values["headscale_pre_auth_keys/used"], check.Equals, float64(1))
+	c.Assert(values["headscale_pre_auth_keys/expired"], check.Equals, float64(1))
+	c.Assert(values["headscale_enabled_routes"], check.Equals, float64(0))
+}
@@ -7,8 +7,11 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/juanfont/headscale/hscontrol/tracing"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"tailscale.com/control/controlbase"
@@ -72,8 +75,12 @@ func (h *Headscale) NoiseUpgradeHandler(
 		challenge: key.NewChallenge(),
 	}
 
+	// The span only covers the handshake, the connection is kept open
+	// and its requests are traced by the router below.
+	ctx, span := tracer.Start(req.Context(), "NoiseUpgradeHandler")
+
 	noiseConn, err := controlhttp.AcceptHTTP(
-		req.Context(),
+		ctx,
 		writer,
 		req,
 		*h.noisePrivateKey,
@@ -83,6 +90,10 @@ func (h *Headscale) NoiseUpgradeHandler(
 		log.Error().Err(err).Msg("noise upgrade failed")
 		http.Error(writer, err.Error(), http.StatusInternalServerError)
 
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "noise upgrade failed")
+		span.End()
+
 		return
 	}
 
@@ -90,11 +101,18 @@ func (h *Headscale) NoiseUpgradeHandler(
 	noiseServer.machineKey = noiseServer.conn.Peer()
 	noiseServer.protocolVersion = noiseServer.conn.ProtocolVersion()
 
+	span.SetAttributes(
+		attribute.String("machine_key", noiseServer.machineKey.ShortString()),
+		attribute.Int("protocol_version", noiseServer.protocolVersion),
+	)
+	span.End()
+
 	// This router is served only over the Noise connection, and exposes only the new API.
 	//
 	// The HTTP2 server that exposes this router is created for
 	// a single hijacked connection from /ts2021, using netutil.NewOneConnListener
 	router := mux.NewRouter()
+	router.Use(tracing.Middleware("noise"))
 
 	router.HandleFunc("/machine/register", noiseServer.NoiseRegistrationHandler).
 		Methods(http.MethodPost)
@@ -0,0 +1,23 @@
+package notifier
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const prometheusNamespace = "headscale"
+
+var (
+	connectedNodes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: prometheusNamespace,
+		Name:      "connected_nodes",
+		Help:      "The number of nodes with a poll session open on this process",
+	})
+
+	notifierSendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Name:      "notifier_send_duration_seconds",
+		Help:      "The time taken to hand an update to the poll session of a node, by update type",
+		Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5, 10},
+	}, []string{"type"})
+)
@@ -1,42 +1,84 @@
 package notifier
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"tailscale.com/types/key"
 )
 
+// Cluster shares the updates and the connected nodes of a notifier with
+// the other headscale replicas using the same database.
+type Cluster interface {
+	// Publish sends update to the other replicas, for the node with
+	// machineKey if it is not nil, or for every node but the ones with
+	// the machine keys in ignore.
+	Publish(update types.StateUpdate, machineKey *key.MachinePublic, ignore []string)
+
+	// Connected and Disconnected record that a node opened or closed
+	// its poll session on this replica.
+	Connected(machineKey key.MachinePublic)
+	Disconnected(machineKey key.MachinePublic)
+
+	// IsConnected reports if a node has a poll session open on
+	// another replica.
+	IsConnected(machineKey key.MachinePublic) bool
+}
+
+var tracer = otel.Tracer("github.com/juanfont/headscale/hscontrol/notifier")
+
 type Notifier struct {
 	l     sync.RWMutex
 	nodes map[string]chan<- types.StateUpdate
+
+	cluster Cluster
 }
 
 func NewNotifier() *Notifier {
 	return &Notifier{}
 }
 
+// SetCluster makes the notifier forward its updates to, and the updates
+// of, the other replicas of cluster. It must be called before any node
+// is added.
+func (n *Notifier) SetCluster(cluster Cluster) {
+	n.cluster = cluster
+}
+
 func (n *Notifier) AddNode(machineKey key.MachinePublic, c chan<- types.StateUpdate) {
 	log.Trace().Caller().Str("key", machineKey.ShortString()).Msg("acquiring lock to add node")
 	defer log.Trace().Caller().Str("key", machineKey.ShortString()).Msg("releasing lock to add node")
 
 	n.l.Lock()
-	defer n.l.Unlock()
 
 	if n.nodes == nil {
 		n.nodes = make(map[string]chan<- types.StateUpdate)
 	}
 
 	n.nodes[machineKey.String()] = c
+	connectedNodes.Set(float64(len(n.nodes)))
 
 	log.Trace().
 		Str("machine_key", machineKey.ShortString()).
 		Int("open_chans", len(n.nodes)).
 		Msg("Added new channel")
+
+	n.l.Unlock()
+
+	// The cluster is told without holding the lock, it must not stall
+	// the updates of the other nodes.
+	if n.cluster != nil {
+		n.cluster.Connected(machineKey)
+	}
 }
 
 func (n *Notifier) RemoveNode(machineKey key.MachinePublic) {
@@ -44,38 +86,73 @@ func (n *Notifier) RemoveNode(machineKey key.MachinePublic) {
 	defer log.Trace().Caller().Str("key", machineKey.ShortString()).Msg("releasing lock to remove node")
 
 	n.l.Lock()
-	defer n.l.Unlock()
 
 	if n.nodes == nil {
+		n.l.Unlock()
+
 		return
 	}
 
 	delete(n.nodes, machineKey.String())
+	connectedNodes.Set(float64(len(n.nodes)))
 
 	log.Trace().
 		Str("machine_key", machineKey.ShortString()).
 		Int("open_chans", len(n.nodes)).
 		Msg("Removed channel")
+
+	n.l.Unlock()
+
+	if n.cluster != nil {
+		n.cluster.Disconnected(machineKey)
+	}
 }
 
 // IsConnected reports if a node is connected to headscale and has a
-// poll session open.
+// poll session open, on this replica or another one of the cluster.
 func (n *Notifier) IsConnected(machineKey key.MachinePublic) bool {
 	n.l.RLock()
-	defer n.l.RUnlock()
+	_, ok := n.nodes[machineKey.String()]
+	n.l.RUnlock()
 
-	if _, ok := n.nodes[machineKey.String()]; ok {
+	if ok {
 		return true
 	}
 
-	return false
+	return n.cluster != nil && n.cluster.IsConnected(machineKey)
 }
 
-func (n *Notifier) NotifyAll(update types.StateUpdate) {
-	n.NotifyWithIgnore(update)
+func (n *Notifier) NotifyAll(ctx context.Context, update types.StateUpdate) {
+	n.NotifyWithIgnore(ctx, update)
 }
 
-func (n *Notifier) NotifyWithIgnore(update types.StateUpdate, ignore ...string) {
+func (n *Notifier) NotifyWithIgnore(
+	ctx context.Context,
+	update types.StateUpdate,
+	ignore ...string,
+) {
+	n.NotifyLocalWithIgnore(ctx, update, ignore...)
+
+	if n.cluster != nil {
+		n.cluster.Publish(update, nil, ignore)
+	}
+}
+
+// NotifyLocalWithIgnore sends update to the nodes connected to this
+// replica only, it is used for the updates received from the cluster.
+func (n *Notifier) NotifyLocalWithIgnore(
+	ctx context.Context,
+	update types.StateUpdate,
+	ignore ...string,
+) {
+	_, span := tracer.Start(ctx, "notifier.NotifyWithIgnore", trace.WithAttributes(
+		attribute.String("update.type", update.Type.String()),
+		attribute.String("update.message", update.Message),
+	))
+	defer span.End()
+
+	update.SpanContext = span.SpanContext()
+
 	log.Trace().Caller().Interface("type", update.Type).Msg("acquiring lock to notify")
 	defer log.Trace().
 		Caller().
@@ -91,11 +168,36 @@ func (n *Notifier) NotifyWithIgnore(update types.StateUpdate, ignore ...string)
 		}
 
 		log.Trace().Caller().Str("machine", key).Strs("ignoring", ignore).Msg("sending update")
-		c <- update
+		send(c, update)
 	}
 }
 
-func (n *Notifier) NotifyByMachineKey(update types.StateUpdate, mKey key.MachinePublic) {
+func (n *Notifier) NotifyByMachineKey(
+	ctx context.Context,
+	update types.StateUpdate,
+	mKey key.MachinePublic,
+) {
+	if !n.NotifyLocalByMachineKey(ctx, update, mKey) && n.cluster != nil {
+		n.cluster.Publish(update, &mKey, nil)
+	}
+}
+
+// NotifyLocalByMachineKey sends update to the node with mKey if it is
+// connected to this replica, and reports if it is.
+func (n *Notifier) NotifyLocalByMachineKey(
+	ctx context.Context,
+	update types.StateUpdate,
+	mKey key.MachinePublic,
+) bool {
+	_, span := tracer.Start(ctx, "notifier.NotifyByMachineKey", trace.WithAttributes(
+		attribute.String("update.type", update.Type.String()),
+		attribute.String("update.message", update.Message),
+		attribute.String("machine_key", mKey.ShortString()),
+	))
+	defer span.End()
+
+	update.SpanContext = span.SpanContext()
+
 	log.Trace().Caller().Interface("type", update.Type).Msg("acquiring lock to notify")
 	defer log.Trace().
 		Caller().
@@ -105,9 +207,12 @@ func (n *Notifier) NotifyByMachineKey(update types.StateUpdate, mKey key.Machine
 	n.l.RLock()
 	defer n.l.RUnlock()
 
-	if c, ok := n.nodes[mKey.String()]; ok {
-		c <- update
+	c, ok := n.nodes[mKey.String()]
+	if ok {
+		send(c, update)
 	}
+
+	return ok
 }
 
 func (n *Notifier) String() string {
@@ -122,3 +227,11 @@ func (n *Notifier) String() string {
 
 	return strings.Join(str, "")
 }
+
+// send hands update to the poll session of a node, recording how long it
+// waited for the session to take it.
+func send(c chan<- types.StateUpdate, update types.StateUpdate) {
+	started := time.Now()
+	c <- update
+	notifierSendDuration.WithLabelValues(update.Type.String()).Observe(time.Since(started).Seconds())
+}
@@ -37,10 +37,7 @@ var (
 	errOIDCAllowedUsers  = errors.New(
 		"authenticated principal does not match any allowed user",
 	)
-	errOIDCInvalidNodeState = errors.New(
-		"requested node state key expired before authorisation completed",
-	)
-	errOIDCNodeKeyMissing = errors.New("could not get node key from cache")
+	errOIDCNodeKeyMissing = errors.New("could not get pending registration for state")
 )
 
 type IDTokenClaims struct {
@@ -54,7 +51,7 @@ func (h *Headscale) initOIDC() error {
 	var err error
 	// grab oidc config if it hasn't been already
 	if h.oauth2Config == nil {
-		h.oidcProvider, err = oidc.NewProvider(context.Background(), h.cfg.OIDC.Issuer)
+		h.oidcProvider, err = oidc.NewProvider(context.Background(), h.config().OIDC.Issuer)
 
 		if err != nil {
 			log.Error().
@@ -66,14 +63,14 @@ func (h *Headscale) initOIDC() error {
 		}
 
 		h.oauth2Config = &oauth2.Config{
-			ClientID:     h.cfg.OIDC.ClientID,
-			ClientSecret: h.cfg.OIDC.ClientSecret,
+			ClientID:     h.config().OIDC.ClientID,
+			ClientSecret: h.config().OIDC.ClientSecret,
 			Endpoint:     h.oidcProvider.Endpoint(),
 			RedirectURL: fmt.Sprintf(
 				"%s/oidc/callback",
-				strings.TrimSuffix(h.cfg.ServerURL, "/"),
+				strings.TrimSuffix(h.config().ServerURL, "/"),
 			),
-			Scopes: h.cfg.OIDC.Scope,
+			Scopes: h.config().OIDC.Scope,
 		}
 	}
 
@@ -81,11 +78,11 @@ func (h *Headscale) initOIDC() error {
 }
 
 func (h *Headscale) determineTokenExpiration(idTokenExpiration time.Time) time.Time {
-	if h.cfg.OIDC.UseExpiryFromToken {
+	if h.config().OIDC.UseExpiryFromToken {
 		return idTokenExpiration
 	}
 
-	return time.Now().Add(h.cfg.OIDC.Expiry)
+	return time.Now().Add(h.config().OIDC.Expiry)
 }
 
 // RegisterOIDC redirects to the OIDC provider for authentication
@@ -137,17 +134,29 @@ func (h *Headscale) RegisterOIDC(
 
 	stateStr := hex.EncodeToString(randomBlob)[:32]
 
-	// place the node key into the state cache, so it can be retrieved later
-	h.registrationCache.Set(
-		stateStr,
-		machineKey,
-		registerCacheExpiration,
-	)
+	// record the state on the pending registration of the node, so it
+	// can be retrieved later
+	err = h.db.SetPendingRegistrationOIDCState(machineKey, stateStr)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("machine_key", machineKey.ShortString()).
+			Msg("No pending registration for node in OIDC registration")
+
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writer.WriteHeader(http.StatusBadRequest)
+		_, err := writer.Write([]byte("registration has expired"))
+		if err != nil {
+			util.LogErr(err, "Failed to write response")
+		}
+
+		return
+	}
 
 	// Add any extra parameter provided in the configuration to the Authorize Endpoint request
-	extras := make([]oauth2.AuthCodeOption, 0, len(h.cfg.OIDC.ExtraParams))
+	extras := make([]oauth2.AuthCodeOption, 0, len(h.config().OIDC.ExtraParams))
 
-	for k, v := range h.cfg.OIDC.ExtraParams {
+	for k, v := range h.config().OIDC.ExtraParams {
 		extras = append(extras, oauth2.SetAuthURLParam(k, v))
 	}
 
@@ -207,15 +216,15 @@ func (h *Headscale) OIDCCallback(
 		return
 	}
 
-	if err := validateOIDCAllowedDomains(writer, h.cfg.OIDC.AllowedDomains, claims); err != nil {
+	if err := validateOIDCAllowedDomains(writer, h.config().OIDC.AllowedDomains, claims); err != nil {
 		return
 	}
 
-	if err := validateOIDCAllowedGroups(writer, h.cfg.OIDC.AllowedGroups, claims); err != nil {
+	if err := validateOIDCAllowedGroups(writer, h.config().OIDC.AllowedGroups, claims); err != nil {
 		return
 	}
 
-	if err := validateOIDCAllowedUsers(writer, h.cfg.OIDC.AllowedUsers, claims); err != nil {
+	if err := validateOIDCAllowedUsers(writer, h.config().OIDC.AllowedUsers, claims); err != nil {
 		return
 	}
 
@@ -229,7 +238,7 @@ func (h *Headscale) OIDCCallback(
 		return
 	}
 
-	userName, err := getUserName(writer, claims, h.cfg.OIDC.StripEmaildomain)
+	userName, err := getUserName(writer, claims, h.config().OIDC.StripEmaildomain)
 	if err != nil {
 		return
 	}
@@ -242,10 +251,19 @@ func (h *Headscale) OIDCCallback(
 		return
 	}
 
-	if err := h.registerNodeForOIDCCallback(writer, user, machineKey, idTokenExpiry); err != nil {
+	node, err := h.registerNodeForOIDCCallback(writer, user, machineKey, idTokenExpiry)
+	if err != nil {
 		return
 	}
 
+	h.recordAuditEvent(
+		types.AuditActorOIDCPrefix+idToken.Subject,
+		types.AuditNodeRegister,
+		auditTarget("node", node.ID),
+		nil,
+		auditNode(node),
+	)
+
 	content, err := renderOIDCCallbackTemplate(writer, claims)
 	if err != nil {
 		return
@@ -323,7 +341,7 @@ func (h *Headscale) verifyIDTokenForOIDCCallback(
 	writer http.ResponseWriter,
 	rawIDToken string,
 ) (*oidc.IDToken, error) {
-	verifier := h.oidcProvider.Verifier(&oidc.Config{ClientID: h.cfg.OIDC.ClientID})
+	verifier := h.oidcProvider.Verifier(&oidc.Config{ClientID: h.config().OIDC.ClientID})
 	idToken, err := verifier.Verify(ctx, rawIDToken)
 	if err != nil {
 		util.LogErr(err, "failed to verify id token")
@@ -450,10 +468,11 @@ func (h *Headscale) validateNodeForOIDCCallback(
 	claims *IDTokenClaims,
 	expiry time.Time,
 ) (*key.MachinePublic, bool, error) {
-	// retrieve nodekey from state cache
-	machineKeyIf, machineKeyFound := h.registrationCache.Get(state)
-	if !machineKeyFound {
+	// retrieve the pending registration the login was started for
+	reg, err := h.db.GetPendingRegistrationByOIDCState(state)
+	if err != nil {
 		log.Trace().
+			Err(err).
 			Msg("requested node state key expired before authorisation completed")
 		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		writer.WriteHeader(http.StatusBadRequest)
@@ -465,21 +484,7 @@ func (h *Headscale) validateNodeForOIDCCallback(
 		return nil, false, errOIDCNodeKeyMissing
 	}
 
-	var machineKey key.MachinePublic
-	machineKey, machineKeyOK := machineKeyIf.(key.MachinePublic)
-	if !machineKeyOK {
-		log.Trace().
-			Interface("got", machineKeyIf).
-			Msg("requested node state key is not a nodekey")
-		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		writer.WriteHeader(http.StatusBadRequest)
-		_, err := writer.Write([]byte("state is invalid"))
-		if err != nil {
-			util.LogErr(err, "Failed to write response")
-		}
-
-		return nil, false, errOIDCInvalidNodeState
-	}
+	machineKey := reg.MachineKey
 
 	// retrieve node information if it exist
 	// The error is not important, because if it does not
@@ -493,6 +498,8 @@ func (h *Headscale) validateNodeForOIDCCallback(
 			Str("node", node.Hostname).
 			Msg("node already registered, reauthenticating")
 
+		expiry = h.keyExpiry(node, &node.User, expiry)
+
 		err := h.db.NodeSetExpiry(node, expiry)
 		if err != nil {
 			util.LogErr(err, "Failed to refresh node")
@@ -613,15 +620,20 @@ func (h *Headscale) registerNodeForOIDCCallback(
 	user *types.User,
 	machineKey *key.MachinePublic,
 	expiry time.Time,
-) error {
-	if _, err := h.db.RegisterNodeFromAuthCallback(
-		// TODO(kradalby): find a better way to use the cache across modules
-		h.registrationCache,
+) (*types.Node, error) {
+	// Without the pending registration, registering fails below.
+	if reg, err := h.db.GetPendingRegistration(*machineKey); err == nil {
+		expiry = h.keyExpiry(&reg.Node, user, expiry)
+	}
+
+	node, err := h.db.RegisterNodeFromAuthCallback(
 		*machineKey,
 		user.Name,
 		&expiry,
 		util.RegisterMethodOIDC,
-	); err != nil {
+		h.config().NodeApproval.Required,
+	)
+	if err != nil {
 		util.LogErr(err, "could not register node")
 		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		writer.WriteHeader(http.StatusInternalServerError)
@@ -630,10 +642,10 @@ func (h *Headscale) registerNodeForOIDCCallback(
 			util.LogErr(err, "Failed to write response")
 		}
 
-		return err
+		return nil, err
 	}
 
-	return nil
+	return node, nil
 }
 
 func renderOIDCCallbackTemplate(
@@ -0,0 +1,88 @@
+package hscontrol
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+
+	"github.com/juanfont/headscale/hscontrol/db"
+)
+
+// maxPageSize is the largest page returned by the List calls, larger
+// page sizes are lowered to it.
+const maxPageSize = 1000
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// encodePageToken returns the token of the page starting at offset. The
+// items must be listed in the same order for every page.
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodePageToken returns the offset held by pageToken, 0 if it is empty.
+func decodePageToken(pageToken string) (int, error) {
+	if pageToken == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return 0, errInvalidPageToken
+	}
+
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil || offset < 0 {
+		return 0, errInvalidPageToken
+	}
+
+	return offset, nil
+}
+
+// paginate returns the page of items starting at pageToken and the token
+// of the next page, empty on the last page. All the items are returned if
+// pageSize is 0.
+func paginate[T any](items []T, pageSize uint32, pageToken string) ([]T, string, error) {
+	offset, err := decodePageToken(pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	if offset > len(items) {
+		return nil, "", errInvalidPageToken
+	}
+
+	if pageSize == 0 {
+		return items[offset:], "", nil
+	}
+
+	end := offset + int(min(pageSize, maxPageSize))
+	if end >= len(items) {
+		return items[offset:], "", nil
+	}
+
+	return items[offset:end], encodePageToken(end), nil
+}
+
+// dbPage returns the page of rows listed from the database for pageSize
+// and pageToken.
+func dbPage(pageSize uint32, pageToken string) (db.Page, error) {
+	offset, err := decodePageToken(pageToken)
+	if err != nil {
+		return db.Page{}, err
+	}
+
+	return db.Page{
+		Offset: offset,
+		Limit:  int(min(pageSize, maxPageSize)),
+	}, nil
+}
+
+// nextPageToken returns the token of the page following page, empty if
+// page is the last of the total rows.
+func nextPageToken(page db.Page, total int64) string {
+	if page.Limit == 0 || int64(page.Offset+page.Limit) >= total {
+		return ""
+	}
+
+	return encodePageToken(page.Offset + page.Limit)
+}
@@ -25,7 +25,7 @@ func (h *Headscale) WindowsConfigMessage(
 ) {
 	winTemplate := template.Must(template.New("windows").Parse(windowsTemplate))
 	config := map[string]interface{}{
-		"URL": h.cfg.ServerURL,
+		"URL": h.config().ServerURL,
 	}
 
 	var payload bytes.Buffer
@@ -65,7 +65,7 @@ func (h *Headscale) WindowsRegConfig(
 	req *http.Request,
 ) {
 	config := WindowsRegistryConfig{
-		URL: h.cfg.ServerURL,
+		URL: h.config().ServerURL,
 	}
 
 	var content bytes.Buffer
@@ -107,7 +107,7 @@ func (h *Headscale) AppleConfigMessage(
 	appleTemplate := template.Must(template.New("apple").Parse(appleTemplate))
 
 	config := map[string]interface{}{
-		"URL": h.cfg.ServerURL,
+		"URL": h.config().ServerURL,
 	}
 
 	var payload bytes.Buffer
@@ -198,7 +198,7 @@ func (h *Headscale) ApplePlatformConfig(
 
 	platformConfig := AppleMobilePlatformConfig{
 		UUID: contentID,
-		URL:  h.cfg.ServerURL,
+		URL:  h.config().ServerURL,
 	}
 
 	var payload bytes.Buffer
@@ -269,7 +269,7 @@ func (h *Headscale) ApplePlatformConfig(
 
 	config := AppleMobileConfig{
 		UUID:    id,
-		URL:     h.cfg.ServerURL,
+		URL:     h.config().ServerURL,
 		Payload: payload.String(),
 	}
 
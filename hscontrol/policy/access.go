@@ -0,0 +1,223 @@
+package policy
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+
+	"github.com/juanfont/headscale/hscontrol/policy/matcher"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"go4.org/netipx"
+	"tailscale.com/tailcfg"
+)
+
+// AccessResult is the answer to an access query.
+type AccessResult struct {
+	// Allowed reports whether all of the source addresses can reach all
+	// of the destination addresses.
+	Allowed bool
+
+	// ACLs holds the index, in ACLPolicy.ACLs, of every ACL entry that
+	// allows at least part of the requested traffic.
+	ACLs []int
+}
+
+// Reachable describes a destination a source can reach and the ACL entry
+// that allows it.
+type Reachable struct {
+	ACLIndex  int
+	Prefix    netip.Prefix
+	Ports     tailcfg.PortRange
+	Protocols []int
+	Nodes     types.Nodes
+}
+
+// CheckAccess reports whether src can reach dst, a destination in the form
+// alias:port, over the given protocol. Aliases can be node names or
+// anything ExpandAlias understands.
+// An empty protocol is evaluated as TCP. A nil policy allows everything.
+func CheckAccess(
+	pol *ACLPolicy,
+	nodes types.Nodes,
+	src string,
+	dst string,
+	protocol string,
+) (*AccessResult, error) {
+	rules, pol, err := accessRules(pol, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	srcs, err := pol.expandAccessAlias(nodes, src)
+	if err != nil {
+		return nil, err
+	}
+
+	alias, portStr, err := parseDestination(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.ParseUint(portStr, util.Base10, util.BitSize16)
+	if err != nil {
+		return nil, fmt.Errorf("%w: a single destination port is required", ErrInvalidPortFormat)
+	}
+
+	dsts, err := pol.expandAccessAlias(nodes, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	protocols, _, err := parseProtocol(protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(protocols) == 0 {
+		protocols = []int{protocolTCP}
+	}
+
+	result := &AccessResult{
+		Allowed: rulesAllowAll(rules, srcs, dsts, uint16(port), protocols),
+		ACLs:    []int{},
+	}
+
+	// Without a policy there are no ACL entries to report.
+	if len(pol.ACLs) == 0 {
+		return result, nil
+	}
+
+	// generateFilterRules creates exactly one rule per ACL entry, so the
+	// index of a rule is the index of the ACL entry it was created from.
+	for index, rule := range rules {
+		if rulesAllowAny([]tailcfg.FilterRule{rule}, srcs, dsts, uint16(port), protocols) {
+			result.ACLs = append(result.ACLs, index)
+		}
+	}
+
+	return result, nil
+}
+
+// ReachableFrom lists every destination src can reach over the given
+// protocol, or over any protocol if protocol is empty.
+// The addresses of a destination are resolved to the nodes owning them.
+func ReachableFrom(
+	pol *ACLPolicy,
+	nodes types.Nodes,
+	src string,
+	protocol string,
+) ([]Reachable, error) {
+	rules, pol, err := accessRules(pol, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	srcs, err := pol.expandAccessAlias(nodes, src)
+	if err != nil {
+		return nil, err
+	}
+
+	protocols, _, err := parseProtocol(protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := []Reachable{}
+	for index, rule := range rules {
+		if len(protocols) > 0 && !ruleMatchesProtocols(rule, protocols) {
+			continue
+		}
+
+		match := matcher.MatchFromFilterRule(rule)
+		if !match.Srcs.Overlaps(srcs) {
+			continue
+		}
+
+		for _, dest := range rule.DstPorts {
+			destSet, err := util.ParseIPSet(dest.IP, nil)
+			if err != nil {
+				continue
+			}
+
+			for _, prefix := range destSet.Prefixes() {
+				reach := Reachable{
+					ACLIndex:  index,
+					Prefix:    prefix,
+					Ports:     dest.Ports,
+					Protocols: rule.IPProto,
+					Nodes:     types.Nodes{},
+				}
+
+				// Listing every node for a wildcard destination is
+				// only noise.
+				if prefix.Bits() > 0 {
+					for _, node := range nodes {
+						for _, addr := range node.IPAddresses {
+							if prefix.Contains(addr) {
+								reach.Nodes = append(reach.Nodes, node)
+
+								break
+							}
+						}
+					}
+				}
+
+				reachable = append(reachable, reach)
+			}
+		}
+	}
+
+	return reachable, nil
+}
+
+// accessRules returns the filter rules of the policy for the given nodes.
+// A nil policy is replaced by an empty one, paired with the allow-all rules.
+func accessRules(
+	pol *ACLPolicy,
+	nodes types.Nodes,
+) ([]tailcfg.FilterRule, *ACLPolicy, error) {
+	if pol == nil {
+		return tailcfg.FilterAllowAll, &ACLPolicy{}, nil
+	}
+
+	rules, err := pol.generateFilterRulesForNodes(nodes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rules, pol, nil
+}
+
+// expandAccessAlias resolves an alias to a set of addresses. The alias is
+// first matched against the names of the nodes, then expanded as an ACL
+// alias.
+func (pol *ACLPolicy) expandAccessAlias(
+	nodes types.Nodes,
+	alias string,
+) (*netipx.IPSet, error) {
+	build := netipx.IPSetBuilder{}
+	for _, node := range nodes {
+		if node.GivenName == alias || node.Hostname == alias {
+			node.IPAddresses.AppendToIPSet(&build)
+		}
+	}
+
+	ipSet, err := build.IPSet()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ipSet.Prefixes()) == 0 {
+		ipSet, err = pol.ExpandAlias(nodes, alias)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ipSet.Prefixes()) == 0 {
+		return nil, fmt.Errorf("%q does not match any node or address", alias)
+	}
+
+	return ipSet, nil
+}
@@ -0,0 +1,206 @@
+package policy
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"tailscale.com/tailcfg"
+)
+
+func accessTestPolicy() *ACLPolicy {
+	return &ACLPolicy{
+		Groups: Groups{
+			"group:admins": []string{"admin"},
+		},
+		TagOwners: TagOwners{
+			"tag:server": []string{"group:admins"},
+		},
+		ACLs: []ACL{
+			{
+				Action:       "accept",
+				Sources:      []string{"group:admins"},
+				Destinations: []string{"*:*"},
+			},
+			{
+				Action:       "accept",
+				Sources:      []string{"user1"},
+				Destinations: []string{"tag:server:80,443"},
+			},
+			{
+				Action:       "accept",
+				Protocol:     "udp",
+				Sources:      []string{"user1"},
+				Destinations: []string{"tag:server:53"},
+			},
+		},
+	}
+}
+
+func accessTestNodes() types.Nodes {
+	return types.Nodes{
+		&types.Node{
+			ID:          1,
+			Hostname:    "laptop",
+			GivenName:   "laptop",
+			IPAddresses: types.NodeAddresses{netip.MustParseAddr("100.64.0.1")},
+			User:        types.User{Name: "user1"},
+			Hostinfo:    &tailcfg.Hostinfo{},
+		},
+		&types.Node{
+			ID:          2,
+			Hostname:    "workstation",
+			GivenName:   "workstation",
+			IPAddresses: types.NodeAddresses{netip.MustParseAddr("100.64.0.2")},
+			User:        types.User{Name: "admin"},
+			Hostinfo:    &tailcfg.Hostinfo{},
+		},
+		&types.Node{
+			ID:          3,
+			Hostname:    "server",
+			GivenName:   "server",
+			IPAddresses: types.NodeAddresses{netip.MustParseAddr("100.64.0.3")},
+			User:        types.User{Name: "admin"},
+			ForcedTags:  types.StringList{"tag:server"},
+			Hostinfo:    &tailcfg.Hostinfo{},
+		},
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		pol      *ACLPolicy
+		src      string
+		dst      string
+		protocol string
+		want     *AccessResult
+		wantErr  bool
+	}{
+		{
+			name: "node-to-tag-allowed",
+			pol:  accessTestPolicy(),
+			src:  "laptop",
+			dst:  "tag:server:443",
+			want: &AccessResult{Allowed: true, ACLs: []int{1}},
+		},
+		{
+			name: "user-to-node-denied-port",
+			pol:  accessTestPolicy(),
+			src:  "user1",
+			dst:  "server:22",
+			want: &AccessResult{Allowed: false, ACLs: []int{}},
+		},
+		{
+			name:     "udp-only-entry",
+			pol:      accessTestPolicy(),
+			src:      "laptop",
+			dst:      "server:53",
+			protocol: "udp",
+			want:     &AccessResult{Allowed: true, ACLs: []int{2}},
+		},
+		{
+			name: "udp-entry-does-not-allow-tcp",
+			pol:  accessTestPolicy(),
+			src:  "laptop",
+			dst:  "server:53",
+			want: &AccessResult{Allowed: false, ACLs: []int{}},
+		},
+		{
+			name: "ip-destination",
+			pol:  accessTestPolicy(),
+			src:  "workstation",
+			dst:  "100.64.0.1:22",
+			want: &AccessResult{Allowed: true, ACLs: []int{0}},
+		},
+		{
+			name: "no-policy-allows-all",
+			pol:  nil,
+			src:  "laptop",
+			dst:  "workstation:22",
+			want: &AccessResult{Allowed: true, ACLs: []int{}},
+		},
+		{
+			name:    "unknown-source",
+			pol:     accessTestPolicy(),
+			src:     "nonexistent",
+			dst:     "server:22",
+			wantErr: true,
+		},
+		{
+			name:    "port-range-destination",
+			pol:     accessTestPolicy(),
+			src:     "laptop",
+			dst:     "server:80-443",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckAccess(tt.pol, accessTestNodes(), tt.src, tt.dst, tt.protocol)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckAccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("CheckAccess() unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReachableFrom(t *testing.T) {
+	reachable, err := ReachableFrom(accessTestPolicy(), accessTestNodes(), "laptop", "")
+	if err != nil {
+		t.Fatalf("ReachableFrom() error = %v", err)
+	}
+
+	type entry struct {
+		ACLIndex int
+		Prefix   string
+		Ports    tailcfg.PortRange
+		Nodes    []string
+	}
+
+	got := []entry{}
+	for _, reach := range reachable {
+		names := []string{}
+		for _, node := range reach.Nodes {
+			names = append(names, node.GivenName)
+		}
+
+		got = append(got, entry{
+			ACLIndex: reach.ACLIndex,
+			Prefix:   reach.Prefix.String(),
+			Ports:    reach.Ports,
+			Nodes:    names,
+		})
+	}
+
+	want := []entry{
+		{ACLIndex: 1, Prefix: "100.64.0.3/32", Ports: tailcfg.PortRange{First: 80, Last: 80}, Nodes: []string{"server"}},
+		{ACLIndex: 1, Prefix: "100.64.0.3/32", Ports: tailcfg.PortRange{First: 443, Last: 443}, Nodes: []string{"server"}},
+		{ACLIndex: 2, Prefix: "100.64.0.3/32", Ports: tailcfg.PortRange{First: 53, Last: 53}, Nodes: []string{"server"}},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReachableFrom() unexpected result (-want +got):\n%s", diff)
+	}
+
+	tcp, err := ReachableFrom(accessTestPolicy(), accessTestNodes(), "laptop", "tcp")
+	if err != nil {
+		t.Fatalf("ReachableFrom() error = %v", err)
+	}
+
+	for _, reach := range tcp {
+		if reach.ACLIndex == 2 {
+			t.Errorf("ReachableFrom() with tcp returned the udp only ACL: %+v", reach)
+		}
+	}
+
+	if len(tcp) != 2 {
+		t.Errorf("ReachableFrom() with tcp returned %d entries, want 2", len(tcp))
+	}
+}
@@ -8,13 +8,16 @@ import (
 	"net/netip"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/juanfont/headscale/hscontrol/policy/matcher"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/juanfont/headscale/hscontrol/util"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 	"github.com/tailscale/hujson"
 	"go4.org/netipx"
 	"gopkg.in/yaml.v3"
@@ -28,6 +31,7 @@ var (
 	ErrInvalidTag        = errors.New("invalid tag")
 	ErrInvalidPortFormat = errors.New("invalid port format")
 	ErrWildcardIsNeeded  = errors.New("wildcard as port is required for the protocol")
+	ErrACLTestFailed     = errors.New("ACL policy test failed")
 )
 
 const (
@@ -111,6 +115,11 @@ func LoadACLPolicyFromBytes(acl []byte, format string) (*ACLPolicy, error) {
 		return nil, ErrEmptyPolicy
 	}
 
+	err := policy.RunTests()
+	if err != nil {
+		return nil, err
+	}
+
 	return &policy, nil
 }
 
@@ -155,9 +164,16 @@ func GenerateFilterAndSSHRules(
 func (pol *ACLPolicy) generateFilterRules(
 	node *types.Node,
 	peers types.Nodes,
+) ([]tailcfg.FilterRule, error) {
+	return pol.generateFilterRulesForNodes(append(peers, node))
+}
+
+// generateFilterRulesForNodes generates the FilterRules of the policy
+// with aliases expanded against the given set of nodes.
+func (pol *ACLPolicy) generateFilterRulesForNodes(
+	nodes types.Nodes,
 ) ([]tailcfg.FilterRule, error) {
 	rules := []tailcfg.FilterRule{}
-	nodes := append(peers, node)
 
 	for index, acl := range pol.ACLs {
 		if acl.Action != "accept" {
@@ -921,6 +937,18 @@ func (pol *ACLPolicy) TagsOfNode(
 	return validTags, invalidTags
 }
 
+// EffectiveTags returns the tags node has, its forced tags and the
+// requested tags its user is allowed to set.
+func (pol *ACLPolicy) EffectiveTags(node *types.Node) []string {
+	if node.Hostinfo == nil {
+		return slices.Clone(node.ForcedTags)
+	}
+
+	tags, _ := pol.TagsOfNode(node)
+
+	return lo.Uniq(append(tags, node.ForcedTags...))
+}
+
 func filterNodesByUser(nodes types.Nodes, user string) types.Nodes {
 	out := types.Nodes{}
 	for _, node := range nodes {
@@ -952,3 +980,327 @@ func FilterNodesByACL(
 
 	return result
 }
+
+// testNodePrefix is the range synthetic nodes are given addresses from
+// when the tests of a policy are evaluated.
+var testNodePrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// RunTests evaluates the tests section of the policy against the filter
+// rules generated from it. Every user, group member and tag referenced by
+// the tests is given a synthetic node, so the result does not depend on
+// which nodes happen to be registered.
+// An error listing every failing assertion is returned if any test fails.
+func (pol *ACLPolicy) RunTests() error {
+	if len(pol.Tests) == 0 {
+		return nil
+	}
+
+	nodes := pol.testNodes()
+
+	rules, err := pol.generateFilterRulesForNodes(nodes)
+	if err != nil {
+		return fmt.Errorf("%w: failed to generate filter rules: %w", ErrACLTestFailed, err)
+	}
+
+	failures := []string{}
+	for index, test := range pol.Tests {
+		srcs, err := pol.ExpandAlias(nodes, test.Source)
+		if err != nil {
+			failures = append(failures,
+				fmt.Sprintf("test %d: invalid src %q: %s", index, test.Source, err))
+
+			continue
+		}
+
+		if len(srcs.Prefixes()) == 0 {
+			failures = append(failures,
+				fmt.Sprintf("test %d: src %q does not match any address", index, test.Source))
+
+			continue
+		}
+
+		protocols, _, err := parseProtocol(test.Protocol)
+		if err != nil {
+			failures = append(failures,
+				fmt.Sprintf("test %d: invalid proto %q: %s", index, test.Protocol, err))
+
+			continue
+		}
+
+		// Tests without a protocol are evaluated as TCP.
+		if len(protocols) == 0 {
+			protocols = []int{protocolTCP}
+		}
+
+		for _, dest := range test.Accept {
+			dsts, port, err := pol.expandTestDestination(nodes, dest)
+			if err != nil {
+				failures = append(failures,
+					fmt.Sprintf("test %d: invalid accept %q: %s", index, dest, err))
+
+				continue
+			}
+
+			if !rulesAllowAll(rules, srcs, dsts, port, protocols) {
+				failures = append(failures,
+					fmt.Sprintf("test %d: %q cannot access %q", index, test.Source, dest))
+			}
+		}
+
+		for _, dest := range test.Deny {
+			dsts, port, err := pol.expandTestDestination(nodes, dest)
+			if err != nil {
+				failures = append(failures,
+					fmt.Sprintf("test %d: invalid deny %q: %s", index, dest, err))
+
+				continue
+			}
+
+			if rulesAllowAny(rules, srcs, dsts, port, protocols) {
+				failures = append(failures,
+					fmt.Sprintf("test %d: %q can access %q", index, test.Source, dest))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %s", ErrACLTestFailed, strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// testNodes returns a synthetic node for every user, group member and
+// tag referenced by the tests of the policy.
+func (pol *ACLPolicy) testNodes() types.Nodes {
+	aliases := []string{}
+	for _, test := range pol.Tests {
+		aliases = append(aliases, test.Source)
+
+		for _, dest := range test.Accept {
+			if alias, _, err := parseDestination(dest); err == nil {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		for _, dest := range test.Deny {
+			if alias, _, err := parseDestination(dest); err == nil {
+				aliases = append(aliases, alias)
+			}
+		}
+	}
+
+	// Single IPs from the hosts section are skipped so a synthetic node
+	// is never mistaken for a host the policy refers to.
+	reserved := map[netip.Addr]bool{}
+	for _, prefix := range pol.Hosts {
+		if prefix.IsSingleIP() {
+			reserved[prefix.Addr()] = true
+		}
+	}
+
+	nodes := types.Nodes{}
+	seen := map[string]bool{}
+	addr := testNodePrefix.Addr()
+
+	addNode := func(user string, tag string) {
+		name := user
+		if tag != "" {
+			name = tag
+		}
+
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+
+		addr = addr.Next()
+		for reserved[addr] {
+			addr = addr.Next()
+		}
+
+		if !testNodePrefix.Contains(addr) {
+			return
+		}
+
+		node := &types.Node{
+			ID:          uint64(len(nodes) + 1),
+			Hostname:    name,
+			GivenName:   name,
+			IPAddresses: types.NodeAddresses{addr},
+			User:        types.User{Name: user},
+			Hostinfo:    &tailcfg.Hostinfo{},
+		}
+
+		if tag != "" {
+			node.ForcedTags = types.StringList{tag}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	for _, alias := range aliases {
+		switch {
+		case isWildcard(alias):
+			continue
+
+		case isGroup(alias):
+			users, err := pol.expandUsersFromGroup(alias)
+			if err != nil {
+				continue
+			}
+
+			for _, user := range users {
+				addNode(user, "")
+			}
+
+		case isTag(alias):
+			addNode("", alias)
+
+		default:
+			if _, ok := pol.Hosts[alias]; ok {
+				continue
+			}
+
+			if _, err := netip.ParseAddr(alias); err == nil {
+				continue
+			}
+
+			if _, err := netip.ParsePrefix(alias); err == nil {
+				continue
+			}
+
+			addNode(alias, "")
+		}
+	}
+
+	return nodes
+}
+
+// expandTestDestination parses a test destination in the form alias:port
+// and returns the addresses the alias resolves to together with the port.
+func (pol *ACLPolicy) expandTestDestination(
+	nodes types.Nodes,
+	dest string,
+) (*netipx.IPSet, uint16, error) {
+	alias, portStr, err := parseDestination(dest)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	port, err := strconv.ParseUint(portStr, util.Base10, util.BitSize16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: tests require a single port", ErrInvalidPortFormat)
+	}
+
+	dsts, err := pol.ExpandAlias(nodes, alias)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(dsts.Prefixes()) == 0 {
+		return nil, 0, fmt.Errorf("%q does not match any address", alias)
+	}
+
+	return dsts, uint16(port), nil
+}
+
+// rulesAllowAll reports whether every address in srcs can reach every
+// address in dsts on the given port and protocols.
+func rulesAllowAll(
+	rules []tailcfg.FilterRule,
+	srcs, dsts *netipx.IPSet,
+	port uint16,
+	protocols []int,
+) bool {
+	for _, dst := range dsts.Prefixes() {
+		var allowed netipx.IPSetBuilder
+
+		for _, rule := range rules {
+			if !ruleMatchesProtocols(rule, protocols) {
+				continue
+			}
+
+			for _, dest := range rule.DstPorts {
+				if !dest.Ports.Contains(port) {
+					continue
+				}
+
+				destSet, err := util.ParseIPSet(dest.IP, nil)
+				if err != nil || !destSet.ContainsPrefix(dst) {
+					continue
+				}
+
+				allowed.AddSet(matcher.MatchFromFilterRule(rule).Srcs)
+			}
+		}
+
+		allowedSet, err := allowed.IPSet()
+		if err != nil {
+			return false
+		}
+
+		for _, src := range srcs.Prefixes() {
+			if !allowedSet.ContainsPrefix(src) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// rulesAllowAny reports whether any address in srcs can reach any
+// address in dsts on the given port and protocols.
+func rulesAllowAny(
+	rules []tailcfg.FilterRule,
+	srcs, dsts *netipx.IPSet,
+	port uint16,
+	protocols []int,
+) bool {
+	for _, rule := range rules {
+		if !ruleMatchesProtocols(rule, protocols) {
+			continue
+		}
+
+		match := matcher.MatchFromFilterRule(rule)
+		if !match.Srcs.Overlaps(srcs) {
+			continue
+		}
+
+		for _, dest := range rule.DstPorts {
+			if !dest.Ports.Contains(port) {
+				continue
+			}
+
+			destSet, err := util.ParseIPSet(dest.IP, nil)
+			if err != nil {
+				continue
+			}
+
+			if destSet.Overlaps(dsts) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ruleMatchesProtocols reports whether the rule applies to all of the
+// given protocols. A rule without protocols applies to TCP, UDP and ICMP
+// as per tailcfg.FilterRule.
+func ruleMatchesProtocols(rule tailcfg.FilterRule, protocols []int) bool {
+	ruleProtocols := rule.IPProto
+	if len(ruleProtocols) == 0 {
+		ruleProtocols = []int{protocolTCP, protocolUDP, protocolICMP, protocolIPv6ICMP}
+	}
+
+	for _, protocol := range protocols {
+		if !slices.Contains(ruleProtocols, protocol) {
+			return false
+		}
+	}
+
+	return true
+}
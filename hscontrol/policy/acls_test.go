@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"encoding/json"
 	"errors"
 	"net/netip"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/tailscale/hujson"
 	"go4.org/netipx"
 	"gopkg.in/check.v1"
 	"tailscale.com/tailcfg"
@@ -542,9 +544,21 @@ func (s *Suite) TestRuleInvalidGeneration(c *check.C) {
 	],
 }
 	`)
+	// The tests cannot be evaluated as the rules refer to undefined
+	// groups, so the policy is rejected when loaded.
 	pol, err := LoadACLPolicyFromBytes(acl, "hujson")
-	c.Assert(pol.ACLs, check.HasLen, 6)
+	c.Assert(err, check.NotNil)
+	c.Assert(errors.Is(err, ErrACLTestFailed), check.Equals, true)
+	c.Assert(errors.Is(err, ErrInvalidGroup), check.Equals, true)
+	c.Assert(pol, check.IsNil)
+
+	acl, err = hujson.Standardize(acl)
+	c.Assert(err, check.IsNil)
+
+	pol = &ACLPolicy{}
+	err = json.Unmarshal(acl, pol)
 	c.Assert(err, check.IsNil)
+	c.Assert(pol.ACLs, check.HasLen, 6)
 
 	rules, err := pol.generateFilterRules(&types.Node{}, types.Nodes{})
 	c.Assert(err, check.NotNil)
@@ -3209,3 +3223,208 @@ func TestValidTagInvalidUser(t *testing.T) {
 		t.Errorf("TestValidTagInvalidUser() unexpected result (-want +got):\n%s", diff)
 	}
 }
+
+func TestRunTests(t *testing.T) {
+	tests := []struct {
+		name    string
+		acl     string
+		wantErr bool
+	}{
+		{
+			name: "user-group-and-tag-pass",
+			acl: `
+{
+	"groups": {
+		"group:admins": ["admin1", "admin2"],
+	},
+	"tagOwners": {
+		"tag:server": ["group:admins"],
+	},
+	"hosts": {
+		"database": "10.0.0.10",
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src": ["group:admins"],
+			"dst": ["tag:server:22", "database:5432"],
+		},
+		{
+			"action": "accept",
+			"src": ["user1"],
+			"dst": ["tag:server:80,443"],
+		},
+	],
+	"tests": [
+		{
+			"src": "admin1",
+			"accept": ["tag:server:22", "database:5432"],
+			"deny": ["tag:server:80"],
+		},
+		{
+			"src": "user1",
+			"accept": ["tag:server:443"],
+			"deny": ["tag:server:22", "database:5432", "10.0.0.10:5432"],
+		},
+		{
+			"src": "group:admins",
+			"accept": ["10.0.0.10:5432"],
+		},
+	],
+}
+`,
+			wantErr: false,
+		},
+		{
+			name: "accept-fails",
+			acl: `
+{
+	"tagOwners": {
+		"tag:server": ["admin"],
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src": ["admin"],
+			"dst": ["tag:server:22"],
+		},
+	],
+	"tests": [
+		{
+			"src": "user1",
+			"accept": ["tag:server:22"],
+		},
+	],
+}
+`,
+			wantErr: true,
+		},
+		{
+			name: "deny-fails",
+			acl: `
+{
+	"hosts": {
+		"database": "10.0.0.10",
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src": ["*"],
+			"dst": ["*:*"],
+		},
+	],
+	"tests": [
+		{
+			"src": "user1",
+			"deny": ["database:5432"],
+		},
+	],
+}
+`,
+			wantErr: true,
+		},
+		{
+			name: "accept-needs-all-group-members",
+			acl: `
+{
+	"groups": {
+		"group:admins": ["admin1", "admin2"],
+	},
+	"hosts": {
+		"database": "10.0.0.10",
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src": ["admin1"],
+			"dst": ["database:5432"],
+		},
+	],
+	"tests": [
+		{
+			"src": "group:admins",
+			"accept": ["database:5432"],
+		},
+	],
+}
+`,
+			wantErr: true,
+		},
+		{
+			name: "protocol-is-respected",
+			acl: `
+{
+	"hosts": {
+		"dns": "10.0.0.53",
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"proto": "udp",
+			"src": ["user1"],
+			"dst": ["dns:53"],
+		},
+	],
+	"tests": [
+		{
+			"src": "user1",
+			"proto": "udp",
+			"accept": ["dns:53"],
+		},
+		{
+			"src": "user1",
+			"deny": ["dns:53"],
+		},
+	],
+}
+`,
+			wantErr: false,
+		},
+		{
+			name: "port-range-is-invalid",
+			acl: `
+{
+	"hosts": {
+		"database": "10.0.0.10",
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src": ["user1"],
+			"dst": ["database:*"],
+		},
+	],
+	"tests": [
+		{
+			"src": "user1",
+			"accept": ["database:*"],
+		},
+	],
+}
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pol, err := LoadACLPolicyFromBytes([]byte(tt.acl), "hujson")
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrACLTestFailed) {
+					t.Errorf("LoadACLPolicyFromBytes() error = %v, want %v", err, ErrACLTestFailed)
+				}
+
+				if pol != nil {
+					t.Errorf("LoadACLPolicyFromBytes() returned a policy with failing tests")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("LoadACLPolicyFromBytes() unexpected error = %v", err)
+			}
+		})
+	}
+}
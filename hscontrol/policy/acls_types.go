@@ -37,11 +37,14 @@ type Hosts map[string]netip.Prefix
 // TagOwners specify what users (users?) are allow to use certain tags.
 type TagOwners map[string][]string
 
-// ACLTest is not implemented, but should be use to check if a certain rule is allowed.
+// ACLTest asserts that a source can, or cannot, access a set of destinations.
+// The tests are evaluated when the policy is loaded and a policy with a
+// failing test is rejected.
 type ACLTest struct {
-	Source string   `json:"src"            yaml:"src"`
-	Accept []string `json:"accept"         yaml:"accept"`
-	Deny   []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+	Source   string   `json:"src"             yaml:"src"`
+	Protocol string   `json:"proto,omitempty" yaml:"proto,omitempty"`
+	Accept   []string `json:"accept"          yaml:"accept"`
+	Deny     []string `json:"deny,omitempty"  yaml:"deny,omitempty"`
 }
 
 // AutoApprovers specify which users (users?), groups or tags have their advertised routes
@@ -0,0 +1,193 @@
+package hscontrol
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/juanfont/headscale/hscontrol/types"
+	"github.com/juanfont/headscale/hscontrol/util"
+	"github.com/rs/zerolog/log"
+)
+
+// aclPolicyWatchDebounce is how long the watcher waits for the file to
+// settle after a change, editors and ConfigMap updates often write in
+// several steps.
+const aclPolicyWatchDebounce = 500 * time.Millisecond
+
+// policyFileWatcher watches the directory of the ACL policy file.
+type policyFileWatcher struct {
+	watcher *fsnotify.Watcher
+
+	mu      sync.Mutex
+	path    string
+	lastSum [sha256.Size]byte
+}
+
+// setPath moves the watcher to the policy file at path. The content of
+// the file is taken as already loaded.
+func (w *policyFileWatcher) setPath(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if path == w.path {
+		return nil
+	}
+
+	err := w.watcher.Add(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("failed to watch ACL policy directory: %w", err)
+	}
+
+	if w.path != "" && filepath.Dir(w.path) != filepath.Dir(path) {
+		_ = w.watcher.Remove(filepath.Dir(w.path))
+	}
+
+	w.path = path
+	w.lastSum, _ = fileChecksum(path)
+
+	return nil
+}
+
+// changed returns the path of the policy file and whether its content
+// changed since the last call.
+func (w *policyFileWatcher) changed() (string, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sum, err := fileChecksum(w.path)
+	if err != nil {
+		return w.path, false, err
+	}
+
+	if sum == w.lastSum {
+		return w.path, false, nil
+	}
+	w.lastSum = sum
+
+	return w.path, true, nil
+}
+
+// watchACLPolicyFile reloads the ACL policy whenever the content of the
+// policy file changes, until ctx is cancelled.
+// The directory holding the file is watched rather than the file itself,
+// so a file replaced through a rename or a symlink swap, which is how
+// Kubernetes updates mounted ConfigMaps, is still picked up.
+func (h *Headscale) watchACLPolicyFile(ctx context.Context) error {
+	path := util.AbsolutePathFromConfigPath(h.config().ACL.PolicyPath)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create ACL policy watcher: %w", err)
+	}
+
+	w := &policyFileWatcher{watcher: watcher}
+	err = w.setPath(path)
+	if err != nil {
+		watcher.Close()
+
+		return err
+	}
+	h.aclPolicyWatcher = w
+
+	log.Info().
+		Str("path", path).
+		Msg("Watching ACL policy file for changes")
+
+	go func() {
+		defer watcher.Close()
+
+		var settled <-chan time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				settled = time.After(aclPolicyWatchDebounce)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				log.Error().
+					Err(err).
+					Msg("Error while watching ACL policy file")
+
+			case <-settled:
+				settled = nil
+
+				path, changed, err := w.changed()
+				if err != nil {
+					log.Warn().
+						Err(err).
+						Str("path", path).
+						Msg("Failed to read ACL policy file after change")
+
+					continue
+				}
+
+				if !changed {
+					continue
+				}
+
+				// The policy source might have been changed by a
+				// config reload since the watcher was started.
+				acl := h.config().ACL
+				if acl.PolicyMode == types.PolicyModeDB ||
+					util.AbsolutePathFromConfigPath(acl.PolicyPath) != path {
+					continue
+				}
+
+				if err := h.reloadACLPolicy("watcher"); err != nil {
+					continue
+				}
+
+				h.nodeNotifier.NotifyAll(ctx, types.StateUpdate{
+					Type: types.StateFullUpdate,
+				})
+			}
+		}
+	}()
+
+	return nil
+}
+
+// retargetACLPolicyWatcher moves the watcher to the policy file of the
+// current configuration, after the config was reloaded.
+func (h *Headscale) retargetACLPolicyWatcher() {
+	acl := h.config().ACL
+	if h.aclPolicyWatcher == nil ||
+		acl.PolicyMode == types.PolicyModeDB ||
+		acl.PolicyPath == "" {
+		return
+	}
+
+	path := util.AbsolutePathFromConfigPath(acl.PolicyPath)
+	if err := h.aclPolicyWatcher.setPath(path); err != nil {
+		log.Error().
+			Err(err).
+			Str("path", path).
+			Msg("Failed to watch the new ACL policy file")
+	}
+}
+
+func fileChecksum(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(data), nil
+}
@@ -0,0 +1,132 @@
+package hscontrol
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juanfont/headscale/hscontrol/policy"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/check.v1"
+)
+
+func waitForACLPolicy(c *check.C, cond func(pol *policy.ACLPolicy) bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond(app.ACLPolicy()) {
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	c.Fatalf("ACL policy did not reach the expected state: %+v", app.ACLPolicy())
+}
+
+// waitForLastReload waits until the last ACL policy reload succeeded, or
+// failed, as reported by the reload metrics.
+func waitForLastReload(c *check.C, successful bool) {
+	want := 0.0
+	if successful {
+		want = 1
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		families, err := prometheus.DefaultGatherer.Gather()
+		c.Assert(err, check.IsNil)
+
+		for _, family := range families {
+			if family.GetName() == "headscale_acl_policy_last_reload_successful" &&
+				family.GetMetric()[0].GetGauge().GetValue() == want {
+				return
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	c.Fatalf("last ACL policy reload did not report successful=%t", successful)
+}
+
+func (s *Suite) TestWatchACLPolicyFile(c *check.C) {
+	path := filepath.Join(tmpDir, "acl.hujson")
+	err := os.WriteFile(path, []byte(`{"acls": []}`), 0o600)
+	c.Assert(err, check.IsNil)
+
+	app.config().ACL.PolicyPath = path
+	app.setACLPolicy(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err = app.watchACLPolicyFile(ctx)
+	c.Assert(err, check.IsNil)
+
+	err = os.WriteFile(
+		path,
+		[]byte(`{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:22"]}]}`),
+		0o600,
+	)
+	c.Assert(err, check.IsNil)
+
+	waitForACLPolicy(c, func(pol *policy.ACLPolicy) bool {
+		return pol != nil && len(pol.ACLs) == 1
+	})
+	waitForLastReload(c, true)
+
+	// A broken policy is ignored and the previous one is kept.
+	err = os.WriteFile(path, []byte(`{"acls": [`), 0o600)
+	c.Assert(err, check.IsNil)
+
+	waitForLastReload(c, false)
+	c.Assert(app.ACLPolicy(), check.NotNil)
+	c.Assert(app.ACLPolicy().ACLs, check.HasLen, 1)
+
+	// Replacing the file, as done for ConfigMaps, is picked up.
+	replacement := filepath.Join(tmpDir, "acl.hujson.new")
+	err = os.WriteFile(
+		replacement,
+		[]byte(`{"acls": [
+			{"action": "accept", "src": ["*"], "dst": ["*:22"]},
+			{"action": "accept", "src": ["*"], "dst": ["*:80"]},
+		]}`),
+		0o600,
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(os.Rename(replacement, path), check.IsNil)
+
+	waitForACLPolicy(c, func(pol *policy.ACLPolicy) bool {
+		return pol != nil && len(pol.ACLs) == 2
+	})
+
+	// A config reload moving the policy file moves the watcher with it.
+	moved := filepath.Join(tmpDir, "moved", "acl.hujson")
+	c.Assert(os.Mkdir(filepath.Dir(moved), 0o700), check.IsNil)
+	err = os.WriteFile(
+		moved,
+		[]byte(`{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}]}`),
+		0o600,
+	)
+	c.Assert(err, check.IsNil)
+
+	// Replaced rather than changed in place as the watcher reads it.
+	cfg := *app.config()
+	cfg.ACL.PolicyPath = moved
+	app.cfg.Store(&cfg)
+	app.retargetACLPolicyWatcher()
+	c.Assert(app.reloadACLPolicy("signal"), check.IsNil)
+	c.Assert(app.ACLPolicy().ACLs[0].Destinations, check.DeepEquals, []string{"*:*"})
+
+	err = os.WriteFile(
+		moved,
+		[]byte(`{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:443"]}]}`),
+		0o600,
+	)
+	c.Assert(err, check.IsNil)
+
+	waitForACLPolicy(c, func(pol *policy.ACLPolicy) bool {
+		return pol != nil && pol.ACLs[0].Destinations[0] == "*:443"
+	})
+}
@@ -6,9 +6,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/juanfont/headscale/hscontrol/events"
 	"github.com/juanfont/headscale/hscontrol/mapper"
 	"github.com/juanfont/headscale/hscontrol/types"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	xslices "golang.org/x/exp/slices"
 	"tailscale.com/tailcfg"
 )
@@ -62,6 +65,21 @@ func (h *Headscale) handlePoll(
 ) {
 	logInfo, logErr := logPollFunc(mapRequest, node)
 
+	ctx, span := tracer.Start(ctx, "handlePoll", trace.WithAttributes(
+		attribute.String("node", node.Hostname),
+		attribute.String("machine_key", node.MachineKey.ShortString()),
+		attribute.Bool("stream", mapRequest.Stream),
+		attribute.Bool("omit_peers", mapRequest.OmitPeers),
+		attribute.Bool("read_only", mapRequest.ReadOnly),
+	))
+	defer span.End()
+
+	// Saved with the node below, the read only requests are not
+	// recorded.
+	lastMapRequest := time.Now()
+	node.LastMapRequest = &lastMapRequest
+	node.CapabilityVersion = mapRequest.Version
+
 	// This is the mechanism where the node gives us information about its
 	// current configuration.
 	//
@@ -118,7 +136,7 @@ func (h *Headscale) handlePoll(
 			// which is more costly.
 			if !xslices.Equal(oldRoutes, newRoutes) {
 				var err error
-				sendUpdate, err = h.db.SaveNodeRoutes(node)
+				sendUpdate, err = h.db.WithContext(ctx).SaveNodeRoutes(node)
 				if err != nil {
 					logErr(err, "Error processing node routes")
 					http.Error(writer, "", http.StatusInternalServerError)
@@ -138,7 +156,7 @@ func (h *Headscale) handlePoll(
 			}
 
 			if sendUpdate {
-				if err := h.db.NodeSave(node); err != nil {
+				if err := h.db.WithContext(ctx).NodeSave(node); err != nil {
 					logErr(err, "Failed to persist/update node in the database")
 					http.Error(writer, "", http.StatusInternalServerError)
 
@@ -152,6 +170,7 @@ func (h *Headscale) handlePoll(
 				}
 				if stateUpdate.Valid() {
 					h.nodeNotifier.NotifyWithIgnore(
+						ctx,
 						stateUpdate,
 						node.MachineKey.String())
 				}
@@ -160,7 +179,7 @@ func (h *Headscale) handlePoll(
 			}
 		}
 
-		if err := h.db.NodeSave(node); err != nil {
+		if err := h.db.WithContext(ctx).NodeSave(node); err != nil {
 			logErr(err, "Failed to persist/update node in the database")
 			http.Error(writer, "", http.StatusInternalServerError)
 
@@ -173,6 +192,7 @@ func (h *Headscale) handlePoll(
 		}
 		if stateUpdate.Valid() {
 			h.nodeNotifier.NotifyWithIgnore(
+				ctx,
 				stateUpdate,
 				node.MachineKey.String())
 		}
@@ -192,7 +212,7 @@ func (h *Headscale) handlePoll(
 		// The intended use is for clients to discover the DERP map at
 		// start-up before their first real endpoint update.
 	} else if mapRequest.OmitPeers && !mapRequest.Stream && mapRequest.ReadOnly {
-		h.handleLiteRequest(writer, node, mapRequest)
+		h.handleLiteRequest(ctx, writer, node, mapRequest)
 
 		return
 	} else if mapRequest.OmitPeers && mapRequest.Stream {
@@ -218,7 +238,7 @@ func (h *Headscale) handlePoll(
 		node.Hostinfo = mapRequest.Hostinfo
 
 		if !xslices.Equal(oldRoutes, newRoutes) {
-			_, err := h.db.SaveNodeRoutes(node)
+			_, err := h.db.WithContext(ctx).SaveNodeRoutes(node)
 			if err != nil {
 				logErr(err, "Error processing node routes")
 				http.Error(writer, "", http.StatusInternalServerError)
@@ -228,7 +248,7 @@ func (h *Headscale) handlePoll(
 		}
 	}
 
-	if err := h.db.NodeSave(node); err != nil {
+	if err := h.db.WithContext(ctx).NodeSave(node); err != nil {
 		logErr(err, "Failed to persist/update node in the database")
 		http.Error(writer, "", http.StatusInternalServerError)
 
@@ -239,7 +259,7 @@ func (h *Headscale) handlePoll(
 	// that given point, further updates are kept in memory in
 	// the Mapper, which lives for the duration of the polling
 	// session.
-	peers, err := h.db.ListPeers(node)
+	peers, err := h.db.WithContext(ctx).ListPeers(node)
 	if err != nil {
 		logErr(err, "Failed to list peers when opening poller")
 		http.Error(writer, "", http.StatusInternalServerError)
@@ -252,20 +272,22 @@ func (h *Headscale) handlePoll(
 		peer.IsOnline = &online
 	}
 
+	cfg := h.config()
 	mapp := mapper.NewMapper(
 		node,
 		peers,
 		h.DERPMap,
-		h.cfg.BaseDomain,
-		h.cfg.DNSConfig,
-		h.cfg.LogTail.Enabled,
-		h.cfg.RandomizeClientPort,
+		cfg.BaseDomain,
+		cfg.DNSConfig,
+		cfg.LogTail.Enabled,
+		cfg.RandomizeClientPort,
+		cfg.NodeExpiry.Notify.Before,
 	)
 
 	// update ACLRules with peer informations (to update server tags if necessary)
-	if h.ACLPolicy != nil {
+	if pol := h.ACLPolicy(); pol != nil {
 		// update routes with peer information
-		err = h.db.EnableAutoApprovedRoutes(h.ACLPolicy, node)
+		err = h.db.WithContext(ctx).EnableAutoApprovedRoutes(pol, node)
 		if err != nil {
 			logErr(err, "Error running auto approved routes")
 		}
@@ -273,7 +295,7 @@ func (h *Headscale) handlePoll(
 
 	logInfo("Sending initial map")
 
-	mapResp, err := mapp.FullMapResponse(mapRequest, node, h.ACLPolicy)
+	mapResp, err := mapp.FullMapResponse(ctx, mapRequest, node, h.ACLPolicy())
 	if err != nil {
 		logErr(err, "Failed to create MapResponse")
 		http.Error(writer, "", http.StatusInternalServerError)
@@ -302,10 +324,18 @@ func (h *Headscale) handlePoll(
 	}
 	if stateUpdate.Valid() {
 		h.nodeNotifier.NotifyWithIgnore(
+			ctx,
 			stateUpdate,
 			node.MachineKey.String())
 	}
 
+	// The span of the poll ends once the stream is set up, the long poll
+	// would otherwise get a child span for every update and keep alive of
+	// the session. The updates are traced on their own, linked to it.
+	pollLink := trace.LinkFromContext(ctx)
+	span.End()
+	ctx = trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+
 	// Set up the client stream
 	h.pollNetMapStreamWG.Add(1)
 	defer h.pollNetMapStreamWG.Done()
@@ -321,6 +351,13 @@ func (h *Headscale) handlePoll(
 	h.nodeNotifier.AddNode(node.MachineKey, updateChan)
 	defer h.nodeNotifier.RemoveNode(node.MachineKey)
 
+	// The online status is refreshed on every keep alive, so the
+	// event is published once here when the stream is established.
+	h.events.Publish(events.Event{
+		Type: events.NodeOnline,
+		Node: node,
+	})
+
 	keepAliveTicker := time.NewTicker(keepAliveInterval)
 
 	ctx = context.WithValue(ctx, nodeNameContextKey, node.Hostname)
@@ -329,7 +366,7 @@ func (h *Headscale) handlePoll(
 	defer cancel()
 
 	if len(node.Routes) > 0 {
-		go h.db.EnsureFailoverRouteIsAvailable(node)
+		go h.db.WithContext(context.WithoutCancel(ctx)).EnsureFailoverRouteIsAvailable(node)
 	}
 
 	for {
@@ -361,12 +398,24 @@ func (h *Headscale) handlePoll(
 			// One alternative is to split these different channels into
 			// goroutines, but then you might have a problem without a lock
 			// if a keepalive is written at the same time as an update.
-			go h.updateNodeOnlineStatus(true, node)
+			go h.updateNodeOnlineStatus(context.WithoutCancel(ctx), true, node)
 
 		case update := <-updateChan:
 			logInfo("Received update")
 			now := time.Now()
 
+			// The update is traced as part of the trace of the change
+			// that caused it, linked to the poll of the node.
+			updateCtx, updateSpan := tracer.Start(
+				trace.ContextWithSpanContext(ctx, update.SpanContext),
+				"poll.update",
+				trace.WithLinks(pollLink),
+				trace.WithAttributes(
+					attribute.String("node", node.Hostname),
+					attribute.String("type", update.Type.String()),
+				),
+			)
+
 			var data []byte
 			var err error
 
@@ -374,7 +423,17 @@ func (h *Headscale) handlePoll(
 			case types.StateFullUpdate:
 				logInfo("Sending Full MapResponse")
 
-				data, err = mapp.FullMapResponse(mapRequest, node, h.ACLPolicy)
+				// The configuration might have been reloaded since the
+				// mapper was created.
+				current := h.config()
+				mapp.SetConfig(
+					h.DERPMap,
+					current.DNSConfig,
+					current.RandomizeClientPort,
+					current.NodeExpiry.Notify.Before,
+				)
+
+				data, err = mapp.FullMapResponse(updateCtx, mapRequest, node, h.ACLPolicy())
 			case types.StatePeerChanged:
 				logInfo(fmt.Sprintf("Sending Changed MapResponse: %s", update.Message))
 
@@ -390,28 +449,30 @@ func (h *Headscale) handlePoll(
 					}
 				}
 
-				data, err = mapp.PeerChangedResponse(mapRequest, node, update.ChangeNodes, h.ACLPolicy, update.Message)
+				data, err = mapp.PeerChangedResponse(updateCtx, mapRequest, node, update.ChangeNodes, h.ACLPolicy(), update.Message)
 			case types.StatePeerChangedPatch:
 				logInfo("Sending PeerChangedPatch MapResponse")
-				data, err = mapp.PeerChangedPatchResponse(mapRequest, node, update.ChangePatches, h.ACLPolicy)
+				data, err = mapp.PeerChangedPatchResponse(updateCtx, mapRequest, node, update.ChangePatches, h.ACLPolicy())
 			case types.StatePeerRemoved:
 				logInfo("Sending PeerRemoved MapResponse")
-				data, err = mapp.PeerRemovedResponse(mapRequest, node, update.Removed)
+				data, err = mapp.PeerRemovedResponse(updateCtx, mapRequest, node, update.Removed)
 			case types.StateSelfUpdate:
 				if len(update.ChangeNodes) == 1 {
 					logInfo("Sending SelfUpdate MapResponse")
 					node = update.ChangeNodes[0]
-					data, err = mapp.LiteMapResponse(mapRequest, node, h.ACLPolicy)
+					data, err = mapp.LiteMapResponse(updateCtx, mapRequest, node, h.ACLPolicy())
 				} else {
 					logInfo("SelfUpdate contained too many nodes, this is likely a bug in the code, please report.")
 				}
 			case types.StateDERPUpdated:
 				logInfo("Sending DERPUpdate MapResponse")
-				data, err = mapp.DERPMapResponse(mapRequest, node, update.DERPMap)
+				data, err = mapp.DERPMapResponse(updateCtx, mapRequest, node, update.DERPMap)
 			}
 
 			if err != nil {
 				logErr(err, "Could not get the create map update")
+				updateSpan.RecordError(err)
+				updateSpan.End()
 
 				return
 			}
@@ -421,6 +482,8 @@ func (h *Headscale) handlePoll(
 				_, err = writer.Write(data)
 				if err != nil {
 					logErr(err, "Could not write the map response")
+					updateSpan.RecordError(err)
+					updateSpan.End()
 
 					updateRequestsSentToNode.WithLabelValues(node.User.Name, node.Hostname, "failed").
 						Inc()
@@ -432,6 +495,7 @@ func (h *Headscale) handlePoll(
 					flusher.Flush()
 				} else {
 					log.Error().Msg("Failed to create http flusher")
+					updateSpan.End()
 
 					return
 				}
@@ -448,13 +512,17 @@ func (h *Headscale) handlePoll(
 					Msg("update sent")
 			}
 
+			updateSpan.End()
+
 		case <-ctx.Done():
 			logInfo("The client has closed the connection")
 
-			go h.updateNodeOnlineStatus(false, node)
+			// The request context is cancelled, the node is
+			// marked offline outside of it.
+			go h.updateNodeOnlineStatus(context.WithoutCancel(ctx), false, node)
 
 			// Failover the node's routes if any.
-			go h.db.FailoverNodeRoutesWithNotify(node)
+			go h.db.WithContext(context.WithoutCancel(ctx)).FailoverNodeRoutesWithNotify(node)
 
 			// The connection has been closed, so we can stop polling.
 			return
@@ -470,7 +538,7 @@ func (h *Headscale) handlePoll(
 // updateNodeOnlineStatus records the last seen status of a node and notifies peers
 // about change in their online/offline status.
 // It takes a StateUpdateType of either StatePeerOnlineChanged or StatePeerOfflineChanged.
-func (h *Headscale) updateNodeOnlineStatus(online bool, node *types.Node) {
+func (h *Headscale) updateNodeOnlineStatus(ctx context.Context, online bool, node *types.Node) {
 	now := time.Now()
 
 	node.LastSeen = &now
@@ -486,10 +554,17 @@ func (h *Headscale) updateNodeOnlineStatus(online bool, node *types.Node) {
 		},
 	}
 	if statusUpdate.Valid() {
-		h.nodeNotifier.NotifyWithIgnore(statusUpdate, node.MachineKey.String())
+		h.nodeNotifier.NotifyWithIgnore(ctx, statusUpdate, node.MachineKey.String())
+	}
+
+	if !online {
+		h.events.Publish(events.Event{
+			Type: events.NodeOffline,
+			Node: node,
+		})
 	}
 
-	err := h.db.UpdateLastSeen(node)
+	err := h.db.WithContext(ctx).UpdateLastSeen(node)
 	if err != nil {
 		log.Error().Err(err).Msg("Cannot update node LastSeen")
 
@@ -508,25 +583,28 @@ func closeChanWithLog[C chan []byte | chan struct{} | chan types.StateUpdate](ch
 }
 
 func (h *Headscale) handleLiteRequest(
+	ctx context.Context,
 	writer http.ResponseWriter,
 	node *types.Node,
 	mapRequest tailcfg.MapRequest,
 ) {
 	logInfo, logErr := logPollFunc(mapRequest, node)
 
+	cfg := h.config()
 	mapp := mapper.NewMapper(
 		node,
 		types.Nodes{},
 		h.DERPMap,
-		h.cfg.BaseDomain,
-		h.cfg.DNSConfig,
-		h.cfg.LogTail.Enabled,
-		h.cfg.RandomizeClientPort,
+		cfg.BaseDomain,
+		cfg.DNSConfig,
+		cfg.LogTail.Enabled,
+		cfg.RandomizeClientPort,
+		cfg.NodeExpiry.Notify.Before,
 	)
 
 	logInfo("Client asked for a lite update, responding without peers")
 
-	mapResp, err := mapp.LiteMapResponse(mapRequest, node, h.ACLPolicy)
+	mapResp, err := mapp.LiteMapResponse(ctx, mapRequest, node, h.ACLPolicy())
 	if err != nil {
 		logErr(err, "Failed to create MapResponse")
 		http.Error(writer, "", http.StatusInternalServerError)
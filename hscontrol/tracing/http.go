@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Middleware traces the requests served by a router, naming the spans
+// after the route template. Requests with a path starting with one of
+// skip are not traced, it is meant for long lived connections that
+// trace their own work.
+func Middleware(operation string, skip ...string) mux.MiddlewareFunc {
+	return otelhttp.NewMiddleware(
+		operation,
+		otelhttp.WithFilter(func(req *http.Request) bool {
+			for _, prefix := range skip {
+				if strings.HasPrefix(req.URL.Path, prefix) {
+					return false
+				}
+			}
+
+			return true
+		}),
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			if route := mux.CurrentRoute(req); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil {
+					return req.Method + " " + template
+				}
+			}
+
+			return req.Method
+		}),
+	)
+}
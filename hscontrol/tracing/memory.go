@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// memorySpans is the number of spans kept by the memory exporter.
+const memorySpans = 10000
+
+// MemoryExporter keeps the latest spans in memory, so traces can be read
+// without a collector.
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []tracetest.SpanStub
+	size  int
+}
+
+var _ sdktrace.SpanExporter = (*MemoryExporter)(nil)
+
+func NewMemoryExporter(size int) *MemoryExporter {
+	return &MemoryExporter{size: size}
+}
+
+// ExportSpans implements sdktrace.SpanExporter, dropping the oldest
+// spans once size spans are kept.
+func (e *MemoryExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, tracetest.SpanStubsFromReadOnlySpans(spans)...)
+	if overflow := len(e.spans) - e.size; overflow > 0 {
+		e.spans = append(e.spans[:0:0], e.spans[overflow:]...)
+	}
+
+	return nil
+}
+
+func (e *MemoryExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// Spans returns the spans kept, oldest first.
+func (e *MemoryExporter) Spans() []tracetest.SpanStub {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]tracetest.SpanStub(nil), e.spans...)
+}
+
+// ServeHTTP writes the spans kept, in the JSON format of the file
+// exporter. The spans of a single trace are written if the trace_id
+// query parameter is set.
+func (e *MemoryExporter) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	traceID := req.URL.Query().Get("trace_id")
+
+	spans := e.Spans()
+	if traceID != "" {
+		filtered := spans[:0]
+		for _, span := range spans {
+			if span.SpanContext.TraceID().String() == traceID {
+				filtered = append(filtered, span)
+			}
+		}
+		spans = filtered
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(spans); err != nil {
+		log.Error().Err(err).Msg("Failed to write traces")
+	}
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestMemoryExporter(t *testing.T) {
+	exporter := NewMemoryExporter(3)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	tracer := provider.Tracer("test")
+
+	ctx, first := tracer.Start(context.Background(), "first")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	first.End()
+
+	for _, name := range []string{"second", "third"} {
+		_, span := tracer.Start(context.Background(), name)
+		span.End()
+	}
+
+	// The oldest span is dropped once the exporter is full.
+	spans := exporter.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("Spans() returned %d spans, want 3", len(spans))
+	}
+	if spans[0].Name != "first" {
+		t.Errorf("oldest span is %q, want %q", spans[0].Name, "first")
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name: "all",
+			want: []string{"first", "second", "third"},
+		},
+		{
+			name:  "trace",
+			query: "?trace_id=" + first.SpanContext().TraceID().String(),
+			want:  []string{"first"},
+		},
+		{
+			name:  "unknown trace",
+			query: "?trace_id=00000000000000000000000000000001",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/traces"+tt.query, nil))
+
+			var got []struct{ Name string }
+			if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d spans, want %d", len(got), len(tt.want))
+			}
+			for i, span := range got {
+				if span.Name != tt.want[i] {
+					t.Errorf("span %d is %q, want %q", i, span.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
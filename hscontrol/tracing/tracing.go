@@ -0,0 +1,122 @@
+// Package tracing sets up the OpenTelemetry traces of headscale. The
+// other packages create their spans with the global tracer provider,
+// which records nothing until Setup installs one.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/juanfont/headscale/hscontrol/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+const serviceName = "headscale"
+
+// Tracing is the tracer provider installed by Setup.
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+
+	// Memory holds the latest spans when the memory exporter is used.
+	Memory *MemoryExporter
+
+	file *os.File
+}
+
+// Setup installs the tracer provider and the propagator of the trace
+// context described by cfg. It returns nil if tracing is disabled.
+func Setup(cfg types.TracingConfig) (*Tracing, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	tracing := &Tracing{}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch cfg.Exporter {
+	case types.TracingExporterOTLP:
+		options := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		}
+		if cfg.Insecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+
+		// The exporter connects in the background, and retries
+		// sending the spans while the collector is unreachable.
+		exporter, err = otlptracegrpc.New(context.Background(), options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+
+	case types.TracingExporterFile:
+		tracing.file, err = os.OpenFile(cfg.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open traces file: %w", err)
+		}
+
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(tracing.file))
+		if err != nil {
+			tracing.file.Close()
+
+			return nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+
+	case types.TracingExporterMemory:
+		tracing.Memory = NewMemoryExporter(memorySpans)
+		exporter = tracing.Memory
+
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tracing.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(
+			sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio)),
+		),
+	)
+
+	otel.SetTracerProvider(tracing.provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tracing, nil
+}
+
+// Shutdown sends the spans not exported yet and stops tracing.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	err := t.provider.Shutdown(ctx)
+
+	if t.file != nil {
+		err = errors.Join(err, t.file.Close())
+	}
+
+	return err
+}
@@ -1,12 +1,70 @@
 package types
 
 import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
 	"time"
 
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	// APIKeyRoleAdmin grants access to every API call, like a key
+	// without any scope.
+	APIKeyRoleAdmin            = "admin"
+	APIKeyRoleReadOnly         = "read-only"
+	APIKeyRolePreAuthKeyIssuer = "preauthkey-issuer"
+	APIKeyRoleRouteApprover    = "route-approver"
+)
+
+var ErrAPIKeyInvalidScope = errors.New("invalid API key scope")
+
+// APIKeyRoles maps the roles an API key can be scoped to, to the API
+// calls they allow.
+var APIKeyRoles = map[string][]string{
+	APIKeyRoleReadOnly: {
+		"GetUser",
+		"ListUsers",
+		"ListPreAuthKeys",
+		"GetNode",
+		"ListNodes",
+		"ListPendingRegistrations",
+		"ListNodesOutsideIPPools",
+		"ListIPReservations",
+		"GetRoutes",
+		"GetNodeRoutes",
+		"ListApiKeys",
+		"GetPolicy",
+		"CheckAccess",
+		"ListAuditEvents",
+		"WatchEvents",
+	},
+	APIKeyRolePreAuthKeyIssuer: {
+		"CreatePreAuthKey",
+		"ExpirePreAuthKey",
+		"ListPreAuthKeys",
+	},
+	APIKeyRoleRouteApprover: {
+		"GetRoutes",
+		"GetNodeRoutes",
+		"EnableRoute",
+		"DisableRoute",
+	},
+}
+
+// apiKeyAdminMethods can only be called with full access, as they
+// would otherwise allow a scoped key to escalate its own access.
+var apiKeyAdminMethods = []string{
+	"CreateApiKey",
+	"BackupDatabase",
+	"RestoreDatabase",
+	"ExportState",
+	"ImportState",
+}
+
 // APIKey describes the datamodel for API keys used to remotely authenticate with
 // headscale.
 type APIKey struct {
@@ -17,6 +75,14 @@ type APIKey struct {
 	CreatedAt  *time.Time
 	Expiration *time.Time
 	LastSeen   *time.Time
+
+	// Scopes are the roles and API calls the key is allowed to use,
+	// the key has full access if empty.
+	Scopes StringList
+
+	// Users limits the key to objects owned by these users, the key
+	// is not limited if empty.
+	Users StringList
 }
 
 func (key *APIKey) Proto() *v1.ApiKey {
@@ -37,5 +103,82 @@ func (key *APIKey) Proto() *v1.ApiKey {
 		protoKey.LastSeen = timestamppb.New(*key.LastSeen)
 	}
 
+	protoKey.Scopes = key.Scopes
+	protoKey.Users = key.Users
+
 	return &protoKey
 }
+
+// HasFullAccess reports if the key is allowed to call every API call for
+// all users.
+func (key *APIKey) HasFullAccess() bool {
+	return len(key.Users) == 0 && key.IsAdmin()
+}
+
+// IsAdmin reports if the key is allowed to call every API call, it might
+// still be limited to some users.
+func (key *APIKey) IsAdmin() bool {
+	return len(key.Scopes) == 0 || slices.Contains(key.Scopes, APIKeyRoleAdmin)
+}
+
+// AllowsMethod reports if the scopes of the key allow the given API call,
+// either by name (ListNodes) or by gRPC method
+// (/headscale.v1.HeadscaleService/ListNodes).
+func (key *APIKey) AllowsMethod(method string) bool {
+	method = path.Base(method)
+
+	if key.IsAdmin() {
+		return true
+	}
+
+	if slices.Contains(apiKeyAdminMethods, method) {
+		return false
+	}
+
+	for _, scope := range key.Scopes {
+		if scope == method || slices.Contains(APIKeyRoles[scope], method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsUser reports if the key is allowed to access objects of the user.
+func (key *APIKey) AllowsUser(user string) bool {
+	return len(key.Users) == 0 || slices.Contains(key.Users, user)
+}
+
+// ValidateAPIKeyScopes checks that every scope is either a known role
+// or the name of an API call.
+func ValidateAPIKeyScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if scope == APIKeyRoleAdmin {
+			continue
+		}
+
+		if _, ok := APIKeyRoles[scope]; ok {
+			continue
+		}
+
+		if !slices.Contains(apiMethods(), scope) {
+			return fmt.Errorf("%w: %q is neither a role nor an API call", ErrAPIKeyInvalidScope, scope)
+		}
+	}
+
+	return nil
+}
+
+// apiMethods returns the names of all calls of the headscale API.
+func apiMethods() []string {
+	methods := []string{}
+	for _, method := range v1.HeadscaleService_ServiceDesc.Methods {
+		methods = append(methods, method.MethodName)
+	}
+
+	for _, stream := range v1.HeadscaleService_ServiceDesc.Streams {
+		methods = append(methods, stream.StreamName)
+	}
+
+	return methods
+}
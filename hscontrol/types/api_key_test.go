@@ -0,0 +1,119 @@
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIKeyAllowsMethod(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        APIKey
+		method     string
+		want       bool
+		fullAccess bool
+	}{
+		{
+			name:       "no-scope",
+			key:        APIKey{},
+			method:     "DeleteUser",
+			want:       true,
+			fullAccess: true,
+		},
+		{
+			name:       "admin",
+			key:        APIKey{Scopes: StringList{APIKeyRoleAdmin}},
+			method:     "CreateApiKey",
+			want:       true,
+			fullAccess: true,
+		},
+		{
+			name:   "admin-limited-to-user",
+			key:    APIKey{Scopes: StringList{APIKeyRoleAdmin}, Users: StringList{"ci"}},
+			method: "DeleteNode",
+			want:   true,
+		},
+		{
+			name:   "read-only-list",
+			key:    APIKey{Scopes: StringList{APIKeyRoleReadOnly}},
+			method: "/headscale.v1.HeadscaleService/ListNodes",
+			want:   true,
+		},
+		{
+			name:   "read-only-write",
+			key:    APIKey{Scopes: StringList{APIKeyRoleReadOnly}},
+			method: "/headscale.v1.HeadscaleService/DeleteNode",
+			want:   false,
+		},
+		{
+			name:   "preauthkey-issuer",
+			key:    APIKey{Scopes: StringList{APIKeyRolePreAuthKeyIssuer}},
+			method: "CreatePreAuthKey",
+			want:   true,
+		},
+		{
+			name:   "route-approver-and-method",
+			key:    APIKey{Scopes: StringList{APIKeyRoleRouteApprover, "ListNodes"}},
+			method: "ListNodes",
+			want:   true,
+		},
+		{
+			name:   "route-approver-delete",
+			key:    APIKey{Scopes: StringList{APIKeyRoleRouteApprover}},
+			method: "DeleteRoute",
+			want:   false,
+		},
+		{
+			name:   "create-api-key-needs-full-access",
+			key:    APIKey{Scopes: StringList{"CreateApiKey"}},
+			method: "CreateApiKey",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.AllowsMethod(tt.method); got != tt.want {
+				t.Errorf("AllowsMethod() = %v, want %v", got, tt.want)
+			}
+
+			if got := tt.key.HasFullAccess(); got != tt.fullAccess {
+				t.Errorf("HasFullAccess() = %v, want %v", got, tt.fullAccess)
+			}
+		})
+	}
+}
+
+func TestValidateAPIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "roles-and-methods",
+			scopes: []string{APIKeyRoleAdmin, APIKeyRoleReadOnly, "EnableRoute", "WatchEvents"},
+		},
+		{
+			name:    "unknown",
+			scopes:  []string{APIKeyRoleReadOnly, "write-only"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAPIKeyScopes(tt.scopes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAPIKeyScopes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrAPIKeyInvalidScope) {
+				t.Errorf("ValidateAPIKeyScopes() error = %v, want %v", err, ErrAPIKeyInvalidScope)
+			}
+		})
+	}
+}
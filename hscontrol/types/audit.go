@@ -0,0 +1,84 @@
+package types
+
+import (
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditUserCreate       = "user.create"
+	AuditUserRename       = "user.rename"
+	AuditUserDelete       = "user.delete"
+	AuditPreAuthKeyCreate = "pre_auth_key.create"
+	AuditPreAuthKeyExpire = "pre_auth_key.expire"
+	AuditNodeRegister     = "node.register"
+	AuditNodeSetTags      = "node.set_tags"
+	AuditNodeDelete       = "node.delete"
+	AuditNodeExpire       = "node.expire"
+	AuditNodeSetExpiry    = "node.set_expiry"
+	AuditNodeNoExpiry     = "node.disable_expiry"
+	AuditNodeRename       = "node.rename"
+	AuditNodeMove         = "node.move"
+	AuditNodeReject       = "node.reject_registration"
+	AuditNodeApprove      = "node.approve"
+	AuditNodeRevoke       = "node.revoke_approval"
+	AuditNodeSetIP        = "node.set_ip"
+	AuditRouteEnable      = "route.enable"
+	AuditRouteDisable     = "route.disable"
+	AuditRouteDelete      = "route.delete"
+	AuditAPIKeyCreate     = "api_key.create"
+	AuditAPIKeyExpire     = "api_key.expire"
+	AuditPolicySet        = "policy.set"
+	AuditDatabaseRestore  = "database.restore"
+	AuditStateImport      = "state.import"
+
+	AuditIPReservationCreate = "ip_reservation.create"
+	AuditIPReservationDelete = "ip_reservation.delete"
+)
+
+// Actors recorded in the audit log, the prefixes are followed by the
+// API key prefix, the OIDC subject or the ID of the pre auth key.
+const (
+	AuditActorUnixSocket       = "unix_socket"
+	AuditActorUnknown          = "unknown"
+	AuditActorAPIKeyPrefix     = "api_key:"
+	AuditActorOIDCPrefix       = "oidc:"
+	AuditActorPreAuthKeyPrefix = "pre_auth_key:"
+)
+
+// AuditEvent records an administrative change, who made it, what it was
+// made to and the state of the target before and after the change.
+type AuditEvent struct {
+	ID        uint64    `gorm:"primary_key"`
+	CreatedAt time.Time `gorm:"index"`
+
+	// Actor is who made the change, e.g. "api_key:<prefix>",
+	// "unix_socket" or "oidc:<subject>".
+	Actor string `gorm:"index"`
+
+	// Action is what was done, e.g. "route.enable".
+	Action string `gorm:"index"`
+
+	// Target is the object changed, e.g. "node:12" or "user:alice".
+	Target string `gorm:"index"`
+
+	// Before and After hold the JSON representation of the target,
+	// they are empty when the target did not exist.
+	Before string
+	After  string
+}
+
+func (event *AuditEvent) Proto() *v1.AuditEvent {
+	return &v1.AuditEvent{
+		Id:        event.ID,
+		CreatedAt: timestamppb.New(event.CreatedAt),
+		Actor:     event.Actor,
+		Action:    event.Action,
+		Target:    event.Target,
+		Before:    event.Before,
+		After:     event.After,
+	}
+}
@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// Replica is a headscale instance sharing the database with others in
+// high availability mode. LastSeen is refreshed on every heartbeat.
+type Replica struct {
+	Name     string    `gorm:"primary_key"`
+	LastSeen time.Time `gorm:"index"`
+}
+
+// NodeSession records that a node has a poll session open on a replica,
+// so every replica can tell if the node is connected.
+type NodeSession struct {
+	MachineKey string `gorm:"primary_key"`
+	Replica    string `gorm:"primary_key;index"`
+	CreatedAt  time.Time
+}
+
+// Lease is held by a single headscale process at a time, until it
+// expires or is released. It elects the process running the background
+// jobs among those sharing the database.
+type Lease struct {
+	Name      string `gorm:"primary_key"`
+	Holder    string
+	ExpiresAt time.Time
+}
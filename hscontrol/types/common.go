@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/netip"
 
+	"go.opentelemetry.io/otel/trace"
 	"tailscale.com/tailcfg"
 )
 
@@ -100,6 +101,25 @@ const (
 	StateDERPUpdated
 )
 
+func (su StateUpdateType) String() string {
+	switch su {
+	case StateFullUpdate:
+		return "full"
+	case StatePeerChanged:
+		return "peer_changed"
+	case StatePeerChangedPatch:
+		return "peer_changed_patch"
+	case StatePeerRemoved:
+		return "peer_removed"
+	case StateSelfUpdate:
+		return "self"
+	case StateDERPUpdated:
+		return "derp"
+	}
+
+	return "unknown"
+}
+
 // StateUpdate is an internal message containing information about
 // a state change that has happened to the network.
 // If type is StateFullUpdate, all fields are ignored.
@@ -128,6 +148,10 @@ type StateUpdate struct {
 	// Additional message for tracking origin or what being
 	// updated, useful for ambiguous updates like StatePeerChanged.
 	Message string
+
+	// SpanContext is the span of the notification sending the update,
+	// the poll session of a node traces the update as its child.
+	SpanContext trace.SpanContext
 }
 
 // Valid reports if a StateUpdate is correctly filled and
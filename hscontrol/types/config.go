@@ -7,6 +7,9 @@ import (
 	"net/netip"
 	"net/url"
 	"os"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -31,6 +34,24 @@ var errOidcMutuallyExclusive = errors.New(
 	"oidc_client_secret and oidc_client_secret_path are mutually exclusive",
 )
 
+var errWebhookURLMissing = errors.New("webhook is missing an url")
+
+var (
+	errIPPoolOwner          = errors.New("ip pool must have either a user or a tag")
+	errIPPoolTag            = errors.New("ip pool tag must start with \"tag:\"")
+	errIPPoolPrefixMissing  = errors.New("ip pool has no prefixes")
+	errIPPoolOutsidePrefix  = errors.New("ip pool prefix is not within ip_prefixes")
+	errIPPoolOverlap        = errors.New("ip pool prefixes overlap")
+	errIPPoolDuplicateOwner = errors.New("ip pool user or tag is used by another pool")
+
+	errNodeExpiryOwner = errors.New("node expiry override must have either a user or a tag")
+	errNodeExpiryTag   = errors.New("node expiry override tag must start with \"tag:\"")
+
+	errNodeExpirySMTPFrom = errors.New("node_expiry.notify.smtp.from is required to send emails")
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
 // Config contains the initial Headscale configuration.
 type Config struct {
 	ServerURL                      string
@@ -41,6 +62,8 @@ type Config struct {
 	EphemeralNodeInactivityTimeout time.Duration
 	NodeUpdateCheckInterval        time.Duration
 	IPPrefixes                     []netip.Prefix
+	IPPools                        IPPools
+	IPAllocation                   IPAllocationConfig
 	NoisePrivateKeyPath            string
 	BaseDomain                     string
 	Log                            LogConfig
@@ -75,6 +98,18 @@ type Config struct {
 	CLI CLIConfig
 
 	ACL ACLConfig
+
+	Audit AuditConfig
+
+	Webhooks []WebhookConfig
+
+	NodeApproval NodeApprovalConfig
+
+	NodeExpiry NodeExpiryConfig
+
+	HA HAConfig
+
+	Tracing TracingConfig
 }
 
 type TLSConfig struct {
@@ -134,7 +169,239 @@ type CLIConfig struct {
 }
 
 type ACLConfig struct {
-	PolicyPath string
+	PolicyPath      string
+	PolicyMode      string
+	WatchPolicyFile bool
+}
+
+type AuditConfig struct {
+	// LogPath is the file audit events are appended to as JSON lines,
+	// in addition to the database. Empty disables the file.
+	LogPath string
+}
+
+type WebhookConfig struct {
+	URL string `mapstructure:"url"`
+
+	// Events limits the event types delivered to this webhook,
+	// all events are delivered if empty.
+	Events []string `mapstructure:"events"`
+
+	// Secret is used to sign the request body with HMAC-SHA256.
+	Secret string `mapstructure:"secret"`
+
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+const (
+	IPAllocationSequential = "sequential"
+	IPAllocationRandom     = "random"
+)
+
+// IPAllocationConfig is how addresses are picked from the free space of
+// ip_prefixes.
+type IPAllocationConfig struct {
+	// Strategy is either IPAllocationSequential, the first free address
+	// is used, or IPAllocationRandom, any free address can be used.
+	Strategy string
+
+	// Quarantine is for how long addresses released by a node are not
+	// given to another one.
+	Quarantine time.Duration
+}
+
+// IPPool is a part of ip_prefixes the addresses of the nodes of a user,
+// or of the nodes tagged with an ACL tag, are allocated from.
+type IPPool struct {
+	User     string
+	Tag      string
+	Prefixes []netip.Prefix
+}
+
+// String returns the owner of the pool, the tag or "user:<name>".
+func (pool IPPool) String() string {
+	if pool.Tag != "" {
+		return pool.Tag
+	}
+
+	return "user:" + pool.User
+}
+
+type IPPools []IPPool
+
+// ForNode returns the pool the addresses of node with the given tags
+// are allocated from, or nil if it is not in any pool. Tag pools take
+// precedence over user pools, the first pool of the configuration wins
+// if the node has several tags with a pool.
+func (pools IPPools) ForNode(node *Node, tags []string) *IPPool {
+	for index, pool := range pools {
+		if pool.Tag != "" && slices.Contains(tags, pool.Tag) {
+			return &pools[index]
+		}
+	}
+
+	for index, pool := range pools {
+		if pool.User != "" && pool.User == node.User.Name {
+			return &pools[index]
+		}
+	}
+
+	return nil
+}
+
+// IPSet returns the addresses of all the pools.
+func (pools IPPools) IPSet() (*netipx.IPSet, error) {
+	var builder netipx.IPSetBuilder
+	for _, pool := range pools {
+		for _, prefix := range pool.Prefixes {
+			builder.AddPrefix(prefix)
+		}
+	}
+
+	return builder.IPSet()
+}
+
+type NodeApprovalConfig struct {
+	// Required keeps nodes registered with a pre-auth key or OIDC
+	// unauthorized until an administrator approves them.
+	Required bool
+
+	// BypassTags lists ACL tags, nodes registered with a pre-auth key
+	// carrying one of them are approved right away.
+	BypassTags []string
+}
+
+// NeedsApproval reports whether a node registered with the given ACL
+// tags has to wait for an administrator to approve it.
+func (cfg NodeApprovalConfig) NeedsApproval(tags []string) bool {
+	if !cfg.Required {
+		return false
+	}
+
+	for _, tag := range tags {
+		if slices.Contains(cfg.BypassTags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NodeExpiryConfig is the policy setting the expiry of the keys of the
+// nodes.
+type NodeExpiryConfig struct {
+	// Default is the key expiry of the nodes without an override, 0 if
+	// their keys do not expire.
+	Default time.Duration
+
+	Overrides []NodeExpiryOverride
+
+	Notify NodeExpiryNotifyConfig
+}
+
+// NodeExpiryNotifyConfig sets up the notifications sent to the owners of
+// nodes before their keys expire.
+type NodeExpiryNotifyConfig struct {
+	// Before is how long before the expiry of a node the notifications
+	// are sent, and its client warns that it has to log in again. The
+	// notifications are disabled if it is 0.
+	Before time.Duration
+
+	// Log writes the notifications to the log.
+	Log bool
+
+	// Webhook receives the node.expiring events, if its URL is set.
+	Webhook WebhookConfig
+
+	SMTP SMTPConfig
+}
+
+// SMTPConfig sends emails through an SMTP server that does not require
+// authentication, usually the mail server of the host.
+type SMTPConfig struct {
+	// Address of the server, e.g. "localhost:25". No email is sent if it
+	// is empty.
+	Address string
+
+	From string
+
+	// Domain is appended to the user names that are not email
+	// addresses to send them an email.
+	Domain string
+}
+
+// NodeExpiryOverride sets the key expiry of the nodes of User or with
+// Tag, 0 if their keys do not expire.
+type NodeExpiryOverride struct {
+	User   string
+	Tag    string
+	Expiry time.Duration
+}
+
+// ForNode returns the key expiry of node with the given tags, 0 if its
+// key does not expire. Tag overrides take precedence over user
+// overrides, the first override of the configuration wins if the node
+// has several tags with an override.
+func (cfg NodeExpiryConfig) ForNode(node *Node, tags []string) time.Duration {
+	if node.ExpiryDisabled {
+		return 0
+	}
+
+	for _, override := range cfg.Overrides {
+		if override.Tag != "" && slices.Contains(tags, override.Tag) {
+			return override.Expiry
+		}
+	}
+
+	for _, override := range cfg.Overrides {
+		if override.User != "" && override.User == node.User.Name {
+			return override.Expiry
+		}
+	}
+
+	return cfg.Default
+}
+
+// HAConfig sets up several headscale replicas sharing a PostgreSQL
+// database, each serving some of the nodes.
+type HAConfig struct {
+	Enabled bool
+
+	// ReplicaName identifies this replica among the replicas sharing
+	// the database, it defaults to the hostname.
+	ReplicaName string
+
+	// HeartbeatInterval is how often a replica tells the others that it
+	// is alive. A replica silent for three intervals is considered
+	// gone, and its nodes offline.
+	HeartbeatInterval time.Duration
+}
+
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterFile   = "file"
+	TracingExporterMemory = "memory"
+)
+
+// TracingConfig sets up OpenTelemetry tracing of the requests handled by
+// headscale.
+type TracingConfig struct {
+	Enabled bool
+
+	// Exporter is where spans are sent: an OTLP collector, a file of
+	// JSON lines, or memory, keeping the latest spans to be read from
+	// /debug/traces on the metrics listener.
+	Exporter string
+
+	// Endpoint is the host:port of the OTLP collector, over gRPC.
+	Endpoint string
+	Insecure bool
+
+	// Path is the file the spans are appended to.
+	Path string
+
+	// SampleRatio is the fraction of new traces that are recorded.
+	SampleRatio float64
 }
 
 type LogConfig struct {
@@ -189,15 +456,33 @@ func LoadConfig(path string, isFile bool) error {
 	viper.SetDefault("oidc.strip_email_domain", true)
 	viper.SetDefault("oidc.only_start_if_oidc_is_available", true)
 	viper.SetDefault("oidc.expiry", "180d")
+
+	viper.SetDefault("node_expiry.default", "0")
+	viper.SetDefault("node_expiry.notify.before", "0")
+	viper.SetDefault("node_expiry.notify.log", true)
 	viper.SetDefault("oidc.use_expiry_from_token", false)
 
 	viper.SetDefault("logtail.enabled", false)
 	viper.SetDefault("randomize_client_port", false)
 
+	viper.SetDefault("ip_allocation", IPAllocationSequential)
+	viper.SetDefault("ip_allocation_quarantine", "0s")
+
 	viper.SetDefault("ephemeral_node_inactivity_timeout", "120s")
 
+	viper.SetDefault("acl_policy_mode", PolicyModeFile)
+	viper.SetDefault("acl_policy_watch", false)
+
 	viper.SetDefault("node_update_check_interval", "10s")
 
+	viper.SetDefault("ha.enabled", false)
+	viper.SetDefault("ha.heartbeat_interval", "10s")
+
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", TracingExporterOTLP)
+	viper.SetDefault("tracing.endpoint", "localhost:4317")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
 	if IsCLIConfigured() {
 		return nil
 	}
@@ -208,6 +493,24 @@ func LoadConfig(path string, isFile bool) error {
 		return fmt.Errorf("fatal error reading config file: %w", err)
 	}
 
+	return validateServerConfig()
+}
+
+// ReloadConfig reads the configuration file loaded by LoadConfig again,
+// validates it and returns the resulting configuration.
+func ReloadConfig() (*Config, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	if err := validateServerConfig(); err != nil {
+		return nil, err
+	}
+
+	return GetHeadscaleConfig()
+}
+
+func validateServerConfig() error {
 	// Collect any validation errors and return them all at once
 	var errorText string
 	if (viper.GetString("tls_letsencrypt_hostname") != "") &&
@@ -257,6 +560,59 @@ func LoadConfig(path string, isFile bool) error {
 		)
 	}
 
+	if policyMode := viper.GetString("acl_policy_mode"); policyMode != PolicyModeFile &&
+		policyMode != PolicyModeDB {
+		errorText += fmt.Sprintf(
+			"Fatal config error: acl_policy_mode (%s) must be either %q or %q\n",
+			policyMode,
+			PolicyModeFile,
+			PolicyModeDB,
+		)
+	}
+
+	if allocation := viper.GetString("ip_allocation"); allocation != IPAllocationSequential &&
+		allocation != IPAllocationRandom {
+		errorText += fmt.Sprintf(
+			"Fatal config error: ip_allocation (%s) must be either %q or %q\n",
+			allocation,
+			IPAllocationSequential,
+			IPAllocationRandom,
+		)
+	}
+
+	if viper.GetBool("ha.enabled") && viper.GetString("db_type") != "postgres" {
+		errorText += "Fatal config error: ha.enabled requires db_type to be postgres\n"
+	}
+
+	if viper.GetDuration("ha.heartbeat_interval") <= 0 {
+		errorText += fmt.Sprintf(
+			"Fatal config error: ha.heartbeat_interval (%s) must be greater than 0\n",
+			viper.GetString("ha.heartbeat_interval"),
+		)
+	}
+
+	if viper.GetBool("tracing.enabled") {
+		switch exporter := viper.GetString("tracing.exporter"); exporter {
+		case TracingExporterOTLP, TracingExporterMemory:
+		case TracingExporterFile:
+			if viper.GetString("tracing.path") == "" {
+				errorText += "Fatal config error: tracing.path is required by the file exporter\n"
+			}
+		default:
+			errorText += fmt.Sprintf(
+				"Fatal config error: tracing.exporter (%s) must be one of %q, %q or %q\n",
+				exporter,
+				TracingExporterOTLP,
+				TracingExporterFile,
+				TracingExporterMemory,
+			)
+		}
+
+		if ratio := viper.GetFloat64("tracing.sample_ratio"); ratio < 0 || ratio > 1 {
+			errorText += "Fatal config error: tracing.sample_ratio must be between 0 and 1\n"
+		}
+	}
+
 	if errorText != "" {
 		//nolint
 		return errors.New(strings.TrimSuffix(errorText, "\n"))
@@ -265,6 +621,92 @@ func LoadConfig(path string, isFile bool) error {
 	}
 }
 
+// RestartRequired returns the name of every setting that differs between
+// cfg and other and can only be applied by restarting headscale.
+func (cfg *Config) RestartRequired(other *Config) []string {
+	prefixes := func(prefixes []netip.Prefix) []string {
+		strs := make([]string, len(prefixes))
+		for index, prefix := range prefixes {
+			strs[index] = prefix.String()
+		}
+		sort.Strings(strs)
+
+		return strs
+	}
+
+	// The DERP map sources are applied live, the embedded DERP
+	// server and the update worker are not.
+	derpStatic := func(derp DERPConfig) DERPConfig {
+		derp.URLs = nil
+		derp.Paths = nil
+
+		return derp
+	}
+
+	// Only the allow-lists and the settings used when a user logs
+	// in are read on every request, the provider is set up once.
+	oidcStatic := func(oidc OIDCConfig) OIDCConfig {
+		oidc.AllowedDomains = nil
+		oidc.AllowedUsers = nil
+		oidc.AllowedGroups = nil
+		oidc.ExtraParams = nil
+		oidc.Expiry = 0
+		oidc.UseExpiryFromToken = false
+		oidc.StripEmaildomain = false
+
+		return oidc
+	}
+
+	settings := []struct {
+		name     string
+		old, new any
+	}{
+		{"server_url", cfg.ServerURL, other.ServerURL},
+		{"listen_addr", cfg.Addr, other.Addr},
+		{"metrics_listen_addr", cfg.MetricsAddr, other.MetricsAddr},
+		{"grpc_listen_addr", cfg.GRPCAddr, other.GRPCAddr},
+		{"grpc_allow_insecure", cfg.GRPCAllowInsecure, other.GRPCAllowInsecure},
+		{"node_update_check_interval", cfg.NodeUpdateCheckInterval, other.NodeUpdateCheckInterval},
+		{"ip_prefixes", prefixes(cfg.IPPrefixes), prefixes(other.IPPrefixes)},
+		{"ip_pools", cfg.IPPools, other.IPPools},
+		{"ip_allocation", cfg.IPAllocation.Strategy, other.IPAllocation.Strategy},
+		{"ip_allocation_quarantine", cfg.IPAllocation.Quarantine, other.IPAllocation.Quarantine},
+		{"noise.private_key_path", cfg.NoisePrivateKeyPath, other.NoisePrivateKeyPath},
+		{"dns_config.base_domain", cfg.BaseDomain, other.BaseDomain},
+		{"log", cfg.Log, other.Log},
+		{"derp.server", derpStatic(cfg.DERP), derpStatic(other.DERP)},
+		{"db_type", cfg.DBtype, other.DBtype},
+		{"db_path", cfg.DBpath, other.DBpath},
+		{"db_host", cfg.DBhost, other.DBhost},
+		{"db_port", cfg.DBport, other.DBport},
+		{"db_name", cfg.DBname, other.DBname},
+		{"db_user", cfg.DBuser, other.DBuser},
+		{"db_pass", cfg.DBpass, other.DBpass},
+		{"db_ssl", cfg.DBssl, other.DBssl},
+		{"tls", cfg.TLS, other.TLS},
+		{"acme_url", cfg.ACMEURL, other.ACMEURL},
+		{"acme_email", cfg.ACMEEmail, other.ACMEEmail},
+		{"unix_socket", cfg.UnixSocket, other.UnixSocket},
+		{"unix_socket_permission", cfg.UnixSocketPermission, other.UnixSocketPermission},
+		{"oidc", oidcStatic(cfg.OIDC), oidcStatic(other.OIDC)},
+		{"logtail", cfg.LogTail, other.LogTail},
+		{"acl_policy_watch", cfg.ACL.WatchPolicyFile, other.ACL.WatchPolicyFile},
+		{"audit_log", cfg.Audit, other.Audit},
+		{"webhooks", cfg.Webhooks, other.Webhooks},
+		{"ha", cfg.HA, other.HA},
+		{"tracing", cfg.Tracing, other.Tracing},
+	}
+
+	changed := []string{}
+	for _, setting := range settings {
+		if !reflect.DeepEqual(setting.old, setting.new) {
+			changed = append(changed, setting.name)
+		}
+	}
+
+	return changed
+}
+
 func GetTLSConfig() TLSConfig {
 	return TLSConfig{
 		LetsEncrypt: LetsEncryptConfig{
@@ -355,12 +797,191 @@ func GetLogTailConfig() LogTailConfig {
 
 func GetACLConfig() ACLConfig {
 	policyPath := viper.GetString("acl_policy_path")
+	policyMode := viper.GetString("acl_policy_mode")
+	watchPolicyFile := viper.GetBool("acl_policy_watch")
 
 	return ACLConfig{
-		PolicyPath: policyPath,
+		PolicyPath:      policyPath,
+		PolicyMode:      policyMode,
+		WatchPolicyFile: watchPolicyFile,
 	}
 }
 
+func GetWebhooksConfig() ([]WebhookConfig, error) {
+	if !viper.IsSet("webhooks") {
+		return nil, nil
+	}
+
+	var webhooks []WebhookConfig
+	if err := viper.UnmarshalKey("webhooks", &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to parse webhooks: %w", err)
+	}
+
+	for i := range webhooks {
+		if webhooks[i].URL == "" {
+			return nil, fmt.Errorf("webhooks[%d]: %w", i, errWebhookURLMissing)
+		}
+
+		if webhooks[i].Timeout == 0 {
+			webhooks[i].Timeout = defaultWebhookTimeout
+		}
+	}
+
+	return webhooks, nil
+}
+
+// GetIPPoolsConfig reads the ip_pools, which have to be within the
+// given ip_prefixes and must not overlap.
+func GetIPPoolsConfig(ipPrefixes []netip.Prefix) (IPPools, error) {
+	if !viper.IsSet("ip_pools") {
+		return nil, nil
+	}
+
+	var rawPools []struct {
+		User     string   `mapstructure:"user"`
+		Tag      string   `mapstructure:"tag"`
+		Prefixes []string `mapstructure:"prefixes"`
+	}
+	if err := viper.UnmarshalKey("ip_pools", &rawPools); err != nil {
+		return nil, fmt.Errorf("failed to parse ip_pools: %w", err)
+	}
+
+	pools := make(IPPools, 0, len(rawPools))
+	owners := map[string]bool{}
+	for index, rawPool := range rawPools {
+		pool := IPPool{
+			User: rawPool.User,
+			Tag:  rawPool.Tag,
+		}
+
+		if (pool.User == "") == (pool.Tag == "") {
+			return nil, fmt.Errorf("ip_pools[%d]: %w", index, errIPPoolOwner)
+		}
+
+		if pool.Tag != "" && !strings.HasPrefix(pool.Tag, "tag:") {
+			return nil, fmt.Errorf("ip_pools[%d]: %w", index, errIPPoolTag)
+		}
+
+		if owners[pool.String()] {
+			return nil, fmt.Errorf("ip_pools[%d]: %w", index, errIPPoolDuplicateOwner)
+		}
+		owners[pool.String()] = true
+
+		if len(rawPool.Prefixes) == 0 {
+			return nil, fmt.Errorf("ip_pools[%d]: %w", index, errIPPoolPrefixMissing)
+		}
+
+		for _, rawPrefix := range rawPool.Prefixes {
+			prefix, err := netip.ParsePrefix(rawPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("ip_pools[%d]: failed to parse prefix: %w", index, err)
+			}
+			prefix = prefix.Masked()
+
+			if !slices.ContainsFunc(ipPrefixes, func(ipPrefix netip.Prefix) bool {
+				return ipPrefix.Bits() <= prefix.Bits() && ipPrefix.Contains(prefix.Addr())
+			}) {
+				return nil, fmt.Errorf("ip_pools[%d]: %s: %w", index, prefix, errIPPoolOutsidePrefix)
+			}
+
+			for _, other := range pools {
+				for _, otherPrefix := range other.Prefixes {
+					if prefix.Overlaps(otherPrefix) {
+						return nil, fmt.Errorf(
+							"ip_pools[%d]: %s and %s of %s: %w",
+							index,
+							prefix,
+							otherPrefix,
+							other,
+							errIPPoolOverlap,
+						)
+					}
+				}
+			}
+
+			pool.Prefixes = append(pool.Prefixes, prefix)
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+// GetNodeExpiryConfig reads the node_expiry policy, each override has
+// either a user or a tag.
+func GetNodeExpiryConfig() (NodeExpiryConfig, error) {
+	cfg := NodeExpiryConfig{}
+
+	if value := viper.GetString("node_expiry.default"); value != "" {
+		defaultExpiry, err := model.ParseDuration(value)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to parse node_expiry.default: %w", err)
+		}
+		cfg.Default = time.Duration(defaultExpiry)
+	}
+
+	var rawOverrides []struct {
+		User   string `mapstructure:"user"`
+		Tag    string `mapstructure:"tag"`
+		Expiry string `mapstructure:"expiry"`
+	}
+	if err := viper.UnmarshalKey("node_expiry.overrides", &rawOverrides); err != nil {
+		return cfg, fmt.Errorf("failed to parse node_expiry.overrides: %w", err)
+	}
+
+	for index, rawOverride := range rawOverrides {
+		if (rawOverride.User == "") == (rawOverride.Tag == "") {
+			return cfg, fmt.Errorf("node_expiry.overrides[%d]: %w", index, errNodeExpiryOwner)
+		}
+
+		if rawOverride.Tag != "" && !strings.HasPrefix(rawOverride.Tag, "tag:") {
+			return cfg, fmt.Errorf("node_expiry.overrides[%d]: %w", index, errNodeExpiryTag)
+		}
+
+		expiry, err := model.ParseDuration(rawOverride.Expiry)
+		if err != nil {
+			return cfg, fmt.Errorf("node_expiry.overrides[%d]: failed to parse expiry: %w", index, err)
+		}
+
+		cfg.Overrides = append(cfg.Overrides, NodeExpiryOverride{
+			User:   rawOverride.User,
+			Tag:    rawOverride.Tag,
+			Expiry: time.Duration(expiry),
+		})
+	}
+
+	if value := viper.GetString("node_expiry.notify.before"); value != "" {
+		before, err := model.ParseDuration(value)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to parse node_expiry.notify.before: %w", err)
+		}
+		cfg.Notify.Before = time.Duration(before)
+	}
+
+	cfg.Notify.Log = viper.GetBool("node_expiry.notify.log")
+
+	cfg.Notify.Webhook = WebhookConfig{
+		URL:     viper.GetString("node_expiry.notify.webhook.url"),
+		Secret:  viper.GetString("node_expiry.notify.webhook.secret"),
+		Timeout: viper.GetDuration("node_expiry.notify.webhook.timeout"),
+	}
+	if cfg.Notify.Webhook.Timeout == 0 {
+		cfg.Notify.Webhook.Timeout = defaultWebhookTimeout
+	}
+
+	cfg.Notify.SMTP = SMTPConfig{
+		Address: viper.GetString("node_expiry.notify.smtp.address"),
+		From:    viper.GetString("node_expiry.notify.smtp.from"),
+		Domain:  viper.GetString("node_expiry.notify.smtp.domain"),
+	}
+	if cfg.Notify.SMTP.Address != "" && cfg.Notify.SMTP.From == "" {
+		return cfg, errNodeExpirySMTPFrom
+	}
+
+	return cfg, nil
+}
+
 func GetLogConfig() LogConfig {
 	logLevelStr := viper.GetString("log.level")
 	logLevel, err := zerolog.ParseLevel(logLevelStr)
@@ -580,6 +1201,21 @@ func GetHeadscaleConfig() (*Config, error) {
 			Msgf("'ip_prefixes' not configured, falling back to default: %v", prefixes)
 	}
 
+	ipPools, err := GetIPPoolsConfig(prefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := GetWebhooksConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeExpiry, err := GetNodeExpiryConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	oidcClientSecret := viper.GetString("oidc.client_secret")
 	oidcClientSecretPath := viper.GetString("oidc.client_secret_path")
 	if oidcClientSecretPath != "" && oidcClientSecret != "" {
@@ -602,6 +1238,11 @@ func GetHeadscaleConfig() (*Config, error) {
 		DisableUpdateCheck: viper.GetBool("disable_check_updates"),
 
 		IPPrefixes: prefixes,
+		IPPools:    ipPools,
+		IPAllocation: IPAllocationConfig{
+			Strategy:   viper.GetString("ip_allocation"),
+			Quarantine: viper.GetDuration("ip_allocation_quarantine"),
+		},
 		NoisePrivateKeyPath: util.AbsolutePathFromConfigPath(
 			viper.GetString("noise.private_key_path"),
 		),
@@ -672,6 +1313,32 @@ func GetHeadscaleConfig() (*Config, error) {
 
 		ACL: GetACLConfig(),
 
+		Audit: AuditConfig{
+			LogPath: util.AbsolutePathFromConfigPath(
+				viper.GetString("audit_log.path"),
+			),
+		},
+
+		Webhooks: webhooks,
+
+		NodeApproval: NodeApprovalConfig{
+			Required:   viper.GetBool("node_approval.required"),
+			BypassTags: viper.GetStringSlice("node_approval.bypass_tags"),
+		},
+
+		NodeExpiry: nodeExpiry,
+
+		HA: GetHAConfig(),
+
+		Tracing: TracingConfig{
+			Enabled:     viper.GetBool("tracing.enabled"),
+			Exporter:    viper.GetString("tracing.exporter"),
+			Endpoint:    viper.GetString("tracing.endpoint"),
+			Insecure:    viper.GetBool("tracing.insecure"),
+			Path:        util.AbsolutePathFromConfigPath(viper.GetString("tracing.path")),
+			SampleRatio: viper.GetFloat64("tracing.sample_ratio"),
+		},
+
 		CLI: CLIConfig{
 			Address:  viper.GetString("cli.address"),
 			APIKey:   viper.GetString("cli.api_key"),
@@ -683,6 +1350,19 @@ func GetHeadscaleConfig() (*Config, error) {
 	}, nil
 }
 
+func GetHAConfig() HAConfig {
+	replicaName := viper.GetString("ha.replica_name")
+	if replicaName == "" {
+		replicaName, _ = os.Hostname()
+	}
+
+	return HAConfig{
+		Enabled:           viper.GetBool("ha.enabled"),
+		ReplicaName:       replicaName,
+		HeartbeatInterval: viper.GetDuration("ha.heartbeat_interval"),
+	}
+}
+
 func IsCLIConfigured() bool {
 	return viper.GetString("cli.address") != "" && viper.GetString("cli.api_key") != ""
 }
@@ -0,0 +1,252 @@
+package types
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestNodeApprovalNeedsApproval(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  NodeApprovalConfig
+		tags []string
+		want bool
+	}{
+		{
+			name: "not-required",
+			cfg:  NodeApprovalConfig{},
+			want: false,
+		},
+		{
+			name: "required",
+			cfg:  NodeApprovalConfig{Required: true},
+			want: true,
+		},
+		{
+			name: "required-other-tag",
+			cfg:  NodeApprovalConfig{Required: true, BypassTags: []string{"tag:server"}},
+			tags: []string{"tag:laptop"},
+			want: true,
+		},
+		{
+			name: "bypass-tag",
+			cfg:  NodeApprovalConfig{Required: true, BypassTags: []string{"tag:server"}},
+			tags: []string{"tag:laptop", "tag:server"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.NeedsApproval(tt.tags); got != tt.want {
+				t.Errorf("NeedsApproval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeExpiryForNode(t *testing.T) {
+	cfg := NodeExpiryConfig{
+		Default: 24 * time.Hour,
+		Overrides: []NodeExpiryOverride{
+			{User: "laptops", Expiry: 90 * 24 * time.Hour},
+			{Tag: "tag:server", Expiry: 0},
+			{Tag: "tag:kiosk", Expiry: time.Hour},
+		},
+	}
+
+	tests := []struct {
+		name string
+		node *Node
+		tags []string
+		want time.Duration
+	}{
+		{
+			name: "default",
+			node: &Node{User: User{Name: "other"}},
+			want: 24 * time.Hour,
+		},
+		{
+			name: "user",
+			node: &Node{User: User{Name: "laptops"}},
+			want: 90 * 24 * time.Hour,
+		},
+		{
+			name: "tag-before-user",
+			node: &Node{User: User{Name: "laptops"}},
+			tags: []string{"tag:server"},
+			want: 0,
+		},
+		{
+			name: "first-tag-override",
+			node: &Node{},
+			tags: []string{"tag:kiosk", "tag:server"},
+			want: 0,
+		},
+		{
+			name: "disabled",
+			node: &Node{User: User{Name: "laptops"}, ExpiryDisabled: true},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.ForNode(tt.node, tt.tags); got != tt.want {
+				t.Errorf("ForNode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNodeExpiryConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []map[string]any
+		notify    map[string]any
+		want      int
+		wantErr   error
+	}{
+		{
+			name: "valid",
+			overrides: []map[string]any{
+				{"user": "laptops", "expiry": "90d"},
+				{"tag": "tag:server", "expiry": 0},
+			},
+			notify: map[string]any{"before": "7d"},
+			want:   2,
+		},
+		{
+			name:      "user-and-tag",
+			overrides: []map[string]any{{"user": "ci", "tag": "tag:ci", "expiry": "1d"}},
+			wantErr:   errNodeExpiryOwner,
+		},
+		{
+			name:      "invalid-tag",
+			overrides: []map[string]any{{"tag": "server", "expiry": "1d"}},
+			wantErr:   errNodeExpiryTag,
+		},
+		{
+			name:    "smtp-without-from",
+			notify:  map[string]any{"smtp": map[string]any{"address": "localhost:25"}},
+			wantErr: errNodeExpirySMTPFrom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			viper.Set("node_expiry.default", "30d")
+			viper.Set("node_expiry.overrides", tt.overrides)
+			viper.Set("node_expiry.notify", tt.notify)
+
+			cfg, err := GetNodeExpiryConfig()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetNodeExpiryConfig() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if cfg.Default != 30*24*time.Hour {
+				t.Errorf("GetNodeExpiryConfig().Default = %v, want 720h", cfg.Default)
+			}
+
+			if len(cfg.Overrides) != tt.want {
+				t.Errorf("GetNodeExpiryConfig().Overrides = %v, want %d overrides", cfg.Overrides, tt.want)
+			}
+
+			if cfg.Notify.Before != 7*24*time.Hour {
+				t.Errorf("GetNodeExpiryConfig().Notify.Before = %v, want 168h", cfg.Notify.Before)
+			}
+		})
+	}
+}
+
+func TestGetIPPoolsConfig(t *testing.T) {
+	ipPrefixes := []netip.Prefix{
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("fd7a:115c:a1e0::/48"),
+	}
+
+	tests := []struct {
+		name    string
+		pools   []map[string]any
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "valid",
+			pools: []map[string]any{
+				{"tag": "tag:server", "prefixes": []string{"100.64.10.0/24", "fd7a:115c:a1e0:10::/64"}},
+				{"user": "ci", "prefixes": []string{"100.64.200.5/24"}},
+			},
+			want: []string{"tag:server", "user:ci"},
+		},
+		{
+			name:    "user-and-tag",
+			pools:   []map[string]any{{"user": "ci", "tag": "tag:ci", "prefixes": []string{"100.64.10.0/24"}}},
+			wantErr: errIPPoolOwner,
+		},
+		{
+			name:    "invalid-tag",
+			pools:   []map[string]any{{"tag": "server", "prefixes": []string{"100.64.10.0/24"}}},
+			wantErr: errIPPoolTag,
+		},
+		{
+			name:    "no-prefixes",
+			pools:   []map[string]any{{"user": "ci"}},
+			wantErr: errIPPoolPrefixMissing,
+		},
+		{
+			name:    "outside-ip-prefixes",
+			pools:   []map[string]any{{"user": "ci", "prefixes": []string{"10.0.0.0/24"}}},
+			wantErr: errIPPoolOutsidePrefix,
+		},
+		{
+			name: "overlap",
+			pools: []map[string]any{
+				{"user": "ci", "prefixes": []string{"100.64.10.0/24"}},
+				{"tag": "tag:server", "prefixes": []string{"100.64.10.128/25"}},
+			},
+			wantErr: errIPPoolOverlap,
+		},
+		{
+			name: "duplicate",
+			pools: []map[string]any{
+				{"user": "ci", "prefixes": []string{"100.64.10.0/24"}},
+				{"user": "ci", "prefixes": []string{"100.64.11.0/24"}},
+			},
+			wantErr: errIPPoolDuplicateOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			viper.Set("ip_pools", tt.pools)
+
+			pools, err := GetIPPoolsConfig(ipPrefixes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetIPPoolsConfig() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(pools) != len(tt.want) {
+				t.Fatalf("GetIPPoolsConfig() = %v, want %v", pools, tt.want)
+			}
+
+			for index, pool := range pools {
+				if pool.String() != tt.want[index] {
+					t.Errorf("GetIPPoolsConfig()[%d] = %s, want %s", index, pool, tt.want[index])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package types
+
+import (
+	"net/netip"
+	"time"
+
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+)
+
+// ExportVersion is the version of the export document written by this
+// version of headscale. Documents of this or an older version can be
+// imported.
+const ExportVersion = 1
+
+// Export is the state of headscale in a document independent of the
+// database, used to move between database types or to seed other
+// instances. The IDs of the objects are kept so references between
+// them, and the IDs known by administrators, stay the same.
+type Export struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	Users          []ExportUser          `json:"users"`
+	PreAuthKeys    []ExportPreAuthKey    `json:"pre_auth_keys"`
+	Nodes          []ExportNode          `json:"nodes"`
+	Routes         []ExportRoute         `json:"routes"`
+	APIKeys        []ExportAPIKey        `json:"api_keys"`
+	IPReservations []ExportIPReservation `json:"ip_reservations"`
+
+	// Policy is the policy stored in the database, only its latest
+	// version is exported.
+	Policy *ExportPolicy `json:"policy,omitempty"`
+}
+
+type ExportUser struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportPreAuthKey struct {
+	ID         uint64     `json:"id"`
+	Key        string     `json:"key"`
+	UserID     uint       `json:"user_id"`
+	Reusable   bool       `json:"reusable"`
+	Ephemeral  bool       `json:"ephemeral"`
+	Used       bool       `json:"used"`
+	ACLTags    []string   `json:"acl_tags,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+type ExportNode struct {
+	ID              uint64            `json:"id"`
+	MachineKey      key.MachinePublic `json:"machine_key"`
+	NodeKey         key.NodePublic    `json:"node_key"`
+	DiscoKey        key.DiscoPublic   `json:"disco_key"`
+	Endpoints       []netip.AddrPort  `json:"endpoints,omitempty"`
+	Hostinfo        *tailcfg.Hostinfo `json:"hostinfo,omitempty"`
+	IPAddresses     []netip.Addr      `json:"ip_addresses"`
+	Hostname        string            `json:"hostname"`
+	GivenName       string            `json:"given_name"`
+	UserID          uint              `json:"user_id"`
+	RegisterMethod  string            `json:"register_method"`
+	ForcedTags      []string          `json:"forced_tags,omitempty"`
+	AuthKeyID       uint              `json:"auth_key_id,omitempty"`
+	LastSeen        *time.Time        `json:"last_seen,omitempty"`
+	Expiry          *time.Time        `json:"expiry,omitempty"`
+	PendingApproval bool              `json:"pending_approval,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+type ExportRoute struct {
+	ID         uint         `json:"id"`
+	NodeID     uint64       `json:"node_id"`
+	Prefix     netip.Prefix `json:"prefix"`
+	Advertised bool         `json:"advertised"`
+	Enabled    bool         `json:"enabled"`
+	IsPrimary  bool         `json:"is_primary"`
+}
+
+// ExportAPIKey holds the hash of an API key, the key itself is never
+// stored.
+type ExportAPIKey struct {
+	Prefix     string     `json:"prefix"`
+	Hash       []byte     `json:"hash"`
+	Scopes     []string   `json:"scopes,omitempty"`
+	Users      []string   `json:"users,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+	LastSeen   *time.Time `json:"last_seen,omitempty"`
+}
+
+type ExportIPReservation struct {
+	IP           netip.Addr `json:"ip"`
+	Hostname     string     `json:"hostname,omitempty"`
+	PreAuthKeyID *uint64    `json:"pre_auth_key_id,omitempty"`
+}
+
+type ExportPolicy struct {
+	Data      string    `json:"data"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (n *User) Export() ExportUser {
+	return ExportUser{
+		ID:        n.ID,
+		Name:      n.Name,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func (exp *ExportUser) User() User {
+	user := User{Name: exp.Name}
+	user.ID = exp.ID
+	user.CreatedAt = exp.CreatedAt
+
+	return user
+}
+
+func (key *PreAuthKey) Export() ExportPreAuthKey {
+	exp := ExportPreAuthKey{
+		ID:         key.ID,
+		Key:        key.Key,
+		UserID:     key.UserID,
+		Reusable:   key.Reusable,
+		Ephemeral:  key.Ephemeral,
+		Used:       key.Used,
+		CreatedAt:  key.CreatedAt,
+		Expiration: key.Expiration,
+	}
+
+	for _, tag := range key.ACLTags {
+		exp.ACLTags = append(exp.ACLTags, tag.Tag)
+	}
+
+	return exp
+}
+
+func (exp *ExportPreAuthKey) PreAuthKey() PreAuthKey {
+	pak := PreAuthKey{
+		ID:         exp.ID,
+		Key:        exp.Key,
+		UserID:     exp.UserID,
+		Reusable:   exp.Reusable,
+		Ephemeral:  exp.Ephemeral,
+		Used:       exp.Used,
+		CreatedAt:  exp.CreatedAt,
+		Expiration: exp.Expiration,
+	}
+
+	for _, tag := range exp.ACLTags {
+		pak.ACLTags = append(pak.ACLTags, PreAuthKeyACLTag{Tag: tag})
+	}
+
+	return pak
+}
+
+func (node *Node) Export() ExportNode {
+	return ExportNode{
+		ID:              node.ID,
+		MachineKey:      node.MachineKey,
+		NodeKey:         node.NodeKey,
+		DiscoKey:        node.DiscoKey,
+		Endpoints:       node.Endpoints,
+		Hostinfo:        node.Hostinfo,
+		IPAddresses:     node.IPAddresses,
+		Hostname:        node.Hostname,
+		GivenName:       node.GivenName,
+		UserID:          node.UserID,
+		RegisterMethod:  node.RegisterMethod,
+		ForcedTags:      node.ForcedTags,
+		AuthKeyID:       node.AuthKeyID,
+		LastSeen:        node.LastSeen,
+		Expiry:          node.Expiry,
+		PendingApproval: node.PendingApproval,
+		CreatedAt:       node.CreatedAt,
+	}
+}
+
+func (exp *ExportNode) Node() Node {
+	return Node{
+		ID:              exp.ID,
+		MachineKey:      exp.MachineKey,
+		NodeKey:         exp.NodeKey,
+		DiscoKey:        exp.DiscoKey,
+		Endpoints:       exp.Endpoints,
+		Hostinfo:        exp.Hostinfo,
+		IPAddresses:     exp.IPAddresses,
+		Hostname:        exp.Hostname,
+		GivenName:       exp.GivenName,
+		UserID:          exp.UserID,
+		RegisterMethod:  exp.RegisterMethod,
+		ForcedTags:      exp.ForcedTags,
+		AuthKeyID:       exp.AuthKeyID,
+		LastSeen:        exp.LastSeen,
+		Expiry:          exp.Expiry,
+		PendingApproval: exp.PendingApproval,
+		CreatedAt:       exp.CreatedAt,
+	}
+}
+
+func (r *Route) Export() ExportRoute {
+	return ExportRoute{
+		ID:         r.ID,
+		NodeID:     r.NodeID,
+		Prefix:     netip.Prefix(r.Prefix),
+		Advertised: r.Advertised,
+		Enabled:    r.Enabled,
+		IsPrimary:  r.IsPrimary,
+	}
+}
+
+func (exp *ExportRoute) Route() Route {
+	route := Route{
+		NodeID:     exp.NodeID,
+		Prefix:     IPPrefix(exp.Prefix),
+		Advertised: exp.Advertised,
+		Enabled:    exp.Enabled,
+		IsPrimary:  exp.IsPrimary,
+	}
+	route.ID = exp.ID
+
+	return route
+}
+
+func (key *APIKey) Export() ExportAPIKey {
+	return ExportAPIKey{
+		Prefix:     key.Prefix,
+		Hash:       key.Hash,
+		Scopes:     key.Scopes,
+		Users:      key.Users,
+		CreatedAt:  key.CreatedAt,
+		Expiration: key.Expiration,
+		LastSeen:   key.LastSeen,
+	}
+}
+
+func (exp *ExportAPIKey) APIKey() APIKey {
+	return APIKey{
+		Prefix:     exp.Prefix,
+		Hash:       exp.Hash,
+		Scopes:     exp.Scopes,
+		Users:      exp.Users,
+		CreatedAt:  exp.CreatedAt,
+		Expiration: exp.Expiration,
+		LastSeen:   exp.LastSeen,
+	}
+}
+
+func (res *IPReservation) Export() ExportIPReservation {
+	return ExportIPReservation{
+		IP:           res.IP,
+		Hostname:     res.Hostname,
+		PreAuthKeyID: res.PreAuthKeyID,
+	}
+}
+
+func (exp *ExportIPReservation) IPReservation() IPReservation {
+	return IPReservation{
+		IP:           exp.IP,
+		Hostname:     exp.Hostname,
+		PreAuthKeyID: exp.PreAuthKeyID,
+	}
+}
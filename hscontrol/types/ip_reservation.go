@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// IPReservation keeps an address of ip_prefixes for a node that has not
+// registered yet. The node matching the hostname or the pre-auth key of
+// the reservation gets the address when it registers.
+type IPReservation struct {
+	ID uint64 `gorm:"primary_key"`
+
+	// IPDatabaseField is the string representation of IP
+	// it is _only_ used for reading and writing the address to the
+	// database and should not be used.
+	// Use IP instead.
+	IPDatabaseField string     `gorm:"column:ip;uniqueIndex"`
+	IP              netip.Addr `gorm:"-"`
+
+	Hostname     string `gorm:"index"`
+	PreAuthKeyID *uint64
+	PreAuthKey   *PreAuthKey
+
+	CreatedAt time.Time
+}
+
+func (res *IPReservation) BeforeSave(tx *gorm.DB) error {
+	res.IPDatabaseField = res.IP.String()
+
+	return nil
+}
+
+func (res *IPReservation) AfterFind(tx *gorm.DB) error {
+	ip, err := netip.ParseAddr(res.IPDatabaseField)
+	if err != nil {
+		return fmt.Errorf("failed to parse reserved ip from db: %w", err)
+	}
+	res.IP = ip
+
+	return nil
+}
+
+// Matches reports if node is the node the address is reserved for.
+func (res *IPReservation) Matches(node *Node) bool {
+	if res.PreAuthKeyID != nil {
+		return node.AuthKeyID != 0 && uint64(node.AuthKeyID) == *res.PreAuthKeyID
+	}
+
+	return res.Hostname != "" && res.Hostname == node.Hostname
+}
+
+func (res *IPReservation) Proto() *v1.IPReservation {
+	protoRes := &v1.IPReservation{
+		Id:        res.ID,
+		Ip:        res.IP.String(),
+		Hostname:  res.Hostname,
+		CreatedAt: timestamppb.New(res.CreatedAt),
+	}
+
+	if res.PreAuthKey != nil {
+		protoRes.PreAuthKey = res.PreAuthKey.Proto()
+	}
+
+	return protoRes
+}
+
+type IPReservations []IPReservation
+
+// ForNode returns the reserved addresses of node.
+func (reservations IPReservations) ForNode(node *Node) []netip.Addr {
+	var ips []netip.Addr
+	for index := range reservations {
+		if reservations[index].Matches(node) {
+			ips = append(ips, reservations[index].IP)
+		}
+	}
+
+	return ips
+}
+
+// ReleasedIP is an address a node used until ReleasedAt, it is not given
+// to another node while it is quarantined.
+type ReleasedIP struct {
+	ID         uint64    `gorm:"primary_key"`
+	IP         string    `gorm:"uniqueIndex"`
+	ReleasedAt time.Time `gorm:"index"`
+}
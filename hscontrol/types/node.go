@@ -93,6 +93,23 @@ type Node struct {
 	LastSeen *time.Time
 	Expiry   *time.Time
 
+	// ExpiryDisabled is set on nodes whose key never expires, whatever
+	// the node_expiry policy.
+	ExpiryDisabled bool `gorm:"not null;default:false"`
+
+	// ExpiryNotified is the expiry the owner of the node was last
+	// notified of, so the notification is sent once per expiry.
+	ExpiryNotified *time.Time
+
+	// LastMapRequest is the time of the latest map request of the node,
+	// made with CapabilityVersion.
+	LastMapRequest    *time.Time
+	CapabilityVersion tailcfg.CapabilityVersion
+
+	// PendingApproval is set on nodes that have to be approved by an
+	// administrator before they are authorized to join the network.
+	PendingApproval bool `gorm:"not null;default:false"`
+
 	Routes []Route
 
 	CreatedAt time.Time
@@ -224,6 +241,19 @@ func (node *Node) CanAccess(filter []tailcfg.FilterRule, node2 *Node) bool {
 	return false
 }
 
+// Approved returns the nodes that are not waiting to be approved.
+func (nodes Nodes) Approved() Nodes {
+	approved := make(Nodes, 0, len(nodes))
+
+	for _, node := range nodes {
+		if !node.PendingApproval {
+			approved = append(approved, node)
+		}
+	}
+
+	return approved
+}
+
 func (nodes Nodes) FilterByIP(ip netip.Addr) Nodes {
 	found := make(Nodes, 0)
 
@@ -322,6 +352,9 @@ func (node *Node) Proto() *v1.Node {
 		User:        node.User.Proto(),
 		ForcedTags:  node.ForcedTags,
 
+		PendingApproval: node.PendingApproval,
+		ExpiryDisabled:  node.ExpiryDisabled,
+
 		// TODO(kradalby): Implement register method enum converter
 		// RegisterMethod: ,
 
@@ -343,6 +376,44 @@ func (node *Node) Proto() *v1.Node {
 	return nodeProto
 }
 
+// Diagnostics returns the network state reported by the node, with its
+// preferred DERP region resolved against derpMap.
+func (node *Node) Diagnostics(derpMap *tailcfg.DERPMap) *v1.NodeDiagnostics {
+	diagnostics := &v1.NodeDiagnostics{
+		Endpoints:         make([]string, len(node.Endpoints)),
+		CapabilityVersion: int32(node.CapabilityVersion),
+	}
+
+	for index, endpoint := range node.Endpoints {
+		diagnostics.Endpoints[index] = endpoint.String()
+	}
+
+	if node.LastMapRequest != nil {
+		diagnostics.LastMapRequest = timestamppb.New(*node.LastMapRequest)
+	}
+
+	if node.Hostinfo == nil {
+		return diagnostics
+	}
+
+	diagnostics.ClientVersion = node.Hostinfo.IPNVersion
+	diagnostics.Os = node.Hostinfo.OS
+	diagnostics.OsVersion = node.Hostinfo.OSVersion
+
+	if netInfo := node.Hostinfo.NetInfo; netInfo != nil {
+		diagnostics.PreferredDerpRegionId = int32(netInfo.PreferredDERP)
+		diagnostics.HardNat = netInfo.MappingVariesByDestIP.EqualBool(true)
+
+		if derpMap != nil {
+			if region, ok := derpMap.Regions[netInfo.PreferredDERP]; ok {
+				diagnostics.PreferredDerp = region.RegionName
+			}
+		}
+	}
+
+	return diagnostics
+}
+
 func (node *Node) GetFQDN(dnsConfig *tailcfg.DNSConfig, baseDomain string) (string, error) {
 	var hostname string
 	if dnsConfig != nil && dnsConfig.Proxied { // MagicDNS
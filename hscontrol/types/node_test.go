@@ -3,9 +3,13 @@ package types
 import (
 	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/key"
 )
@@ -366,3 +370,82 @@ func TestPeerChangeFromMapRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeDiagnostics(t *testing.T) {
+	lastMapRequest := time.Date(2023, time.December, 28, 12, 0, 0, 0, time.UTC)
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1: {RegionID: 1, RegionCode: "nyc", RegionName: "New York City"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		node Node
+		want *v1.NodeDiagnostics
+	}{
+		{
+			name: "no-hostinfo",
+			node: Node{},
+			want: &v1.NodeDiagnostics{
+				Endpoints: []string{},
+			},
+		},
+		{
+			name: "full",
+			node: Node{
+				Endpoints: []netip.AddrPort{
+					netip.MustParseAddrPort("192.0.2.1:41641"),
+					netip.MustParseAddrPort("[2001:db8::1]:41641"),
+				},
+				Hostinfo: &tailcfg.Hostinfo{
+					IPNVersion: "1.56.1",
+					OS:         "linux",
+					OSVersion:  "6.1.0",
+					NetInfo: &tailcfg.NetInfo{
+						PreferredDERP:         1,
+						MappingVariesByDestIP: "true",
+					},
+				},
+				CapabilityVersion: 82,
+				LastMapRequest:    &lastMapRequest,
+			},
+			want: &v1.NodeDiagnostics{
+				Endpoints:             []string{"192.0.2.1:41641", "[2001:db8::1]:41641"},
+				PreferredDerp:         "New York City",
+				PreferredDerpRegionId: 1,
+				ClientVersion:         "1.56.1",
+				Os:                    "linux",
+				OsVersion:             "6.1.0",
+				CapabilityVersion:     82,
+				HardNat:               true,
+				LastMapRequest:        timestamppb.New(lastMapRequest),
+			},
+		},
+		{
+			name: "unknown-derp-region",
+			node: Node{
+				Hostinfo: &tailcfg.Hostinfo{
+					NetInfo: &tailcfg.NetInfo{
+						PreferredDERP: 900,
+					},
+				},
+			},
+			want: &v1.NodeDiagnostics{
+				Endpoints:             []string{},
+				PreferredDerpRegionId: 900,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.node.Diagnostics(derpMap)
+
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Diagnostics unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
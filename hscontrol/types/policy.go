@@ -0,0 +1,29 @@
+package types
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPolicyNotFound         = errors.New("acl policy not found")
+	ErrPolicyUpdateIsDisabled = errors.New(
+		"policy updates through the API are only allowed in database mode",
+	)
+)
+
+const (
+	PolicyModeFile = "file"
+	PolicyModeDB   = "database"
+)
+
+// Policy represents a version of the ACL policy stored in the database.
+// Every update creates a new row, the newest row is the active policy and
+// the older rows make up the history of the policy.
+type Policy struct {
+	gorm.Model
+
+	// Data contains the policy in HuJSON format.
+	Data string
+}
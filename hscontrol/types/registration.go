@@ -0,0 +1,92 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"tailscale.com/types/key"
+)
+
+// PendingRegistration is a node waiting for an administrator or an OIDC
+// login to complete its registration. It is stored in the database so it
+// survives a restart of headscale.
+type PendingRegistration struct {
+	ID uint64 `gorm:"primary_key"`
+
+	// MachineKeyDatabaseField is the string representation of MachineKey
+	// it is _only_ used for reading and writing the key to the
+	// database and should not be used.
+	// Use MachineKey instead.
+	MachineKeyDatabaseField string            `gorm:"column:machine_key;uniqueIndex"`
+	MachineKey              key.MachinePublic `gorm:"-"`
+
+	// NodeDatabaseField is the JSON representation of Node
+	// it is _only_ used for reading and writing the node to the
+	// database and should not be used.
+	// Use Node instead.
+	NodeDatabaseField string `gorm:"column:node"`
+	Node              Node   `gorm:"-"`
+
+	// OIDCState is the state of the OIDC login started for the node,
+	// if any.
+	OIDCState string `gorm:"column:oidc_state;index"`
+
+	// Rejected registrations are kept until they expire to prevent the
+	// node from asking again right away.
+	Rejected bool
+
+	CreatedAt time.Time
+	Expiry    time.Time `gorm:"index"`
+}
+
+func (reg *PendingRegistration) BeforeSave(tx *gorm.DB) error {
+	reg.MachineKeyDatabaseField = reg.MachineKey.String()
+
+	node, err := json.Marshal(reg.Node)
+	if err != nil {
+		return fmt.Errorf("failed to marshal node to store in db: %w", err)
+	}
+	reg.NodeDatabaseField = string(node)
+
+	return nil
+}
+
+func (reg *PendingRegistration) AfterFind(tx *gorm.DB) error {
+	var machineKey key.MachinePublic
+	if err := machineKey.UnmarshalText([]byte(reg.MachineKeyDatabaseField)); err != nil {
+		return fmt.Errorf("failed to unmarshal machine key from db: %w", err)
+	}
+	reg.MachineKey = machineKey
+
+	var node Node
+	if err := json.Unmarshal([]byte(reg.NodeDatabaseField), &node); err != nil {
+		return fmt.Errorf("failed to unmarshal node from db: %w", err)
+	}
+	reg.Node = node
+
+	return nil
+}
+
+func (reg *PendingRegistration) Proto() *v1.PendingRegistration {
+	protoReg := &v1.PendingRegistration{
+		Id:         reg.ID,
+		MachineKey: reg.MachineKey.String(),
+		NodeKey:    reg.Node.NodeKey.String(),
+		Hostname:   reg.Node.Hostname,
+		GivenName:  reg.Node.GivenName,
+		CreatedAt:  timestamppb.New(reg.CreatedAt),
+		Expiry:     timestamppb.New(reg.Expiry),
+		Rejected:   reg.Rejected,
+	}
+
+	// Nodes logging in again after expiring are already owned by a user.
+	if reg.Node.UserID != 0 {
+		protoReg.User = reg.Node.User.Proto()
+	}
+
+	return protoReg
+}